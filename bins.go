@@ -0,0 +1,171 @@
+package bbn
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/mlange-42/bbn/ve"
+	"gopkg.in/yaml.v3"
+)
+
+// Bin returns the index of the bin (i.e. interval outcome) that contains the given value.
+//
+// Bins are half-open intervals [lower, upper), except for the last bin, which includes its upper edge.
+// Returns false if the variable has no bins, or if the value is outside all bins.
+func (v *Variable) Bin(x float64) (int, bool) {
+	if len(v.Bins) < 2 || math.IsNaN(x) {
+		return 0, false
+	}
+	idx := sort.Search(len(v.Bins), func(i int) bool { return v.Bins[i] > x }) - 1
+	if idx < 0 {
+		return 0, false
+	}
+	if idx == len(v.Bins)-1 {
+		if x != v.Bins[idx] {
+			return 0, false
+		}
+		idx--
+	}
+	return idx, true
+}
+
+// Outcome returns the index of the outcome for the given value.
+//
+// Values are matched against outcome names first.
+// For variables with bins, numeric values are mapped to the bin that contains them.
+func (v *Variable) Outcome(value string) (int, bool) {
+	if idx := slices.Index(v.Outcomes, value); idx >= 0 {
+		return idx, true
+	}
+	if len(v.Bins) == 0 {
+		return 0, false
+	}
+	x, err := parseBinEdge(value)
+	if err != nil {
+		return 0, false
+	}
+	return v.Bin(x)
+}
+
+// BinLabels creates outcome names for the intervals given by bin edges, like "10..20".
+func BinLabels(bins []float64) []string {
+	if len(bins) < 2 {
+		return nil
+	}
+	labels := make([]string, len(bins)-1)
+	for i := range labels {
+		labels[i] = formatBinEdge(bins[i]) + ".." + formatBinEdge(bins[i+1])
+	}
+	return labels
+}
+
+// checkBins checks the bin edges of a variable, called from prepareVariables.
+func checkBins(v *Variable) error {
+	if len(v.Bins) == 0 {
+		return nil
+	}
+	if v.NodeType != ve.ChanceNode && v.NodeType != ve.DecisionNode {
+		return newVariableError(v.Name, ErrTableShape, "bins are only supported for chance and decision variables; got %s", v.Name)
+	}
+	if len(v.Bins) != len(v.Outcomes)+1 {
+		return newVariableError(v.Name, ErrTableShape, "variable %s has %d outcomes and requires %d bin edges; got %d", v.Name, len(v.Outcomes), len(v.Outcomes)+1, len(v.Bins))
+	}
+	for i, b := range v.Bins {
+		if math.IsNaN(b) {
+			return newVariableError(v.Name, ErrTableShape, "invalid bin edge for variable %s", v.Name)
+		}
+		if i > 0 && b <= v.Bins[i-1] {
+			return newVariableError(v.Name, ErrTableShape, "bin edges for variable %s must be strictly increasing", v.Name)
+		}
+	}
+	return nil
+}
+
+// binnedValues returns a copy of the given variable values, with numeric values of binned variables
+// replaced by the names of the outcomes they fall into.
+func (n *Network) binnedValues(values map[string]string) (map[string]string, error) {
+	if values == nil {
+		return nil, nil
+	}
+	result := make(map[string]string, len(values))
+	for name, value := range values {
+		result[name] = value
+		v, ok := n.variable(name)
+		if !ok || len(v.Bins) == 0 {
+			continue
+		}
+		idx, ok := v.Outcome(value)
+		if !ok {
+			return nil, newVariableError(name, ErrUnknownOutcome, "value %s for variable %s is neither an outcome nor in any bin", value, name)
+		}
+		result[name] = v.Outcomes[idx]
+	}
+	return result, nil
+}
+
+// binEdge is a bin edge in YAML, supporting "inf" and "-inf" in addition to YAML's ".inf" and "-.inf".
+type binEdge float64
+
+// UnmarshalYAML implements [yaml.Unmarshaler].
+func (b *binEdge) UnmarshalYAML(node *yaml.Node) error {
+	x, err := parseBinEdge(node.Value)
+	if err != nil {
+		return fmt.Errorf("invalid bin edge '%s' in line %d", node.Value, node.Line)
+	}
+	*b = binEdge(x)
+	return nil
+}
+
+// MarshalYAML implements [yaml.Marshaler].
+func (b binEdge) MarshalYAML() (interface{}, error) {
+	x := float64(b)
+	if math.IsInf(x, 0) {
+		return formatBinEdge(x), nil
+	}
+	return x, nil
+}
+
+func toBinEdges(bins []float64) []binEdge {
+	if len(bins) == 0 {
+		return nil
+	}
+	edges := make([]binEdge, len(bins))
+	for i, b := range bins {
+		edges[i] = binEdge(b)
+	}
+	return edges
+}
+
+func fromBinEdges(edges []binEdge) []float64 {
+	if len(edges) == 0 {
+		return nil
+	}
+	bins := make([]float64, len(edges))
+	for i, b := range edges {
+		bins[i] = float64(b)
+	}
+	return bins
+}
+
+func parseBinEdge(s string) (float64, error) {
+	s = strings.TrimSpace(s)
+	s = strings.Replace(s, ".inf", "inf", 1)
+	s = strings.Replace(s, ".Inf", "inf", 1)
+	s = strings.Replace(s, ".INF", "inf", 1)
+	return strconv.ParseFloat(s, 64)
+}
+
+func formatBinEdge(x float64) string {
+	switch {
+	case math.IsInf(x, 1):
+		return "inf"
+	case math.IsInf(x, -1):
+		return "-inf"
+	default:
+		return strconv.FormatFloat(x, 'g', -1, 64)
+	}
+}
@@ -0,0 +1,124 @@
+package bbn_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/mlange-42/bbn"
+	"github.com/mlange-42/bbn/ve"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVariableBin(t *testing.T) {
+	v := bbn.Variable{
+		Name:     "X",
+		Outcomes: []string{"low", "mid", "high"},
+		Bins:     []float64{0, 10, 20, 30},
+	}
+
+	tests := []struct {
+		value float64
+		bin   int
+		ok    bool
+	}{
+		{-1, 0, false},
+		{0, 0, true},
+		{9.99, 0, true},
+		{10, 1, true},
+		{25, 2, true},
+		{30, 2, true},
+		{30.1, 0, false},
+		{math.NaN(), 0, false},
+	}
+	for _, tt := range tests {
+		bin, ok := v.Bin(tt.value)
+		assert.Equal(t, tt.ok, ok, "value %f", tt.value)
+		assert.Equal(t, tt.bin, bin, "value %f", tt.value)
+	}
+
+	idx, ok := v.Outcome("mid")
+	assert.True(t, ok)
+	assert.Equal(t, 1, idx)
+	idx, ok = v.Outcome("12.5")
+	assert.True(t, ok)
+	assert.Equal(t, 1, idx)
+	_, ok = v.Outcome("abc")
+	assert.False(t, ok)
+	_, ok = v.Outcome("-5")
+	assert.False(t, ok)
+
+	v = bbn.Variable{Name: "Y", Outcomes: []string{"a", "b"}}
+	_, ok = v.Outcome("1")
+	assert.False(t, ok)
+}
+
+func TestBinLabels(t *testing.T) {
+	assert.Equal(t,
+		[]string{"-inf..0", "0..2.5", "2.5..inf"},
+		bbn.BinLabels([]float64{math.Inf(-1), 0, 2.5, math.Inf(1)}),
+	)
+	assert.Nil(t, bbn.BinLabels([]float64{1}))
+}
+
+func TestNetworkBins(t *testing.T) {
+	net, err := bbn.FromFile("_examples/bbn/weather-untrained.yml")
+	assert.Nil(t, err)
+
+	vars := net.Variables()
+	assert.Equal(t, []string{"cold", "mild", "hot"}, vars[0].Outcomes)
+	assert.Equal(t, []float64{math.Inf(-1), 9.5, 30.05, math.Inf(1)}, vars[0].Bins)
+	assert.Equal(t, []string{"-inf..86.5", "86.5..inf"}, vars[1].Outcomes)
+
+	result, _, err := net.SolveQuery(map[string]string{"Temperature": "18.5", "Humidity": "90"}, []string{"Play"}, false)
+	assert.Nil(t, err)
+	assert.Equal(t, []float64{0.5, 0.5}, result["Play"])
+
+	ev, err := net.ToEvidence("Temperature", "-3")
+	assert.Nil(t, err)
+	assert.Equal(t, []float64{1, 0, 0}, ev)
+	ev, err = net.ToEvidence("Humidity", "90")
+	assert.Nil(t, err)
+	assert.Equal(t, []float64{0, 1}, ev)
+
+	result, _, err = net.SolveIntervention(map[string]string{"Temperature": "35"}, map[string]string{"Temperature": "hot"}, []string{"Play"}, false)
+	assert.Nil(t, err)
+	assert.Equal(t, []float64{0.5, 0.5}, result["Play"])
+
+	_, _, err = net.SolveIntervention(map[string]string{"Temperature": "35"}, map[string]string{"Temperature": "5"}, []string{"Play"}, false)
+	assert.NotNil(t, err)
+
+	_, _, err = net.SolveQuery(map[string]string{"Temperature": "abc"}, []string{"Play"}, false)
+	assert.ErrorIs(t, err, bbn.ErrUnknownOutcome)
+
+	yml, err := bbn.ToYAML(net)
+	assert.Nil(t, err)
+	net2, err := bbn.FromYAML(yml)
+	assert.Nil(t, err)
+	assert.Equal(t, vars[0].Bins, net2.Variables()[0].Bins)
+	assert.Equal(t, vars[1].Outcomes, net2.Variables()[1].Outcomes)
+}
+
+func TestNetworkBinsInvalid(t *testing.T) {
+	tests := [][]float64{
+		{0, 1},
+		{0, 1, 1},
+		{0, math.NaN(), 2},
+	}
+	for _, bins := range tests {
+		_, err := bbn.New("Test", "", []bbn.Variable{
+			{Name: "X", NodeType: ve.ChanceNode, Outcomes: []string{"a", "b"}, Bins: bins},
+		}, []bbn.Factor{
+			{For: "X", Table: []float64{0.5, 0.5}},
+		})
+		assert.ErrorIs(t, err, bbn.ErrTableShape, "bins %v", bins)
+	}
+
+	_, err := bbn.FromYAML([]byte(`
+name: Test
+variables:
+- variable: X
+  bins: [0, abc, 1]
+  table: [[1, 1]]
+`))
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,366 @@
+package bbn
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/mlange-42/bbn/equation"
+	"github.com/mlange-42/bbn/logic"
+	"github.com/mlange-42/bbn/noisy"
+	"github.com/mlange-42/bbn/ordinal"
+	"github.com/mlange-42/bbn/utility"
+	"github.com/mlange-42/bbn/ve"
+)
+
+// Builder for creating a [Network] step by step.
+//
+// Methods can be chained. The first error is recorded and returned by [Builder.Build],
+// all further calls are ignored after an error.
+type Builder struct {
+	name      string
+	info      string
+	variables []Variable
+	factors   []Factor
+	logic     map[string]logic.Factor
+	noisy     map[string]noisy.Model
+	exprs     map[string]string
+	functions map[string]ordinal.Function
+	equations map[string]string
+	utilities map[string]utility.Function
+	limid     bool
+	err       error
+}
+
+// NewBuilder creates a new [Builder] for a network with the given name and description.
+func NewBuilder(name string, info string) *Builder {
+	return &Builder{
+		name:      name,
+		info:      info,
+		logic:     map[string]logic.Factor{},
+		noisy:     map[string]noisy.Model{},
+		exprs:     map[string]string{},
+		functions: map[string]ordinal.Function{},
+		equations: map[string]string{},
+		utilities: map[string]utility.Function{},
+	}
+}
+
+// AddChance adds a chance variable with the given outcomes.
+func (b *Builder) AddChance(name string, outcomes ...string) *Builder {
+	return b.addVariable(name, ve.ChanceNode, outcomes)
+}
+
+// AddDecision adds a decision variable with the given outcomes.
+func (b *Builder) AddDecision(name string, outcomes ...string) *Builder {
+	return b.addVariable(name, ve.DecisionNode, outcomes)
+}
+
+// AddContinuous adds a continuous variable with a linear Gaussian distribution.
+//
+// See [Factor] for the table layout of continuous variables.
+func (b *Builder) AddContinuous(name string) *Builder {
+	return b.addVariable(name, ve.ContinuousNode, nil)
+}
+
+// AddUtility adds a utility variable.
+//
+// Outcomes are only required for a total utility node, i.e. a utility node with utility parents.
+// Defaults to a single outcome "utility".
+func (b *Builder) AddUtility(name string, outcomes ...string) *Builder {
+	if len(outcomes) == 0 {
+		outcomes = []string{"utility"}
+	}
+	return b.addVariable(name, ve.UtilityNode, outcomes)
+}
+
+// AddEdge adds an edge from a parent to a child variable.
+//
+// Parents are appended in the order of calls to AddEdge,
+// which determines the row order of the child's table.
+func (b *Builder) AddEdge(from, to string) *Builder {
+	if b.err != nil {
+		return b
+	}
+	if _, ok := b.factor(from); !ok {
+		b.err = newVariableError(from, ErrUnknownVariable, "variable %s not found", from)
+		return b
+	}
+	f, ok := b.factor(to)
+	if !ok {
+		b.err = newVariableError(to, ErrUnknownVariable, "variable %s not found", to)
+		return b
+	}
+	if slices.Contains(f.Given, from) {
+		b.err = fmt.Errorf("edge from %s to %s already exists", from, to)
+		return b
+	}
+	f.Given = append(f.Given, from)
+	return b
+}
+
+// SetTable sets the table of a variable.
+func (b *Builder) SetTable(name string, table []float64) *Builder {
+	if b.err != nil {
+		return b
+	}
+	f, ok := b.factor(name)
+	if !ok {
+		b.err = newVariableError(name, ErrUnknownVariable, "variable %s not found", name)
+		return b
+	}
+	b.clearDefinition(name)
+	f.Table = table
+	return b
+}
+
+// SetLogic sets a logic factor for a variable.
+//
+// The variable's table is generated from the factor when calling [Builder.Build],
+// based on the number of parents at that time.
+func (b *Builder) SetLogic(name string, factor logic.Factor) *Builder {
+	if b.err != nil {
+		return b
+	}
+	if _, ok := b.factor(name); !ok {
+		b.err = newVariableError(name, ErrUnknownVariable, "variable %s not found", name)
+		return b
+	}
+	b.clearDefinition(name)
+	b.logic[name] = factor
+	return b
+}
+
+// SetExpression sets a boolean expression over parents for a variable with two outcomes.
+// See [logic.Expression] for the syntax.
+//
+// The expression is parsed immediately.
+// The variable's table is generated when calling [Builder.Build], based on the parents at that time.
+func (b *Builder) SetExpression(name string, expr string) *Builder {
+	if b.err != nil {
+		return b
+	}
+	if _, ok := b.factor(name); !ok {
+		b.err = newVariableError(name, ErrUnknownVariable, "variable %s not found", name)
+		return b
+	}
+	if _, err := logic.ParseExpression(expr); err != nil {
+		b.err = causedVariableError(name, ErrDefinition, err, "logic expression for %s: %s", name, err.Error())
+		return b
+	}
+	b.clearDefinition(name)
+	b.exprs[name] = expr
+	return b
+}
+
+// SetNoisy sets a canonical model, like noisy-OR, for a chance variable.
+//
+// The variable's table is generated from the model when calling [Builder.Build],
+// based on the parents at that time.
+func (b *Builder) SetNoisy(name string, model noisy.Model) *Builder {
+	if b.err != nil {
+		return b
+	}
+	if _, ok := b.factor(name); !ok {
+		b.err = newVariableError(name, ErrUnknownVariable, "variable %s not found", name)
+		return b
+	}
+	b.clearDefinition(name)
+	b.noisy[name] = model
+	return b
+}
+
+// SetFunction sets a deterministic ordinal function, like sum or max, for a chance variable.
+//
+// The variable's table is generated from the function when calling [Builder.Build],
+// based on the parents at that time.
+func (b *Builder) SetFunction(name string, fn ordinal.Function) *Builder {
+	if b.err != nil {
+		return b
+	}
+	if _, ok := b.factor(name); !ok {
+		b.err = newVariableError(name, ErrUnknownVariable, "variable %s not found", name)
+		return b
+	}
+	b.clearDefinition(name)
+	b.functions[name] = fn
+	return b
+}
+
+// SetEquation sets an equation over parents for a chance variable.
+// See [equation.Equation] for the syntax.
+//
+// The equation is parsed immediately.
+// The variable's table is generated when calling [Builder.Build], based on the parents at that time.
+func (b *Builder) SetEquation(name string, expr string) *Builder {
+	if b.err != nil {
+		return b
+	}
+	if _, ok := b.factor(name); !ok {
+		b.err = newVariableError(name, ErrUnknownVariable, "variable %s not found", name)
+		return b
+	}
+	if _, err := equation.Parse(expr); err != nil {
+		b.err = causedVariableError(name, ErrDefinition, err, "equation for %s: %s", name, err.Error())
+		return b
+	}
+	b.clearDefinition(name)
+	b.equations[name] = expr
+	return b
+}
+
+// SetUtility sets a function for combining utilities, like exponential utility, for a total utility variable.
+//
+// The table of the variable provides the weights of its utility parents.
+func (b *Builder) SetUtility(name string, fn utility.Function) *Builder {
+	if b.err != nil {
+		return b
+	}
+	if _, ok := b.factor(name); !ok {
+		b.err = newVariableError(name, ErrUnknownVariable, "variable %s not found", name)
+		return b
+	}
+	b.utilities[name] = fn
+	return b
+}
+
+// SetAllowed sets the allowed outcomes of a decision variable, in the layout of a table.
+//
+// Entries are the outcomes of the decision, for each combination of outcomes of its parents.
+// When solving policies, only allowed outcomes are considered.
+func (b *Builder) SetAllowed(name string, allowed []bool) *Builder {
+	if b.err != nil {
+		return b
+	}
+	f, ok := b.factor(name)
+	if !ok {
+		b.err = newVariableError(name, ErrUnknownVariable, "variable %s not found", name)
+		return b
+	}
+	f.Allowed = allowed
+	return b
+}
+
+// SetLIMID sets whether the network is a limited-memory influence diagram (LIMID).
+// See [Network.SetLIMID].
+func (b *Builder) SetLIMID(limid bool) *Builder {
+	b.limid = limid
+	return b
+}
+
+// Build creates the [Network].
+//
+// Returns the first error that occurred while building,
+// or an error if the network structure is invalid.
+func (b *Builder) Build() (*Network, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+	variables := slices.Clone(b.variables)
+	factors := make([]Factor, 0, len(b.factors))
+	for _, f := range b.factors {
+		if len(f.Given) == 0 && f.Table == nil && f.Allowed == nil && !b.hasDefinition(f.For) {
+			continue
+		}
+		table, err := b.table(&f)
+		if err != nil {
+			return nil, err
+		}
+		f.Given = slices.Clone(f.Given)
+		f.Table = table
+		f.Allowed = slices.Clone(f.Allowed)
+		f.Noisy = b.noisy[f.For]
+		f.Utility = b.utilities[f.For]
+		factors = append(factors, f)
+	}
+
+	net := &Network{
+		name:      b.name,
+		info:      b.info,
+		variables: variables,
+		factors:   factors,
+		policies:  map[string]ve.Factor{},
+		limid:     b.limid,
+	}
+	if err := net.checkStructure(); err != nil {
+		return nil, err
+	}
+	return net, nil
+}
+
+// hasDefinition checks whether the variable has a logic factor, an expression, a canonical model,
+// an ordinal function or an equation.
+func (b *Builder) hasDefinition(name string) bool {
+	_, isLogic := b.logic[name]
+	_, isExpr := b.exprs[name]
+	_, isNoisy := b.noisy[name]
+	_, isFunction := b.functions[name]
+	_, isEquation := b.equations[name]
+	return isLogic || isExpr || isNoisy || isFunction || isEquation
+}
+
+// table creates the table of a factor, from a logic factor, expression, ordinal function or equation if present.
+func (b *Builder) table(f *Factor) ([]float64, error) {
+	if l, ok := b.logic[f.For]; ok {
+		table, err := l.Table(len(f.Given))
+		if err != nil {
+			return nil, causedVariableError(f.For, ErrDefinition, err, "logic node %s: %s", f.For, err.Error())
+		}
+		return table, nil
+	}
+	idx := slices.IndexFunc(b.variables, func(v Variable) bool { return v.Name == f.For })
+	if eq, ok := b.equations[f.For]; ok {
+		return equationTable(&b.variables[idx], eq, f.Given, b.variables)
+	}
+	expr, isExpr := b.exprs[f.For]
+	fn, isFunction := b.functions[f.For]
+	if !isExpr && !isFunction {
+		return slices.Clone(f.Table), nil
+	}
+	outcomes := make(map[string][]string, len(b.variables))
+	for _, v := range b.variables {
+		outcomes[v.Name] = v.Outcomes
+	}
+	if isFunction {
+		return functionTable(&b.variables[idx], fn, f.Given, outcomes)
+	}
+	return expressionTable(&b.variables[idx], expr, f.Given, outcomes)
+}
+
+// clearDefinition removes the table and any generated definition of a variable,
+// before a setter assigns a new one.
+func (b *Builder) clearDefinition(name string) {
+	if f, ok := b.factor(name); ok {
+		f.Table = nil
+	}
+	delete(b.logic, name)
+	delete(b.noisy, name)
+	delete(b.exprs, name)
+	delete(b.functions, name)
+	delete(b.equations, name)
+}
+
+func (b *Builder) addVariable(name string, tp ve.NodeType, outcomes []string) *Builder {
+	if b.err != nil {
+		return b
+	}
+	if _, ok := b.factor(name); ok {
+		b.err = newVariableError(name, ve.ErrDuplicateVariable, "duplicate variable name %s", name)
+		return b
+	}
+	b.variables = append(b.variables, Variable{
+		Name:     name,
+		NodeType: tp,
+		Outcomes: slices.Clone(outcomes),
+	})
+	b.factors = append(b.factors, Factor{For: name})
+	return b
+}
+
+// factor returns the factor for the variable with the given name.
+func (b *Builder) factor(name string) (*Factor, bool) {
+	idx := slices.IndexFunc(b.factors, func(f Factor) bool { return f.For == name })
+	if idx < 0 {
+		return nil, false
+	}
+	return &b.factors[idx], true
+}
@@ -0,0 +1,280 @@
+package bbn_test
+
+import (
+	"testing"
+
+	"github.com/mlange-42/bbn"
+	"github.com/mlange-42/bbn/equation"
+	"github.com/mlange-42/bbn/logic"
+	"github.com/mlange-42/bbn/ordinal"
+	"github.com/mlange-42/bbn/utility"
+	"github.com/mlange-42/bbn/ve"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuilder(t *testing.T) {
+	net, err := bbn.NewBuilder("Sprinkler", "").
+		AddChance("Rain", "yes", "no").
+		AddChance("Sprinkler", "yes", "no").
+		AddChance("GrassWet", "yes", "no").
+		AddEdge("Rain", "Sprinkler").
+		AddEdge("Rain", "GrassWet").
+		AddEdge("Sprinkler", "GrassWet").
+		SetTable("Rain", []float64{0.2, 0.8}).
+		SetTable("Sprinkler", []float64{0.01, 0.99, 0.2, 0.8}).
+		SetTable("GrassWet", []float64{0.99, 0.01, 0.8, 0.2, 0.9, 0.1, 0, 1}).
+		Build()
+	assert.Nil(t, err)
+	assert.Equal(t, "Sprinkler", net.Name())
+	assert.Equal(t, 3, len(net.Variables()))
+
+	result, _, err := net.SolveQuery(map[string]string{"GrassWet": "yes"}, []string{"Rain"}, false)
+	assert.Nil(t, err)
+	assert.InDelta(t, 0.5269, result["Rain"][0], 0.0001)
+}
+
+func TestBuilderDecision(t *testing.T) {
+	net, err := bbn.NewBuilder("Umbrella", "").
+		AddChance("Rain", "yes", "no").
+		AddDecision("Umbrella", "yes", "no").
+		AddUtility("Wet").
+		AddUtility("Comfort").
+		AddUtility("Total", "Wet", "Comfort").
+		AddEdge("Rain", "Wet").
+		AddEdge("Umbrella", "Wet").
+		AddEdge("Umbrella", "Comfort").
+		AddEdge("Wet", "Total").
+		AddEdge("Comfort", "Total").
+		SetTable("Rain", []float64{0.3, 0.7}).
+		SetTable("Wet", []float64{0, -100, 0, 0}).
+		SetTable("Comfort", []float64{-5, 0}).
+		SetTable("Total", []float64{1, 1}).
+		Build()
+	assert.Nil(t, err)
+	assert.Equal(t, 4, net.TotalUtilityIndex())
+
+	policies, err := net.SolvePolicies(false)
+	assert.Nil(t, err)
+	assert.Equal(t, []float64{1, 0}, policies["Umbrella"].Table)
+}
+
+func TestBuilderUtility(t *testing.T) {
+	build := func(fn utility.Function) (*bbn.Network, error) {
+		return bbn.NewBuilder("Bet", "").
+			AddChance("Coin", "heads", "tails").
+			AddDecision("Bet", "yes", "no").
+			AddUtility("Win").
+			AddUtility("Total", "Win").
+			AddEdge("Coin", "Win").
+			AddEdge("Bet", "Win").
+			AddEdge("Win", "Total").
+			SetTable("Coin", []float64{0.5, 0.5}).
+			SetTable("Win", []float64{120, 0, -100, 0}).
+			SetTable("Total", []float64{1}).
+			SetUtility("Total", fn).
+			Build()
+	}
+
+	net, err := build(nil)
+	assert.Nil(t, err)
+	policies, err := net.SolvePolicies(false)
+	assert.Nil(t, err)
+	assert.Equal(t, []float64{1, 0}, policies["Bet"].Table)
+
+	net, err = build(&utility.Exponential{RiskTolerance: 100})
+	assert.Nil(t, err)
+	policies, err = net.SolvePolicies(false)
+	assert.Nil(t, err)
+	assert.Equal(t, []float64{0, 1}, policies["Bet"].Table)
+
+	net, err = build(&utility.Exponential{RiskTolerance: -100})
+	assert.Nil(t, err)
+	policies, err = net.SolvePolicies(false)
+	assert.Nil(t, err)
+	assert.Equal(t, []float64{1, 0}, policies["Bet"].Table)
+
+	net, err = build(&utility.Piecewise{X: []float64{-100, 0, 120}, Y: []float64{-300, 0, 120}})
+	assert.Nil(t, err)
+	policies, err = net.SolvePolicies(false)
+	assert.Nil(t, err)
+	assert.Equal(t, []float64{0, 1}, policies["Bet"].Table)
+
+	_, err = build(&utility.Exponential{})
+	assert.ErrorIs(t, err, bbn.ErrDefinition)
+
+	_, err = bbn.NewBuilder("Bet", "").
+		AddChance("Coin", "heads", "tails").
+		AddUtility("Win").
+		AddEdge("Coin", "Win").
+		SetTable("Coin", []float64{0.5, 0.5}).
+		SetTable("Win", []float64{120, -100}).
+		SetUtility("Win", &utility.Exponential{RiskTolerance: 1}).
+		Build()
+	assert.ErrorIs(t, err, bbn.ErrUnsupported)
+
+	net, err = bbn.NewBuilder("Bet", "").
+		AddChance("Coin", "heads", "tails").
+		AddUtility("Win").
+		AddUtility("Total", "Win").
+		AddEdge("Coin", "Win").
+		AddEdge("Win", "Total").
+		SetTable("Coin", []float64{0.5, 0.5}).
+		SetTable("Win", []float64{120, -100}).
+		SetTable("Total", []float64{1}).
+		SetUtility("Total", &customUtility{}).
+		Build()
+	assert.Nil(t, err)
+	_, err = bbn.ToYAML(net)
+	assert.ErrorIs(t, err, bbn.ErrUnsupported)
+
+	_, err = bbn.NewBuilder("Bet", "").SetUtility("Total", &utility.Exponential{RiskTolerance: 1}).Build()
+	assert.ErrorIs(t, err, bbn.ErrUnknownVariable)
+}
+
+type customUtility struct {
+	utility.Exponential
+}
+
+func (f *customUtility) Validate() error { return nil }
+
+func TestBuilderLogic(t *testing.T) {
+	net, err := bbn.NewBuilder("Logic", "").
+		AddChance("A", "yes", "no").
+		AddChance("B", "yes", "no").
+		AddChance("C", "yes", "no").
+		SetLogic("C", logic.And()).
+		AddEdge("A", "C").
+		AddEdge("B", "C").
+		SetTable("A", []float64{0.5, 0.5}).
+		SetTable("B", []float64{0.5, 0.5}).
+		Build()
+	assert.Nil(t, err)
+	assert.Equal(t, []float64{1, 0, 0, 1, 0, 1, 0, 1}, net.Variables()[2].Factor.Table)
+
+	_, err = bbn.NewBuilder("Logic", "").
+		AddChance("A", "yes", "no").
+		AddChance("C", "yes", "no").
+		SetLogic("C", logic.And()).
+		AddEdge("A", "C").
+		SetTable("A", []float64{0.5, 0.5}).
+		Build()
+	assert.ErrorIs(t, err, bbn.ErrDefinition)
+
+	net, err = bbn.NewBuilder("Logic", "").
+		AddChance("A", "yes", "no").
+		AddChance("C", "yes", "no").
+		AddEdge("A", "C").
+		SetTable("A", []float64{0.5, 0.5}).
+		SetLogic("C", logic.And()).
+		SetExpression("C", "not A").
+		SetTable("C", []float64{0.2, 0.8, 0.6, 0.4}).
+		Build()
+	assert.Nil(t, err)
+	assert.Equal(t, []float64{0.2, 0.8, 0.6, 0.4}, net.Variables()[1].Factor.Table)
+}
+
+func TestBuilderErrors(t *testing.T) {
+	_, err := bbn.NewBuilder("Test", "").
+		AddChance("A", "yes", "no").
+		AddChance("A", "yes", "no").
+		AddChance("B", "yes", "no").
+		Build()
+	assert.ErrorIs(t, err, ve.ErrDuplicateVariable)
+
+	_, err = bbn.NewBuilder("Test", "").
+		AddChance("A", "yes", "no").
+		AddEdge("B", "A").
+		Build()
+	assert.ErrorIs(t, err, bbn.ErrUnknownVariable)
+
+	_, err = bbn.NewBuilder("Test", "").
+		AddChance("A", "yes", "no").
+		AddChance("B", "yes", "no").
+		AddEdge("A", "B").
+		AddEdge("B", "A").
+		Build()
+	assert.ErrorIs(t, err, bbn.ErrCycle)
+
+	_, err = bbn.NewBuilder("Test", "").
+		AddChance("A", "yes", "no").
+		SetTable("B", []float64{0.5, 0.5}).
+		Build()
+	assert.ErrorIs(t, err, bbn.ErrUnknownVariable)
+}
+
+func TestBuilderExpression(t *testing.T) {
+	net, err := bbn.NewBuilder("Test", "").
+		AddChance("Weather", "sunny", "cloudy", "rainy").
+		AddChance("Day Off", "yes", "no").
+		AddChance("Hike", "yes", "no").
+		AddEdge("Weather", "Hike").
+		AddEdge("Day Off", "Hike").
+		SetTable("Weather", []float64{0.5, 0.3, 0.2}).
+		SetTable("Day Off", []float64{0.3, 0.7}).
+		SetExpression("Hike", "Weather != rainy and 'Day Off'").
+		Build()
+	assert.Nil(t, err)
+
+	result, _, err := net.SolveQuery(nil, []string{"Hike"}, false)
+	assert.Nil(t, err)
+	assert.InDeltaSlice(t, []float64{0.8 * 0.3, 1 - 0.8*0.3}, result["Hike"], 1e-9)
+
+	_, err = bbn.NewBuilder("Test", "").
+		AddChance("A", "yes", "no").
+		SetExpression("A", "A and").
+		Build()
+	var syntaxErr *logic.SyntaxError
+	assert.ErrorAs(t, err, &syntaxErr)
+	assert.ErrorIs(t, err, bbn.ErrDefinition)
+}
+
+func TestBuilderFunction(t *testing.T) {
+	levels := []string{"low", "medium", "high"}
+	net, err := bbn.NewBuilder("Test", "").
+		AddChance("Design", levels...).
+		AddChance("Material", levels...).
+		AddChance("Quality", levels...).
+		AddEdge("Design", "Quality").
+		AddEdge("Material", "Quality").
+		SetTable("Design", []float64{0.2, 0.3, 0.5}).
+		SetTable("Material", []float64{0.1, 0.6, 0.3}).
+		SetFunction("Quality", &ordinal.Min{}).
+		Build()
+	assert.Nil(t, err)
+
+	result, _, err := net.SolveQuery(nil, []string{"Quality"}, false)
+	assert.Nil(t, err)
+	assert.InDeltaSlice(t, []float64{1 - 0.8*0.9, 0.8*0.9 - 0.5*0.3, 0.5 * 0.3}, result["Quality"], 1e-9)
+
+	_, err = bbn.NewBuilder("Test", "").
+		AddChance("A", levels...).
+		SetFunction("A", &ordinal.Max{}).
+		Build()
+	assert.ErrorIs(t, err, bbn.ErrTableShape)
+}
+
+func TestBuilderEquation(t *testing.T) {
+	net, err := bbn.NewBuilder("Test", "").
+		AddChance("Trials", "2", "4").
+		AddChance("Successes", "0", "1", "2", "3", "4").
+		AddEdge("Trials", "Successes").
+		SetTable("Trials", []float64{1, 0}).
+		SetEquation("Successes", "binomial(Trials, 0.5)").
+		Build()
+	assert.Nil(t, err)
+
+	result, _, err := net.SolveQuery(nil, []string{"Successes"}, false)
+	assert.Nil(t, err)
+	assert.InDeltaSlice(t, []float64{0.25, 0.5, 0.25, 0, 0}, result["Successes"], 1e-9)
+
+	_, err = bbn.NewBuilder("Test", "").
+		AddChance("A", "yes", "no").
+		SetEquation("A", "0.5 +").
+		Build()
+	var syntaxErr *equation.SyntaxError
+	assert.ErrorAs(t, err, &syntaxErr)
+	assert.ErrorIs(t, err, bbn.ErrDefinition)
+	var vErr *bbn.VariableError
+	assert.ErrorAs(t, err, &vErr)
+	assert.Equal(t, bbn.ErrDefinition, vErr.Err)
+}
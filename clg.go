@@ -0,0 +1,424 @@
+package bbn
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+
+	"github.com/mlange-42/bbn/ve"
+)
+
+// maxConfigurations is the maximum number of configurations of discrete parents of continuous variables.
+const maxConfigurations = 1 << 20
+
+// Gaussian is the posterior distribution of a continuous variable, given by its first two moments.
+//
+// For conditional linear Gaussian networks with discrete parents of continuous variables,
+// posteriors are mixtures of Gaussians. Mean and variance are the exact moments of the mixture.
+type Gaussian struct {
+	Mean     float64 // Mean of the distribution.
+	Variance float64 // Variance of the distribution.
+}
+
+// SolveContinuous solves the posterior distributions of continuous variables.
+//
+// Continuous evidence is given as a string representation of a number, e.g. "12.5".
+// Inference is exact for conditional linear Gaussian (CLG) networks.
+// Decision policies from [Network.SolvePolicies] are used.
+// Interventions in do are applied to discrete variables, like in [Network.SolveIntervention].
+//
+// Returns mean and variance for each query variable, by variable name.
+func (n *Network) SolveContinuous(evidence map[string]string, do map[string]string, query []string) (map[string]Gaussian, error) {
+	if err := n.checkInterventions(do, evidence); err != nil {
+		return nil, err
+	}
+	discrete, continuous, err := n.splitEvidence(evidence)
+	if err != nil {
+		return nil, err
+	}
+	model, err := n.clgModel()
+	if err != nil {
+		return nil, err
+	}
+	queryIdx := make([]int, len(query))
+	for i, q := range query {
+		idx, err := n.variableIdx(q)
+		if err != nil {
+			return nil, err
+		}
+		pos, ok := model.position[idx]
+		if !ok {
+			return nil, newVariableError(q, ErrUnsupported, "query variable %s is not continuous", q)
+		}
+		queryIdx[i] = pos
+	}
+
+	weights, err := n.configurationWeights(model, discrete, evidence, do)
+	if err != nil {
+		return nil, err
+	}
+
+	moments := make([][2]float64, len(query))
+	err = model.forEachConfiguration(func(c int, config []int) error {
+		if weights[c] == 0 {
+			return nil
+		}
+		g, err := n.conditionalGaussian(model, config, continuous)
+		if err != nil {
+			return err
+		}
+		for i, pos := range queryIdx {
+			m, v := g.mean[pos], g.cov[pos][pos]
+			moments[i][0] += weights[c] * m
+			moments[i][1] += weights[c] * (v + m*m)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]Gaussian, len(query))
+	for i, q := range query {
+		mean := moments[i][0]
+		result[q] = Gaussian{Mean: mean, Variance: math.Max(moments[i][1]-mean*mean, 0)}
+	}
+	return result, nil
+}
+
+// configurationWeights calculates posterior probabilities for all configurations of discrete parents of continuous variables,
+// under the given interventions.
+func (n *Network) configurationWeights(model *clgModel, discrete map[string]string, evidence map[string]string, do map[string]string) ([]float64, error) {
+	free := []string{}
+	for _, idx := range model.discrete {
+		name := n.variables[idx].Name
+		if _, ok := discrete[name]; !ok {
+			free = append(free, name)
+		}
+	}
+
+	f, err := n.solve(evidence, do, free, false, "", false)
+	if err != nil {
+		return nil, err
+	}
+	if len(free) > 0 {
+		rearranged, err := n.TryRearrange(f, free)
+		if err != nil {
+			return nil, err
+		}
+		f = &rearranged
+	}
+	norm := n.Normalize(f)
+	data := norm.Data()
+
+	weights := make([]float64, model.configurations)
+	err = model.forEachConfiguration(func(c int, config []int) error {
+		index := 0
+		for i, idx := range model.discrete {
+			v := &n.variables[idx]
+			if value, ok := discrete[v.Name]; ok {
+				if v.Outcomes[config[i]] != value {
+					return nil
+				}
+				continue
+			}
+			index = index*len(v.Outcomes) + config[i]
+		}
+		weights[c] = data[index]
+		return nil
+	})
+	return weights, err
+}
+
+// isContinuous checks whether the variable with the given name exists and is continuous.
+func (n *Network) isContinuous(name string) bool {
+	v, ok := n.variable(name)
+	return ok && v.NodeType == ve.ContinuousNode
+}
+
+// splitEvidence splits evidence into discrete evidence and (parsed) continuous evidence.
+// Numeric values of binned variables are replaced by outcome names.
+func (n *Network) splitEvidence(evidence map[string]string) (map[string]string, map[string]float64, error) {
+	discrete := make(map[string]string, len(evidence))
+	continuous := map[string]float64{}
+	for name, value := range evidence {
+		v, ok := n.variable(name)
+		if !ok || v.NodeType != ve.ContinuousNode {
+			discrete[name] = value
+			continue
+		}
+		x, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil, nil, newVariableError(name, ErrUnknownOutcome, "invalid value %s for continuous evidence variable %s", value, name)
+		}
+		continuous[name] = x
+	}
+	discrete, err := n.binnedValues(discrete)
+	if err != nil {
+		return nil, nil, err
+	}
+	return discrete, continuous, nil
+}
+
+// clgModel describes the continuous part of a network.
+type clgModel struct {
+	nodes          []int       // Indices of continuous variables, in topological order.
+	position       map[int]int // Position of continuous variables in nodes, by variable index.
+	discrete       []int       // Indices of discrete parents of continuous variables.
+	outcomes       []int       // Number of outcomes of discrete parents.
+	configurations int         // Number of configurations of discrete parents.
+}
+
+// clgModel creates the model for the continuous part of the network.
+func (n *Network) clgModel() (*clgModel, error) {
+	model := clgModel{position: map[int]int{}, configurations: 1}
+	for _, i := range n.topologicalIndices() {
+		if n.variables[i].NodeType != ve.ContinuousNode {
+			continue
+		}
+		model.position[i] = len(model.nodes)
+		model.nodes = append(model.nodes, i)
+		for _, p := range n.parentIndices(i) {
+			if n.variables[p].NodeType == ve.ContinuousNode || slices.Contains(model.discrete, p) {
+				continue
+			}
+			model.discrete = append(model.discrete, p)
+			model.outcomes = append(model.outcomes, len(n.variables[p].Outcomes))
+			model.configurations *= len(n.variables[p].Outcomes)
+			if model.configurations > maxConfigurations {
+				return nil, fmt.Errorf("too many configurations of discrete parents of continuous variables; maximum is %d", maxConfigurations)
+			}
+		}
+	}
+	return &model, nil
+}
+
+// forEachConfiguration calls fn for each configuration of the discrete parents,
+// with the last parent varying fastest.
+func (m *clgModel) forEachConfiguration(fn func(c int, config []int) error) error {
+	config := make([]int, len(m.discrete))
+	for c := 0; c < m.configurations; c++ {
+		rem := c
+		for i := len(config) - 1; i >= 0; i-- {
+			config[i] = rem % m.outcomes[i]
+			rem /= m.outcomes[i]
+		}
+		if err := fn(c, config); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// gaussian is a multivariate Gaussian over the continuous variables of a network.
+type gaussian struct {
+	mean []float64
+	cov  [][]float64
+}
+
+// jointGaussian calculates the joint Gaussian over all continuous variables,
+// for the given configuration of discrete parents.
+func (n *Network) jointGaussian(model *clgModel, config []int) (*gaussian, error) {
+	cnt := len(model.nodes)
+	g := gaussian{mean: make([]float64, cnt), cov: make([][]float64, cnt)}
+	for i := range g.cov {
+		g.cov[i] = make([]float64, cnt)
+	}
+
+	for i, idx := range model.nodes {
+		intercept, coef, parents, variance, err := n.linearGaussian(model, idx, config)
+		if err != nil {
+			return nil, err
+		}
+		g.mean[i] = intercept
+		for j, p := range parents {
+			g.mean[i] += coef[j] * g.mean[p]
+		}
+		// covariances with previous variables
+		for k := 0; k < i; k++ {
+			c := 0.0
+			for j, p := range parents {
+				c += coef[j] * g.cov[p][k]
+			}
+			g.cov[i][k], g.cov[k][i] = c, c
+		}
+		v := variance
+		for j, p := range parents {
+			for l, q := range parents {
+				v += coef[j] * coef[l] * g.cov[p][q]
+			}
+		}
+		g.cov[i][i] = v
+	}
+	return &g, nil
+}
+
+// linearGaussian returns the parameters of the linear Gaussian distribution of a continuous variable,
+// for the given configuration of discrete parents.
+// Parents are returned as positions in the model's continuous variables.
+func (n *Network) linearGaussian(model *clgModel, index int, config []int) (float64, []float64, []int, float64, error) {
+	v := &n.variables[index]
+	row := 0
+	parents := []int{}
+	for _, p := range n.parentIndices(index) {
+		if pos, ok := model.position[p]; ok {
+			parents = append(parents, pos)
+			continue
+		}
+		d := slices.Index(model.discrete, p)
+		row = row*model.outcomes[d] + config[d]
+	}
+	cols := v.Factor.columns
+	if (row+1)*cols > len(v.Factor.Table) {
+		return 0, nil, nil, 0, newVariableError(v.Name, ErrTableShape, "wrong table size for continuous variable %s", v.Name)
+	}
+	values := v.Factor.Table[row*cols : (row+1)*cols]
+	return values[0], values[1 : cols-1], parents, values[cols-1], nil
+}
+
+// conditionalGaussian calculates the joint Gaussian over all continuous variables,
+// for the given configuration of discrete parents, conditioned on continuous evidence.
+func (n *Network) conditionalGaussian(model *clgModel, config []int, evidence map[string]float64) (*gaussian, error) {
+	g, err := n.jointGaussian(model, config)
+	if err != nil {
+		return nil, err
+	}
+	for name, value := range evidence {
+		idx, err := n.variableIdx(name)
+		if err != nil {
+			return nil, err
+		}
+		g.condition(model.position[idx], value)
+	}
+	return g, nil
+}
+
+// condition conditions the Gaussian on a value of the variable at the given position.
+// Returns the density of the value before conditioning.
+func (g *gaussian) condition(k int, value float64) float64 {
+	mean, variance := g.mean[k], g.cov[k][k]
+	if variance <= 0 {
+		if value == mean {
+			return 1
+		}
+		return 0
+	}
+	diff := value - mean
+	density := math.Exp(-0.5*diff*diff/variance) / math.Sqrt(2*math.Pi*variance)
+
+	cov := slices.Clone(g.cov[k])
+	for i := range g.mean {
+		g.mean[i] += cov[i] / variance * diff
+		for j := range g.mean {
+			g.cov[i][j] -= cov[i] * cov[j] / variance
+		}
+	}
+	g.mean[k], g.cov[k][k] = value, 0
+	return density
+}
+
+// likelihood calculates the density of the continuous evidence, given a configuration of discrete parents.
+func (n *Network) likelihood(model *clgModel, config []int, evidence map[string]float64) (float64, error) {
+	g, err := n.jointGaussian(model, config)
+	if err != nil {
+		return 0, err
+	}
+	names := make([]string, 0, len(evidence))
+	for name := range evidence {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	lik := 1.0
+	for _, name := range names {
+		idx, err := n.variableIdx(name)
+		if err != nil {
+			return 0, err
+		}
+		lik *= g.condition(model.position[idx], evidence[name])
+	}
+	return lik, nil
+}
+
+// likelihoodFactor creates a factor over the discrete parents of continuous variables,
+// representing the density of the continuous evidence.
+func (n *Network) likelihoodFactor(vars *ve.Variables, varNames map[string]*variable, evidence map[string]float64) (*ve.Factor, error) {
+	if len(evidence) == 0 {
+		return nil, nil
+	}
+	model, err := n.clgModel()
+	if err != nil {
+		return nil, err
+	}
+	variables := make([]ve.Variable, len(model.discrete))
+	for i, idx := range model.discrete {
+		variables[i] = varNames[n.variables[idx].Name].VeVariable
+	}
+	table := make([]float64, model.configurations)
+	err = model.forEachConfiguration(func(c int, config []int) error {
+		var err error
+		table[c], err = n.likelihood(model, config, evidence)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	f, err := vars.TryCreateFactor(variables, table)
+	if err != nil {
+		return nil, err
+	}
+	return &f, nil
+}
+
+// prepareContinuousNodes checks continuous variables and their parents, and sets up table columns.
+// Called from prepareVariables.
+func (n *Network) prepareContinuousNodes(varNames map[string]*Variable) error {
+	for i := range n.variables {
+		v := &n.variables[i]
+		if v.NodeType != ve.ContinuousNode {
+			if err := checkDiscreteParents(v, varNames); err != nil {
+				return err
+			}
+			continue
+		}
+		if len(v.Outcomes) > 0 {
+			return fmt.Errorf("continuous variable %s can't have outcomes", v.Name)
+		}
+		if v.Factor == nil {
+			return newVariableError(v.Name, ErrTableShape, "continuous variable %s has no table", v.Name)
+		}
+		rows := 1
+		v.Factor.columns = 2
+		for j, p := range v.Factor.Given {
+			parent := varNames[p]
+			switch parent.NodeType {
+			case ve.ContinuousNode:
+				v.Factor.columns++
+			case ve.ChanceNode:
+				rows *= v.Factor.outcomes[j]
+			default:
+				return fmt.Errorf("continuous variable %s can only have chance and continuous parents; got %s", v.Name, p)
+			}
+		}
+		if len(v.Factor.Table) != rows*v.Factor.columns {
+			return newVariableError(v.Name, ErrTableShape,
+				"wrong table size for continuous variable %s; expected %d rows with %d columns (intercept, coefficients, variance), got %d values",
+				v.Name, rows, v.Factor.columns, len(v.Factor.Table))
+		}
+	}
+	return nil
+}
+
+// checkDiscreteParents checks that a discrete variable has no continuous parents.
+func checkDiscreteParents(v *Variable, varNames map[string]*Variable) error {
+	if v.Factor == nil {
+		return nil
+	}
+	for _, p := range v.Factor.Given {
+		if varNames[p].NodeType == ve.ContinuousNode {
+			return fmt.Errorf("discrete variable %s can't have continuous parent %s", v.Name, p)
+		}
+	}
+	return nil
+}
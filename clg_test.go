@@ -0,0 +1,166 @@
+package bbn_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/mlange-42/bbn"
+	"github.com/stretchr/testify/assert"
+)
+
+func normalPDF(x, mean, variance float64) float64 {
+	return math.Exp(-0.5*(x-mean)*(x-mean)/variance) / math.Sqrt(2*math.Pi*variance)
+}
+
+func TestNetworkSolveContinuous(t *testing.T) {
+	net, err := bbn.NewBuilder("Test", "").
+		AddContinuous("X").
+		AddContinuous("Y").
+		AddEdge("X", "Y").
+		SetTable("X", []float64{0, 1}).
+		SetTable("Y", []float64{1, 2, 1}).
+		Build()
+	assert.Nil(t, err)
+
+	result, err := net.SolveContinuous(nil, nil, []string{"X", "Y"})
+	assert.Nil(t, err)
+	assert.InDelta(t, 0, result["X"].Mean, 1e-9)
+	assert.InDelta(t, 1, result["X"].Variance, 1e-9)
+	assert.InDelta(t, 1, result["Y"].Mean, 1e-9)
+	assert.InDelta(t, 5, result["Y"].Variance, 1e-9)
+
+	result, err = net.SolveContinuous(map[string]string{"Y": "3"}, nil, []string{"X", "Y"})
+	assert.Nil(t, err)
+	assert.InDelta(t, 0.8, result["X"].Mean, 1e-9)
+	assert.InDelta(t, 0.2, result["X"].Variance, 1e-9)
+	assert.InDelta(t, 3, result["Y"].Mean, 1e-9)
+	assert.InDelta(t, 0, result["Y"].Variance, 1e-9)
+
+	_, err = net.SolveContinuous(map[string]string{"Y": "abc"}, nil, []string{"X"})
+	assert.ErrorIs(t, err, bbn.ErrUnknownOutcome)
+	_, err = net.SolveContinuous(nil, nil, []string{"Z"})
+	assert.ErrorIs(t, err, bbn.ErrUnknownVariable)
+}
+
+func TestNetworkSolveContinuousMixed(t *testing.T) {
+	net, err := bbn.FromFile("_examples/continuous/sensor.yml")
+	assert.Nil(t, err)
+	assert.Empty(t, net.Validate())
+
+	// prior
+	result, err := net.SolveContinuous(nil, nil, []string{"Temperature", "Sensor"})
+	assert.Nil(t, err)
+	mean := 0.9*50 + 0.1*80
+	assert.InDelta(t, mean, result["Temperature"].Mean, 1e-9)
+	assert.InDelta(t, 0.9*(25+50*50)+0.1*(100+80*80)-mean*mean, result["Temperature"].Variance, 1e-9)
+	assert.InDelta(t, result["Temperature"].Variance+4, result["Sensor"].Variance, 1e-9)
+
+	// posterior, given continuous evidence
+	evidence := map[string]string{"Sensor": "75"}
+	wOk := 0.9 * normalPDF(75, 50, 29)
+	wFaulty := 0.1 * normalPDF(75, 80, 104)
+	pOk := wOk / (wOk + wFaulty)
+
+	probs, _, err := net.SolveQuery(evidence, []string{"Machine", "Alarm"}, false)
+	assert.Nil(t, err)
+	assert.InDelta(t, pOk, probs["Machine"][0], 1e-9)
+	assert.InDelta(t, pOk*0.05+(1-pOk)*0.9, probs["Alarm"][0], 1e-9)
+
+	result, err = net.SolveContinuous(evidence, nil, []string{"Temperature"})
+	assert.Nil(t, err)
+	meanOk, varOk := 50+25.0/29*(75-50), 25-25.0*25/29
+	meanFaulty, varFaulty := 80+100.0/104*(75-80), 100-100.0*100/104
+	mean = pOk*meanOk + (1-pOk)*meanFaulty
+	variance := pOk*(varOk+meanOk*meanOk) + (1-pOk)*(varFaulty+meanFaulty*meanFaulty) - mean*mean
+	assert.InDelta(t, mean, result["Temperature"].Mean, 1e-9)
+	assert.InDelta(t, variance, result["Temperature"].Variance, 1e-9)
+
+	// discrete evidence
+	result, err = net.SolveContinuous(map[string]string{"Machine": "faulty"}, nil, []string{"Sensor"})
+	assert.Nil(t, err)
+	assert.InDelta(t, 80, result["Sensor"].Mean, 1e-9)
+	assert.InDelta(t, 104, result["Sensor"].Variance, 1e-9)
+
+	result, err = net.SolveContinuous(map[string]string{"Alarm": "yes"}, nil, []string{"Temperature"})
+	assert.Nil(t, err)
+	pOk = 0.9 * 0.05 / (0.9*0.05 + 0.1*0.9)
+	assert.InDelta(t, pOk*50+(1-pOk)*80, result["Temperature"].Mean, 1e-9)
+
+	// interventions
+	result, err = net.SolveContinuous(nil, map[string]string{"Machine": "faulty"}, []string{"Temperature"})
+	assert.Nil(t, err)
+	assert.InDelta(t, 80, result["Temperature"].Mean, 1e-9)
+	assert.InDelta(t, 100, result["Temperature"].Variance, 1e-9)
+
+	result, err = net.SolveContinuous(nil, map[string]string{"Alarm": "yes"}, []string{"Temperature"})
+	assert.Nil(t, err)
+	assert.InDelta(t, 0.9*50+0.1*80, result["Temperature"].Mean, 1e-9)
+
+	_, err = net.SolveContinuous(nil, map[string]string{"Temperature": "50"}, []string{"Sensor"})
+	assert.NotNil(t, err)
+
+	_, _, err = net.SolveQuery(nil, []string{"Temperature"}, false)
+	assert.ErrorIs(t, err, bbn.ErrUnsupported)
+}
+
+func TestNetworkContinuousErrors(t *testing.T) {
+	_, err := bbn.NewBuilder("Test", "").
+		AddContinuous("X").
+		AddChance("A", "yes", "no").
+		AddEdge("X", "A").
+		SetTable("X", []float64{0, 1}).
+		SetTable("A", []float64{0.5, 0.5}).
+		Build()
+	assert.NotNil(t, err)
+
+	_, err = bbn.NewBuilder("Test", "").
+		AddChance("A", "yes", "no").
+		AddContinuous("X").
+		AddEdge("A", "X").
+		SetTable("A", []float64{0.5, 0.5}).
+		SetTable("X", []float64{0, 1}).
+		Build()
+	assert.ErrorIs(t, err, bbn.ErrTableShape)
+
+	net, err := bbn.NewBuilder("Test", "").
+		AddContinuous("X").
+		SetTable("X", []float64{0, -1}).
+		Build()
+	assert.Nil(t, err)
+	assert.True(t, net.Validate().HasErrors())
+}
+
+func TestNetworkContinuousYAML(t *testing.T) {
+	net, err := bbn.FromFile("_examples/continuous/sensor.yml")
+	assert.Nil(t, err)
+
+	yml, err := bbn.ToYAML(net)
+	assert.Nil(t, err)
+
+	net2, err := bbn.FromYAML(yml)
+	assert.Nil(t, err)
+	assert.Equal(t, net.Variables()[2].Factor.Table, net2.Variables()[2].Factor.Table)
+	assert.Equal(t, net.Variables()[2].NodeType, net2.Variables()[2].NodeType)
+}
+
+func TestNetworkContinuousMutate(t *testing.T) {
+	net, err := bbn.NewBuilder("Test", "").
+		AddContinuous("X").
+		AddContinuous("Y").
+		SetTable("X", []float64{2, 1}).
+		SetTable("Y", []float64{1, 1}).
+		Build()
+	assert.Nil(t, err)
+
+	assert.Nil(t, net.AddEdge("X", "Y"))
+	assert.Equal(t, []float64{1, 0, 1}, net.Variables()[1].Factor.Table)
+
+	result, err := net.SolveContinuous(nil, nil, []string{"Y"})
+	assert.Nil(t, err)
+	assert.InDelta(t, 1, result["Y"].Mean, 1e-9)
+
+	assert.Nil(t, net.RemoveEdge("X", "Y"))
+	assert.Equal(t, []float64{1, 1}, net.Variables()[1].Factor.Table)
+
+	assert.NotNil(t, net.AddOutcome("X", "abc"))
+}
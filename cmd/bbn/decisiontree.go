@@ -0,0 +1,85 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/mlange-42/bbn"
+	"github.com/mlange-42/bbn/internal/tui"
+	"github.com/spf13/cobra"
+)
+
+// decisionTreeCommand exports the decision tree of a decision network.
+func decisionTreeCommand() *cobra.Command {
+	evidence := []string{}
+	var format string
+	var maxNodes int
+
+	root := cobra.Command{
+		Use:   "decision-tree file",
+		Short: "Exports the decision tree equivalent to a decision network.",
+		Long: `Exports the decision tree equivalent to a decision network.
+
+Decisions and the chance variables observed before them appear in information order.
+Chance branches show the conditional probability of the outcome, and zero-probability branches are pruned.
+Each node shows its expected utility (EU), and optimal decision branches are marked by '*'.
+
+Output formats are indented text, JSON and DOT for rendering with Graphviz.`,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		Args:          cobra.ExactArgs(1),
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			tree, err := runDecisionTreeCommand(args[0], evidence, maxNodes)
+			if err != nil {
+				return err
+			}
+			out, err := formatDecisionTree(tree, format)
+			if err != nil {
+				return err
+			}
+			fmt.Print(out)
+			return nil
+		},
+	}
+	root.Flags().StringSliceVarP(&evidence, "evidence", "e", []string{}, "Evidence in the format:\n    k1=v1,k2=v2,k3=v3")
+	root.Flags().StringVarP(&format, "format", "f", "text", "Output format [text, json, dot]")
+	root.Flags().IntVarP(&maxNodes, "max-nodes", "m", 10000, "Maximum number of tree nodes, 0 for no limit")
+
+	root.Flags().SortFlags = false
+
+	return &root
+}
+
+func runDecisionTreeCommand(path string, evidence []string, maxNodes int) (*bbn.TreeNode, error) {
+	net, err := bbn.FromFile(path)
+	if err != nil {
+		return nil, err
+	}
+	ev, err := tui.ParseEvidence(evidence)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := net.SolvePolicies(true); err != nil {
+		return nil, err
+	}
+	return net.DecisionTree(ev, maxNodes)
+}
+
+func formatDecisionTree(tree *bbn.TreeNode, format string) (string, error) {
+	switch format {
+	case "text":
+		return tree.Text(), nil
+	case "dot":
+		return tree.DOT(), nil
+	case "json":
+		js, err := json.MarshalIndent(tree, "", "  ")
+		if err != nil {
+			return "", err
+		}
+		return string(js) + "\n", nil
+	}
+	return "", fmt.Errorf("unknown output format %s; use one of [text, json, dot]", format)
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/mlange-42/bbn"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunDecisionTreeCommand(t *testing.T) {
+	tree, err := runDecisionTreeCommand("../../_examples/decision/oil.yml", []string{}, 0)
+	assert.Nil(t, err)
+	assert.Equal(t, "Do test drill", tree.Variable)
+
+	tree, err = runDecisionTreeCommand("../../_examples/decision/umbrella.yml", []string{"Forecast=Rainy"}, 0)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, tree.Size())
+
+	_, err = runDecisionTreeCommand("../../_examples/decision/oil.yml", []string{}, 10)
+	assert.ErrorIs(t, err, bbn.ErrTreeSize)
+
+	_, err = runDecisionTreeCommand("../../_examples/bbn/sprinkler.yml", []string{}, 0)
+	assert.NotNil(t, err)
+
+	_, err = runDecisionTreeCommand("../../_examples/decision/missing.yml", []string{}, 0)
+	assert.NotNil(t, err)
+}
+
+func TestFormatDecisionTree(t *testing.T) {
+	tree, err := runDecisionTreeCommand("../../_examples/decision/umbrella.yml", []string{"Forecast=Rainy"}, 0)
+	assert.Nil(t, err)
+
+	text, err := formatDecisionTree(tree, "text")
+	assert.Nil(t, err)
+	assert.Contains(t, text, "*Take: EU 56.000")
+
+	dot, err := formatDecisionTree(tree, "dot")
+	assert.Nil(t, err)
+	assert.Contains(t, dot, "digraph DecisionTree")
+
+	js, err := formatDecisionTree(tree, "json")
+	assert.Nil(t, err)
+	decoded := bbn.TreeNode{}
+	assert.Nil(t, json.Unmarshal([]byte(js), &decoded))
+	assert.Equal(t, *tree, decoded)
+
+	_, err = formatDecisionTree(tree, "xml")
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,162 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/mlange-42/bbn"
+	"github.com/spf13/cobra"
+)
+
+// discretizeCommand proposes bins for numeric data columns.
+func discretizeCommand() *cobra.Command {
+	var method string
+	var bins int
+	var target string
+	var delim string
+	var noData string
+
+	root := cobra.Command{
+		Use:   "discretize data-file column...",
+		Short: "Proposes bins for numeric data columns.",
+		Long: `Proposes bins for numeric data columns.
+
+Prints variable snippets with 'outcomes' and 'bins' in YAML format, for use in a network file.
+Methods are:
+  equal-width      bins of equal width
+  equal-frequency  bins with equal numbers of values
+  mdl              supervised bins, using the MDL method by Fayyad & Irani; requires --target`,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		Args:          cobra.MinimumNArgs(2),
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			delimRunes := []rune(delim)
+			if len(delimRunes) != 1 {
+				return fmt.Errorf("argument for --delim must be a single rune; got '%s'", delim)
+			}
+
+			result, err := runDiscretizeCommand(args[0], args[1:], method, bins, target, noData, delimRunes[0])
+			if err != nil {
+				return err
+			}
+
+			for i, column := range args[1:] {
+				fmt.Printf("- variable: %s\n", column)
+				fmt.Printf("  outcomes: [%s]\n", strings.Join(bbn.BinLabels(result[i]), ", "))
+				fmt.Printf("  bins: [%s]\n", formatBins(result[i]))
+			}
+			return nil
+		},
+	}
+
+	root.Flags().StringVarP(&method, "method", "m", "equal-width", "Discretization method [equal-width, equal-frequency, mdl]")
+	root.Flags().IntVarP(&bins, "bins", "b", 3, "Number of bins, for equal-width and equal-frequency")
+	root.Flags().StringVarP(&target, "target", "t", "", "Class column for supervised discretization, for mdl")
+	root.Flags().StringVarP(&noData, "no-data", "n", "", "Value for missing data (default \"\")")
+	root.Flags().StringVarP(&delim, "delim", "d", ",", "CSV delimiter")
+
+	root.Flags().SortFlags = false
+
+	return &root
+}
+
+func runDiscretizeCommand(path string, columns []string, method string, bins int, target string, noData string, delimiter rune) ([][]float64, error) {
+	if method == "mdl" && target == "" {
+		return nil, fmt.Errorf("method mdl requires a target column (--target)")
+	}
+	result := make([][]float64, len(columns))
+	for i, column := range columns {
+		values, classes, err := readColumn(path, column, target, noData, delimiter)
+		if err != nil {
+			return nil, err
+		}
+		switch method {
+		case "equal-width":
+			result[i], err = bbn.EqualWidthBins(values, bins)
+		case "equal-frequency":
+			result[i], err = bbn.EqualFrequencyBins(values, bins)
+		case "mdl":
+			result[i], err = bbn.MDLBins(values, classes)
+		default:
+			return nil, fmt.Errorf("unknown discretization method '%s'; valid methods are equal-width, equal-frequency, mdl", method)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("column '%s': %s", column, err.Error())
+		}
+	}
+	return result, nil
+}
+
+// readColumn reads a numeric column from a CSV file, and optionally the values of a target column.
+// Rows with missing data are skipped.
+func readColumn(path string, column string, target string, noData string, delimiter rune) ([]float64, []string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer file.Close()
+
+	r := csv.NewReader(file)
+	r.ReuseRecord = true
+	r.Comma = delimiter
+
+	header, err := r.Read()
+	if err != nil {
+		return nil, nil, err
+	}
+	idx := slices.Index(header, column)
+	if idx < 0 {
+		return nil, nil, fmt.Errorf("no column '%s' in data file", column)
+	}
+	targetIdx := idx
+	if target != "" {
+		if targetIdx = slices.Index(header, target); targetIdx < 0 {
+			return nil, nil, fmt.Errorf("no column '%s' in data file", target)
+		}
+	}
+
+	values, classes := []float64{}, []string{}
+	for {
+		record, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		if record[idx] == noData || record[targetIdx] == noData {
+			continue
+		}
+		v, err := strconv.ParseFloat(record[idx], 64)
+		if err != nil {
+			return nil, nil, fmt.Errorf("unable to parse value '%s' in column '%s' to a number", record[idx], column)
+		}
+		values = append(values, v)
+		classes = append(classes, record[targetIdx])
+	}
+	return values, classes, nil
+}
+
+func formatBins(bins []float64) string {
+	parts := make([]string, len(bins))
+	for i, b := range bins {
+		switch {
+		case math.IsInf(b, 1):
+			parts[i] = "inf"
+		case math.IsInf(b, -1):
+			parts[i] = "-inf"
+		default:
+			parts[i] = strconv.FormatFloat(b, 'g', -1, 64)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,29 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunDiscretizeCommand(t *testing.T) {
+	path := "../../_examples/bbn/weather.csv"
+
+	result, err := runDiscretizeCommand(path, []string{"Temperature", "Humidity"}, "equal-frequency", 4, "", "", ',')
+	assert.Nil(t, err)
+	assert.Len(t, result, 2)
+	assert.Len(t, result[0], 5)
+
+	result, err = runDiscretizeCommand(path, []string{"Temperature"}, "mdl", 0, "Play", "", ',')
+	assert.Nil(t, err)
+	assert.Greater(t, len(result[0]), 2)
+
+	_, err = runDiscretizeCommand(path, []string{"Temperature"}, "mdl", 0, "", "", ',')
+	assert.NotNil(t, err)
+	_, err = runDiscretizeCommand(path, []string{"Temperature"}, "foo", 3, "", "", ',')
+	assert.NotNil(t, err)
+	_, err = runDiscretizeCommand(path, []string{"Play"}, "equal-width", 3, "", "", ',')
+	assert.NotNil(t, err)
+	_, err = runDiscretizeCommand(path, []string{"Foo"}, "equal-width", 3, "", "", ',')
+	assert.NotNil(t, err)
+}
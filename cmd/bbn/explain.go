@@ -0,0 +1,62 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/mlange-42/bbn"
+	"github.com/mlange-42/bbn/internal/tui"
+	"github.com/spf13/cobra"
+)
+
+// explainCommand explains the impact of evidence on a target variable.
+func explainCommand() *cobra.Command {
+	evidence := []string{}
+
+	root := cobra.Command{
+		Use:   "explain file target",
+		Short: "Explains the impact of evidence on the posterior of a target variable.",
+		Long: `Explains the impact of evidence on the posterior of a target variable.
+
+For each finding, reports the target posterior without that finding (leave-one-out),
+and the finding's log-likelihood ratio for each target outcome, given the remaining evidence.
+Positive ratios indicate support for an outcome, negative ratios indicate evidence against it.
+
+Further, reports Jensen's data conflict measure for the whole evidence.
+Positive values indicate that findings are in conflict.`,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		Args:          cobra.ExactArgs(2),
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			exp, err := runExplainCommand(args[0], args[1], evidence)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("Evidence impact on %s\n\n", exp.Target)
+			fmt.Print(tui.FormatExplanation(exp))
+			return nil
+		},
+	}
+	root.Flags().StringSliceVarP(&evidence, "evidence", "e", []string{}, "Evidence in the format:\n    k1=v1,k2=v2,k3=v3")
+
+	root.Flags().SortFlags = false
+
+	return &root
+}
+
+func runExplainCommand(path string, target string, evidence []string) (*bbn.Explanation, error) {
+	net, err := bbn.FromFile(path)
+	if err != nil {
+		return nil, err
+	}
+	ev, err := tui.ParseEvidence(evidence)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := net.SolvePolicies(true); err != nil {
+		return nil, err
+	}
+	return net.ExplainEvidence(ev, target)
+}
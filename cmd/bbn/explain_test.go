@@ -0,0 +1,27 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunExplainCommand(t *testing.T) {
+	exp, err := runExplainCommand("../../_examples/bbn/sprinkler.yml", "Rain", []string{"GrassWet=yes", "Sprinkler=yes"})
+	assert.Nil(t, err)
+	assert.Len(t, exp.Findings, 2)
+	assert.Equal(t, "Sprinkler", exp.Findings[0].Variable)
+
+	exp, err = runExplainCommand("../../_examples/decision/umbrella.yml", "Weather", []string{"Forecast=Rainy", "Umbrella=Take"})
+	assert.Nil(t, err)
+	assert.Len(t, exp.Findings, 2)
+
+	_, err = runExplainCommand("../../_examples/bbn/sprinkler.yml", "Rain", []string{"GrassWet"})
+	assert.NotNil(t, err)
+
+	_, err = runExplainCommand("../../_examples/bbn/sprinkler.yml", "Foo", []string{})
+	assert.NotNil(t, err)
+
+	_, err = runExplainCommand("../../_examples/bbn/missing.yml", "Rain", []string{})
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/mlange-42/bbn"
+	"github.com/mlange-42/bbn/internal/tui"
+	"github.com/spf13/cobra"
+)
+
+// explainDecisionCommand explains the optimal action of a decision.
+func explainDecisionCommand() *cobra.Command {
+	evidence := []string{}
+
+	root := cobra.Command{
+		Use:   "explain-decision file decision",
+		Short: "Explains the optimal action of a decision.",
+		Long: `Explains the optimal action of a decision.
+
+Reports the expected utility of each alternative, and the contribution of each utility variable
+to the difference between the optimal action and the runner-up.
+Contributions are omitted if the total utility has a utility function, as they are not additive then.
+
+Further, reports the uncertain variables whose change would flip the decision,
+with the threshold probability of an outcome at which another action becomes optimal.
+The probabilities of the variable's other outcomes are scaled proportionally.`,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		Args:          cobra.ExactArgs(2),
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			exp, err := runExplainDecisionCommand(args[0], args[1], evidence)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("Decision %s\n\n", exp.Decision)
+			fmt.Print(tui.FormatDecisionExplanation(exp))
+			return nil
+		},
+	}
+	root.Flags().StringSliceVarP(&evidence, "evidence", "e", []string{}, "Evidence in the format:\n    k1=v1,k2=v2,k3=v3")
+
+	root.Flags().SortFlags = false
+
+	return &root
+}
+
+func runExplainDecisionCommand(path string, decision string, evidence []string) (*bbn.DecisionExplanation, error) {
+	net, err := bbn.FromFile(path)
+	if err != nil {
+		return nil, err
+	}
+	ev, err := tui.ParseEvidence(evidence)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := net.SolvePolicies(true); err != nil {
+		return nil, err
+	}
+	return net.ExplainDecision(ev, decision)
+}
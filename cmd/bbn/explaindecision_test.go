@@ -0,0 +1,26 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunExplainDecisionCommand(t *testing.T) {
+	exp, err := runExplainDecisionCommand("../../_examples/decision/oil.yml", "Do drill", []string{"Do test drill=yes", "Test result=diffuse"})
+	assert.Nil(t, err)
+	assert.Equal(t, "no", exp.Action)
+	assert.Equal(t, "Oil", exp.Thresholds[0].Variable)
+
+	_, err = runExplainDecisionCommand("../../_examples/decision/oil.yml", "Oil", []string{})
+	assert.NotNil(t, err)
+
+	_, err = runExplainDecisionCommand("../../_examples/decision/oil.yml", "Do drill", []string{"Oil"})
+	assert.NotNil(t, err)
+
+	_, err = runExplainDecisionCommand("../../_examples/bbn/sprinkler.yml", "Rain", []string{})
+	assert.NotNil(t, err)
+
+	_, err = runExplainDecisionCommand("../../_examples/decision/missing.yml", "Do drill", []string{})
+	assert.NotNil(t, err)
+}
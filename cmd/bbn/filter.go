@@ -0,0 +1,181 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+
+	"github.com/mlange-42/bbn"
+	"github.com/spf13/cobra"
+)
+
+// filterCommand performs filtering in a dynamic network.
+func filterCommand() *cobra.Command {
+	var index string
+	var predict int
+	var smooth bool
+	var delim string
+	var noData string
+
+	root := cobra.Command{
+		Use:   "filter dbn-file data-file",
+		Short: "Performs filtering in a dynamic network over a time series.",
+		Long: `Performs filtering in a dynamic network over a time series.
+
+The data file is a CSV file with one row per time step, in temporal order.
+Columns are variables of the network, and contain evidence.
+Use the no-data value (--no-data) for missing observations.
+An optional index column (--index) labels the time steps.
+
+By default, marginals at each step are conditioned on evidence up to that step.
+With --smooth, they are conditioned on all evidence.
+Evidence variables are marked by '+'.`,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		Args:          cobra.ExactArgs(2),
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			delimRunes := []rune(delim)
+			if len(delimRunes) != 1 {
+				return fmt.Errorf("argument for --delim must be a single rune; got '%s'", delim)
+			}
+
+			result, err := runFilterCommand(args[0], args[1], index, predict, smooth, noData, delimRunes[0])
+			if err != nil {
+				return err
+			}
+
+			for t, marginals := range result.Marginals {
+				fmt.Printf("%s\n", result.Labels[t])
+				for _, node := range result.Variables {
+					fmt.Print("                              ")
+					for _, s := range node.Outcomes {
+						fmt.Printf(" %10s", s)
+					}
+					fmt.Printf("\n%30s", node.Name)
+					for _, p := range marginals[node.Name] {
+						fmt.Printf(" %9.3f%%", p*100)
+					}
+					if t < len(result.Evidence) {
+						if _, ok := result.Evidence[t][node.Name]; ok {
+							fmt.Print("  +")
+						}
+					}
+					fmt.Println()
+				}
+			}
+			return nil
+		},
+	}
+
+	root.Flags().StringVarP(&index, "index", "i", "", "Index column for labelling time steps, optional")
+	root.Flags().IntVarP(&predict, "predict", "p", 0, "Number of time steps to predict after the data")
+	root.Flags().BoolVarP(&smooth, "smooth", "s", false, "Condition on all evidence (smoothing) instead of filtering")
+	root.Flags().StringVarP(&noData, "no-data", "n", "", "Value for missing data (default \"\")")
+	root.Flags().StringVarP(&delim, "delim", "d", ",", "CSV delimiter")
+
+	root.Flags().SortFlags = false
+
+	return &root
+}
+
+// filterResult holds the results of the filter command.
+type filterResult struct {
+	Variables []bbn.Variable         // Variables of a time slice.
+	Labels    []string               // Labels of the time steps, including predicted steps.
+	Evidence  []map[string]string    // Evidence for each time step with data.
+	Marginals []map[string][]float64 // Marginals for each time step, including predicted steps.
+}
+
+func runFilterCommand(path string, dataFile string, index string, predict int, smooth bool, noData string, delimiter rune) (*filterResult, error) {
+	dbn, err := bbn.DBNFromFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if smooth && predict > 0 {
+		return nil, fmt.Errorf("prediction is not supported together with smoothing")
+	}
+
+	labels, evidence, err := readTimeSeries(dataFile, index, noData, delimiter)
+	if err != nil {
+		return nil, err
+	}
+
+	var marginals []map[string][]float64
+	if smooth {
+		marginals, err = dbn.Smooth(evidence)
+	} else {
+		marginals, err = dbn.Predict(evidence, predict)
+	}
+	if err != nil {
+		return nil, err
+	}
+	prefix := "Step"
+	if index != "" {
+		prefix = index
+	}
+	for i := 1; i <= predict; i++ {
+		labels = append(labels, prefix+" +"+strconv.Itoa(i))
+	}
+
+	return &filterResult{
+		Variables: dbn.Variables(),
+		Labels:    labels,
+		Evidence:  evidence,
+		Marginals: marginals,
+	}, nil
+}
+
+// readTimeSeries reads evidence from a CSV file with one row per time step.
+// Returns labels of the time steps, and evidence per time step.
+func readTimeSeries(path string, index string, noData string, delimiter rune) ([]string, []map[string]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer file.Close()
+
+	r := csv.NewReader(file)
+	r.Comma = delimiter
+
+	header, err := r.Read()
+	if err != nil {
+		return nil, nil, err
+	}
+	indexCol := -1
+	if index != "" {
+		if indexCol = slices.Index(header, index); indexCol < 0 {
+			return nil, nil, fmt.Errorf("no index column '%s' in data file", index)
+		}
+	}
+
+	labels, evidence := []string{}, []map[string]string{}
+	for {
+		record, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		label := "Step " + strconv.Itoa(len(labels))
+		ev := map[string]string{}
+		for i, value := range record {
+			if i == indexCol {
+				label = index + " " + value
+				continue
+			}
+			if value != noData {
+				ev[header[i]] = value
+			}
+		}
+		labels = append(labels, label)
+		evidence = append(evidence, ev)
+	}
+	return labels, evidence, nil
+}
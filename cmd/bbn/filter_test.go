@@ -0,0 +1,39 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunFilterCommand(t *testing.T) {
+	result, err := runFilterCommand("../../_examples/dbn/umbrella.yml", "../../_examples/dbn/umbrella.csv", "Day", 2, false, "", ',')
+	assert.Nil(t, err)
+	assert.Len(t, result.Marginals, 7)
+	assert.Equal(t, []string{"Day 1", "Day 2", "Day 3", "Day 4", "Day 5", "Day +1", "Day +2"}, result.Labels)
+	assert.InDelta(t, 0.818, result.Marginals[0]["Rain"][0], 0.001)
+	assert.InDelta(t, 0.883, result.Marginals[1]["Rain"][0], 0.001)
+	assert.Equal(t, map[string]string{"Umbrella": "no"}, result.Evidence[2])
+
+	result, err = runFilterCommand("../../_examples/dbn/umbrella.yml", "../../_examples/dbn/umbrella.csv", "Day", 0, true, "", ',')
+	assert.Nil(t, err)
+	assert.Len(t, result.Marginals, 5)
+	assert.InDelta(t, 0.867, result.Marginals[0]["Rain"][0], 0.001)
+
+	result, err = runFilterCommand("../../_examples/dbn/umbrella.yml", "../../_examples/dbn/umbrella.csv", "", 0, false, "", ';')
+	assert.ErrorContains(t, err, "evidence variable Day,Umbrella not found")
+
+	result, err = runFilterCommand("../../_examples/dbn/monitoring.yml", "../../_examples/dbn/monitoring.csv", "Hour", 0, false, "", ',')
+	assert.Nil(t, err)
+	assert.NotContains(t, result.Evidence[2], "Alarm")
+	assert.Greater(t, result.Marginals[5]["Health"][2], 0.5)
+
+	_, err = runFilterCommand("../../_examples/dbn/umbrella.yml", "../../_examples/dbn/umbrella.csv", "", 0, false, "", ',')
+	assert.ErrorContains(t, err, "evidence variable Day not found")
+
+	_, err = runFilterCommand("../../_examples/dbn/umbrella.yml", "../../_examples/dbn/umbrella.csv", "Time", 0, false, "", ',')
+	assert.NotNil(t, err)
+
+	_, err = runFilterCommand("../../_examples/dbn/umbrella.yml", "../../_examples/dbn/umbrella.csv", "Day", 1, true, "", ',')
+	assert.NotNil(t, err)
+}
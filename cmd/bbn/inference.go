@@ -12,11 +12,18 @@ import (
 // inferCommand performs rejection sampling.
 func inferCommand() *cobra.Command {
 	evidence := []string{}
+	do := []string{}
 
 	root := cobra.Command{
-		Use:           "inference file",
-		Short:         "Performs inference by variable elimination.",
-		Long:          `Performs inference by variable elimination.`,
+		Use:   "inference file",
+		Short: "Performs inference by variable elimination.",
+		Long: `Performs inference by variable elimination.
+
+Evidence conditions on observations, while interventions (--do) are applied using the do-operator:
+incoming edges of intervened variables are cut, and their outcome is fixed.
+Evidence variables are marked by '+', intervened variables by 'do'.
+
+For continuous variables, evidence is given as a number, and the posterior mean and variance are reported.`,
 		SilenceUsage:  true,
 		SilenceErrors: true,
 		Args:          cobra.ExactArgs(1),
@@ -24,21 +31,24 @@ func inferCommand() *cobra.Command {
 			return nil
 		},
 		RunE: func(cmd *cobra.Command, args []string) error {
-			nodes, ev, result, err := runInferenceCommand(args[0], evidence)
+			net, ev, doEv, result, err := runInferenceCommand(args[0], evidence, do)
 			if err != nil {
 				return err
 			}
 
-			for _, node := range nodes {
+			for i, node := range net.Variables() {
 				fmt.Print("                              ")
 				states := node.Outcomes
+				if node.NodeType == ve.ContinuousNode {
+					states = []string{"mean", "variance"}
+				}
 				for _, s := range states {
 					fmt.Printf(" %10s", s)
 				}
 				fmt.Printf("\n%30s", node.Name)
 				probs := result[node.Name]
 				for _, p := range probs {
-					if node.NodeType == ve.UtilityNode {
+					if node.NodeType == ve.UtilityNode || node.NodeType == ve.ContinuousNode {
 						fmt.Printf(" %10.3f", p)
 					} else {
 						fmt.Printf(" %9.3f%%", p*100)
@@ -47,45 +57,61 @@ func inferCommand() *cobra.Command {
 				if _, ok := ev[node.Name]; ok {
 					fmt.Print("  +")
 				}
+				if _, ok := doEv[node.Name]; ok {
+					fmt.Print("  do")
+				}
 				fmt.Println()
+				if i == net.TotalUtilityIndex() && node.Factor.Utility != nil {
+					if ce, ok := net.CertaintyEquivalent(probs[len(probs)-1]); ok {
+						fmt.Printf("%30s %10.3f\n", "certainty equivalent", ce)
+					}
+				}
 			}
 
 			return nil
 		},
 	}
 	root.Flags().StringSliceVarP(&evidence, "evidence", "e", []string{}, "Evidence in the format:\n    k1=v1,k2=v2,k3=v3")
+	root.Flags().StringSliceVar(&do, "do", []string{}, "Interventions (do-operator) in the format:\n    k1=v1,k2=v2,k3=v3")
 
 	root.Flags().SortFlags = false
 
 	return &root
 }
 
-func runInferenceCommand(path string, evidence []string) ([]bbn.Variable, map[string]string, map[string][]float64, error) {
+func runInferenceCommand(path string, evidence []string, do []string) (*bbn.Network, map[string]string, map[string]string, map[string][]float64, error) {
 	net, err := bbn.FromFile(path)
 	if err != nil {
-		return nil, nil, nil, err
+		return nil, nil, nil, nil, err
 	}
 
 	ev, err := tui.ParseEvidence(evidence)
 	if err != nil {
-		return nil, nil, nil, err
+		return nil, nil, nil, nil, err
+	}
+	doEv, err := tui.ParseEvidence(do)
+	if err != nil {
+		return nil, nil, nil, nil, err
 	}
 
 	nodes := net.Variables()
 	tuiNodes := make([]tui.Node, len(nodes))
 	for i, n := range nodes {
-		tuiNodes[i] = tui.NewNode(n)
+		tuiNodes[i], err = tui.NewNode(n)
+		if err != nil {
+			return nil, nil, nil, nil, err
+		}
 	}
 
 	_, err = net.SolvePolicies(true)
 	if err != nil {
-		return nil, nil, nil, err
+		return nil, nil, nil, nil, err
 	}
 
-	result, err := tui.Solve(net, ev, tuiNodes, false)
+	result, err := tui.Solve(net, ev, doEv, tuiNodes, false)
 	if err != nil {
-		return nil, nil, nil, err
+		return nil, nil, nil, nil, err
 	}
 
-	return nodes, ev, result, nil
+	return net, ev, doEv, result, nil
 }
@@ -7,6 +7,37 @@ import (
 )
 
 func TestRunInferenceCommand(t *testing.T) {
-	_, _, _, err := runInferenceCommand("../../_examples/bbn/sprinkler.yml", []string{"Rain=no"})
+	_, _, _, _, err := runInferenceCommand("../../_examples/bbn/sprinkler.yml", []string{"Rain=no"}, nil)
 	assert.Nil(t, err)
+
+	_, _, _, result, err := runInferenceCommand("../../_examples/bbn/sprinkler.yml", nil, []string{"Sprinkler=yes"})
+	assert.Nil(t, err)
+	assert.Equal(t, []float64{1, 0}, result["Sprinkler"])
+	assert.InDelta(t, 0.2, result["Rain"][0], 1e-9)
+
+	_, _, _, _, err = runInferenceCommand("../../_examples/bbn/sprinkler.yml", []string{"Rain=no"}, []string{"Rain=yes"})
+	assert.NotNil(t, err)
+}
+
+func TestRunInferenceCommandContinuous(t *testing.T) {
+	_, _, _, result, err := runInferenceCommand("../../_examples/continuous/sensor.yml", []string{"Sensor=75"}, nil)
+	assert.Nil(t, err)
+	assert.Greater(t, result["Machine"][1], 0.99)
+	assert.Len(t, result["Temperature"], 2)
+
+	_, _, _, result, err = runInferenceCommand("../../_examples/continuous/sensor.yml", nil, []string{"Machine=faulty"})
+	assert.Nil(t, err)
+	assert.InDelta(t, 80, result["Temperature"][0], 1e-9)
+	assert.InDelta(t, 100, result["Temperature"][1], 1e-9)
+}
+
+func TestRunInferenceCommandUtility(t *testing.T) {
+	net, _, _, result, err := runInferenceCommand("../../_examples/decision/investment.yml", nil, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, []float64{0, 1}, result["Investment"])
+
+	total := result["Total"]
+	ce, ok := net.CertaintyEquivalent(total[len(total)-1])
+	assert.True(t, ok)
+	assert.InDelta(t, 40, ce, 1e-9)
 }
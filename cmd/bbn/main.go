@@ -32,6 +32,16 @@ func rootCommand() *cobra.Command {
 	}
 	root.AddCommand(inferCommand())
 	root.AddCommand(trainCommand())
+	root.AddCommand(validateCommand())
+	root.AddCommand(discretizeCommand())
+	root.AddCommand(filterCommand())
+	root.AddCommand(sensitivityCommand())
+	root.AddCommand(explainCommand())
+	root.AddCommand(riskCommand())
+	root.AddCommand(decisionTreeCommand())
+	root.AddCommand(policyCommand())
+	root.AddCommand(valueCommand())
+	root.AddCommand(explainDecisionCommand())
 
 	return &root
 }
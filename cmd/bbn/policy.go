@@ -0,0 +1,106 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/mlange-42/bbn"
+	"github.com/mlange-42/bbn/internal/tui"
+	"github.com/spf13/cobra"
+)
+
+// policyCommand reports the solved policies of a decision network.
+func policyCommand() *cobra.Command {
+	var asJSON bool
+	var iterations int
+
+	root := cobra.Command{
+		Use:   "policy file [decision]",
+		Short: "Reports the optimal policies of decisions.",
+		Long: `Reports the optimal policies of decisions.
+
+For each decision and each combination of outcomes of its informational parents,
+reports the expected utility of each alternative, the optimal action,
+and the margin of the optimal action to the runner-up.
+Ties and combinations of parent outcomes that can't occur are flagged.
+Finally, the strategy is summarized for each decision.
+
+For limited-memory influence diagrams (LIMIDs), policies are solved by single policy update,
+and it is reported whether the solution is guaranteed to be optimal.
+
+Without a decision, all decisions are reported.`,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		Args:          cobra.RangeArgs(1, 2),
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			decision := ""
+			if len(args) > 1 {
+				decision = args[1]
+			}
+			reports, solution, err := runPolicyCommand(args[0], decision, iterations)
+			if err != nil {
+				return err
+			}
+			if asJSON {
+				js, err := json.MarshalIndent(reports, "", "  ")
+				if err != nil {
+					return err
+				}
+				fmt.Println(string(js))
+				return nil
+			}
+			if solution != nil {
+				fmt.Println(tui.FormatLIMIDSolution(solution))
+				fmt.Println()
+			}
+			for i := range reports {
+				if i > 0 {
+					fmt.Println()
+				}
+				fmt.Print(tui.FormatPolicyReport(&reports[i]))
+			}
+			return nil
+		},
+	}
+	root.Flags().BoolVarP(&asJSON, "json", "j", false, "Print reports in JSON format")
+	root.Flags().IntVarP(&iterations, "iterations", "i", 100, "Maximum number of single policy update iterations, for LIMIDs")
+
+	root.Flags().SortFlags = false
+
+	return &root
+}
+
+func runPolicyCommand(path string, decision string, iterations int) ([]bbn.PolicyReport, *bbn.LIMIDSolution, error) {
+	net, err := bbn.FromFile(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	var solution *bbn.LIMIDSolution
+	if net.LIMID() {
+		solution, err = net.SolveLIMID(iterations)
+	} else {
+		_, err = net.SolvePolicies(true)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	reports, err := net.PolicyReports()
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(reports) == 0 {
+		return nil, nil, fmt.Errorf("network has no decisions")
+	}
+	if decision == "" {
+		return reports, solution, nil
+	}
+	for _, r := range reports {
+		if r.Decision == decision {
+			return []bbn.PolicyReport{r}, solution, nil
+		}
+	}
+	return nil, nil, fmt.Errorf("decision variable %s not found", decision)
+}
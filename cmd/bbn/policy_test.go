@@ -0,0 +1,38 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunPolicyCommand(t *testing.T) {
+	reports, solution, err := runPolicyCommand("../../_examples/decision/oil.yml", "", 100)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(reports))
+	assert.Nil(t, solution)
+
+	reports, _, err = runPolicyCommand("../../_examples/decision/oil.yml", "Do drill", 100)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(reports))
+	assert.Equal(t, "Do drill", reports[0].Decision)
+
+	reports, solution, err = runPolicyCommand("../../_examples/decision/maintenance.yml", "Replace", 100)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(reports))
+	assert.NotNil(t, solution)
+	assert.True(t, solution.Converged)
+
+	reports, _, err = runPolicyCommand("../../_examples/decision/oil-permit.yml", "Do drill", 100)
+	assert.Nil(t, err)
+	assert.Equal(t, []bool{false, true}, reports[0].Rows[1].Feasible)
+
+	_, _, err = runPolicyCommand("../../_examples/decision/oil.yml", "Oil", 100)
+	assert.NotNil(t, err)
+
+	_, _, err = runPolicyCommand("../../_examples/bbn/sprinkler.yml", "", 100)
+	assert.NotNil(t, err)
+
+	_, _, err = runPolicyCommand("../../_examples/decision/missing.yml", "", 100)
+	assert.NotNil(t, err)
+}
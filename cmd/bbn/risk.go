@@ -0,0 +1,85 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/mlange-42/bbn"
+	"github.com/mlange-42/bbn/internal/tui"
+	"github.com/spf13/cobra"
+)
+
+// riskCommand calculates risk profiles of decision alternatives.
+func riskCommand() *cobra.Command {
+	evidence := []string{}
+	var level float64
+
+	root := cobra.Command{
+		Use:   "risk file [decision]",
+		Short: "Calculates risk profiles, i.e. distributions of utility, for decision alternatives.",
+		Long: `Calculates risk profiles, i.e. distributions of utility, for decision alternatives.
+
+For each alternative of the given decision, reports mean, standard deviation and value at risk (VaR)
+of the total utility, as well as the risk profile and the cumulative risk profile.
+All other decisions follow their optimal policies.
+Further, reports stochastic dominance between alternatives.
+
+Without a decision, the risk profile under the optimal policies is reported.`,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		Args:          cobra.RangeArgs(1, 2),
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			decision := ""
+			if len(args) > 1 {
+				decision = args[1]
+			}
+			net, labels, profiles, err := runRiskCommand(args[0], decision, evidence)
+			if err != nil {
+				return err
+			}
+			fmt.Print(tui.FormatRiskProfiles(net, labels, profiles, level))
+			return nil
+		},
+	}
+	root.Flags().StringSliceVarP(&evidence, "evidence", "e", []string{}, "Evidence in the format:\n    k1=v1,k2=v2,k3=v3")
+	root.Flags().Float64VarP(&level, "level", "l", 0.05, "Probability level for the value at risk")
+
+	root.Flags().SortFlags = false
+
+	return &root
+}
+
+func runRiskCommand(path string, decision string, evidence []string) (*bbn.Network, []string, []bbn.RiskProfile, error) {
+	net, err := bbn.FromFile(path)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	ev, err := tui.ParseEvidence(evidence)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if _, err := net.SolvePolicies(true); err != nil {
+		return nil, nil, nil, err
+	}
+
+	if decision == "" {
+		profile, err := net.RiskProfile(ev, nil)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		return net, []string{"Policy"}, []bbn.RiskProfile{*profile}, nil
+	}
+
+	profiles, err := net.DecisionRiskProfiles(ev, decision)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	for _, v := range net.Variables() {
+		if v.Name == decision {
+			return net, v.Outcomes, profiles, nil
+		}
+	}
+	return nil, nil, nil, fmt.Errorf("decision variable %s not found", decision)
+}
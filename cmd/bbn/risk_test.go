@@ -0,0 +1,32 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunRiskCommand(t *testing.T) {
+	_, labels, profiles, err := runRiskCommand("../../_examples/decision/umbrella.yml", "Umbrella", []string{})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"Take", "Leave"}, labels)
+	assert.Len(t, profiles, 2)
+	assert.InDelta(t, 35, profiles[0].Mean(), 1e-9)
+
+	_, labels, profiles, err = runRiskCommand("../../_examples/decision/oil.yml", "", []string{"Test result=open"})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"Policy"}, labels)
+	assert.Len(t, profiles, 1)
+
+	_, _, _, err = runRiskCommand("../../_examples/decision/umbrella.yml", "Weather", []string{})
+	assert.NotNil(t, err)
+
+	_, _, _, err = runRiskCommand("../../_examples/decision/umbrella.yml", "", []string{"Weather"})
+	assert.NotNil(t, err)
+
+	_, _, _, err = runRiskCommand("../../_examples/bbn/sprinkler.yml", "", []string{})
+	assert.NotNil(t, err)
+
+	_, _, _, err = runRiskCommand("../../_examples/decision/missing.yml", "", []string{})
+	assert.NotNil(t, err)
+}
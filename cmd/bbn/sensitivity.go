@@ -0,0 +1,158 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/mlange-42/bbn"
+	"github.com/mlange-42/bbn/internal/tui"
+	"github.com/mlange-42/bbn/sensitivity"
+	"github.com/spf13/cobra"
+)
+
+const tornadoWidth = 30
+
+// sensitivityCommand performs one-way sensitivity analysis.
+func sensitivityCommand() *cobra.Command {
+	var query string
+	evidence := []string{}
+	var spread float64
+	var top int
+
+	root := cobra.Command{
+		Use:   "sensitivity file",
+		Short: "Performs one-way sensitivity analysis of table parameters.",
+		Long: `Performs one-way sensitivity analysis of table parameters.
+
+The target is the posterior probability of a query outcome (--query),
+or the total expected utility if no query is given.
+Each probability parameter of chance variables is varied in the range
+given by --range around its value, while the remaining parameters of
+the table row are co-varied proportionally.
+
+Parameters are ranked by their impact on the target, and shown as a tornado diagram.
+For decision networks, break-even values are reported where the optimal decisions change.`,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		Args:          cobra.ExactArgs(1),
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			result, err := runSensitivityCommand(args[0], query, evidence, spread, top)
+			if err != nil {
+				return err
+			}
+			printSensitivity(result)
+			return nil
+		},
+	}
+	root.Flags().StringVarP(&query, "query", "q", "", "Query outcome in the format k=v (default expected utility)")
+	root.Flags().StringSliceVarP(&evidence, "evidence", "e", []string{}, "Evidence in the format:\n    k1=v1,k2=v2,k3=v3")
+	root.Flags().Float64VarP(&spread, "range", "r", 1, "Range for varying parameters around their value")
+	root.Flags().IntVarP(&top, "top", "n", 10, "Number of parameters to show")
+
+	root.Flags().SortFlags = false
+
+	return &root
+}
+
+// sensitivityResult holds the results of the sensitivity command.
+type sensitivityResult struct {
+	Target    sensitivity.Target
+	Value     float64              // Value of the target.
+	Results   []sensitivity.Result // Parameters ranked by impact.
+	BreakEven [][]float64          // Break-even values for each result.
+}
+
+func runSensitivityCommand(path string, query string, evidence []string, spread float64, top int) (*sensitivityResult, error) {
+	net, err := bbn.FromFile(path)
+	if err != nil {
+		return nil, err
+	}
+	ev, err := tui.ParseEvidence(evidence)
+	if err != nil {
+		return nil, err
+	}
+	target := sensitivity.Target{Evidence: ev}
+	if query != "" {
+		q, err := tui.ParseEvidence([]string{query})
+		if err != nil {
+			return nil, err
+		}
+		for k, v := range q {
+			target.Variable, target.Outcome = k, v
+		}
+	}
+
+	value, results, err := sensitivity.Analyze(net, target, spread)
+	if err != nil {
+		return nil, err
+	}
+	if top > 0 && top < len(results) {
+		results = results[:top]
+	}
+	breakEven := make([][]float64, len(results))
+	for i, r := range results {
+		if breakEven[i], err = sensitivity.BreakEven(net, r.Parameter); err != nil {
+			return nil, err
+		}
+	}
+
+	return &sensitivityResult{
+		Target:    target,
+		Value:     value,
+		Results:   results,
+		BreakEven: breakEven,
+	}, nil
+}
+
+func printSensitivity(result *sensitivityResult) {
+	fmt.Printf("%s = %.4f\n\n", result.Target.String(), result.Value)
+
+	width := utf8.RuneCountInString("Parameter")
+	for i := range result.Results {
+		width = max(width, utf8.RuneCountInString(result.Results[i].Parameter.String()))
+	}
+	fmt.Printf("%-*s %7s %15s %21s\n", width, "Parameter", "Value", "Range", "Target")
+
+	maxImpact := 0.0
+	for i := range result.Results {
+		maxImpact = math.Max(maxImpact, result.Results[i].Impact())
+	}
+	for i := range result.Results {
+		r := &result.Results[i]
+		fmt.Printf("%-*s %7.3f   %5.3f - %5.3f   %8.4f - %8.4f  %s\n",
+			width, r.Parameter.String(), r.Parameter.Value, r.Low, r.High,
+			r.TargetLow, r.TargetHigh, tornadoBar(r, result.Value, maxImpact))
+	}
+
+	hasBreakEven := false
+	for i, values := range result.BreakEven {
+		if len(values) == 0 {
+			continue
+		}
+		if !hasBreakEven {
+			fmt.Printf("\nBreak-even values (optimal decisions change)\n")
+			hasBreakEven = true
+		}
+		str := make([]string, len(values))
+		for j, v := range values {
+			str[j] = fmt.Sprintf("%.4f", v)
+		}
+		fmt.Printf("%-*s %s\n", width, result.Results[i].Parameter.String(), strings.Join(str, ", "))
+	}
+}
+
+// tornadoBar draws a bar for the range of the target, relative to its value.
+func tornadoBar(r *sensitivity.Result, value float64, maxImpact float64) string {
+	if maxImpact == 0 || r.Impact() == 0 {
+		return strings.Repeat(" ", tornadoWidth) + "|"
+	}
+	low, high := math.Min(r.TargetLow, r.TargetHigh), math.Max(r.TargetLow, r.TargetHigh)
+	left := int(math.Round((value - low) / maxImpact * tornadoWidth))
+	right := int(math.Round((high - value) / maxImpact * tornadoWidth))
+	return strings.Repeat(" ", tornadoWidth-left) + strings.Repeat("#", left) + "|" + strings.Repeat("#", right)
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunSensitivityCommand(t *testing.T) {
+	result, err := runSensitivityCommand("../../_examples/bbn/sprinkler.yml", "Rain=yes", []string{"GrassWet=yes"}, 1, 5)
+	assert.Nil(t, err)
+	assert.InDelta(t, 0.5269, result.Value, 0.0001)
+	assert.Len(t, result.Results, 5)
+	assert.Len(t, result.BreakEven, 5)
+	assert.Nil(t, result.BreakEven[0])
+
+	result, err = runSensitivityCommand("../../_examples/decision/umbrella.yml", "", []string{}, 1, 0)
+	assert.Nil(t, err)
+	assert.Len(t, result.Results, 1+6)
+	found := false
+	for i, r := range result.Results {
+		if r.Parameter.String() == "P(Weather=Sunny)" {
+			assert.Len(t, result.BreakEven[i], 3)
+			found = true
+		}
+	}
+	assert.True(t, found)
+
+	_, err = runSensitivityCommand("../../_examples/bbn/sprinkler.yml", "Rain", []string{}, 1, 5)
+	assert.NotNil(t, err)
+
+	_, err = runSensitivityCommand("../../_examples/bbn/sprinkler.yml", "Rain=yes", []string{}, 0, 5)
+	assert.NotNil(t, err)
+
+	_, err = runSensitivityCommand("../../_examples/bbn/missing.yml", "Rain=yes", []string{}, 1, 5)
+	assert.NotNil(t, err)
+}
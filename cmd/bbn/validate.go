@@ -0,0 +1,63 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/mlange-42/bbn"
+	"github.com/spf13/cobra"
+)
+
+// validateCommand checks a network for structural problems.
+func validateCommand() *cobra.Command {
+	var all bool
+
+	root := cobra.Command{
+		Use:   "validate file",
+		Short: "Checks a network for structural problems.",
+		Long: `Checks a network for structural problems.
+
+Reports cycles, wrong table sizes, invalid probabilities, decisions that can't be ordered
+and utility nodes with children. Exits with an error if any problem of severity 'error' is found.`,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		Args:          cobra.ExactArgs(1),
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			problems, err := runValidateCommand(args[0])
+			if err != nil {
+				return err
+			}
+
+			for _, p := range problems {
+				if p.Severity == bbn.Info && !all {
+					continue
+				}
+				fmt.Println(p.String())
+			}
+			errors := problems.Count(bbn.Error)
+			if errors > 0 {
+				return fmt.Errorf("network has %d error(s)", errors)
+			}
+			if len(problems)-problems.Count(bbn.Info) == 0 {
+				fmt.Println("no problems found")
+			}
+			return nil
+		},
+	}
+
+	root.Flags().BoolVarP(&all, "all", "a", false, "Also show problems of severity 'info'")
+
+	root.Flags().SortFlags = false
+
+	return &root
+}
+
+func runValidateCommand(path string) (bbn.Problems, error) {
+	net, err := bbn.FromFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return net.Validate(), nil
+}
@@ -0,0 +1,18 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunValidateCommand(t *testing.T) {
+	problems, err := runValidateCommand("../../_examples/bbn/sprinkler.yml")
+	assert.Nil(t, err)
+	assert.Empty(t, problems)
+
+	problems, err = runValidateCommand("../../_examples/decision/robot.yml")
+	assert.Nil(t, err)
+	assert.False(t, problems.HasErrors())
+	assert.Equal(t, 1, len(problems))
+}
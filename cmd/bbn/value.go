@@ -0,0 +1,94 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/mlange-42/bbn"
+	"github.com/mlange-42/bbn/internal/tui"
+	"github.com/spf13/cobra"
+)
+
+// valueCommand calculates the value of information and of control for a chance variable.
+func valueCommand() *cobra.Command {
+	var sensitivity, specificity float64
+	var table []float64
+	var outcomes []string
+
+	root := cobra.Command{
+		Use:   "value file variable [decision]",
+		Short: "Calculates the value of information and of control for a chance variable.",
+		Long: `Calculates the value of information and of control for a chance variable.
+
+Reports the expected value of perfect information (EVPI), i.e. the expected utility gain
+if the variable is observed before the decision, and the value of control,
+i.e. the expected utility gain if the variable was a decision taken first.
+
+With a hypothetical imperfect test of the variable, also reports the expected value
+of sample information (EVSI), i.e. the expected utility gain if the test result is observed before the decision.
+For a variable with two outcomes, the test can be given by sensitivity and specificity,
+with a positive result for the first outcome.
+Otherwise, give a table with the probabilities of the test outcomes for each outcome of the variable.
+
+Without a decision, the first decision is used.`,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		Args:          cobra.RangeArgs(2, 3),
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			decision := ""
+			if len(args) > 2 {
+				decision = args[2]
+			}
+			test, err := parseTest(sensitivity, specificity, table, outcomes)
+			if err != nil {
+				return err
+			}
+			report, err := runValueCommand(args[0], args[1], decision, test)
+			if err != nil {
+				return err
+			}
+			fmt.Print(tui.FormatValueReport(report))
+			return nil
+		},
+	}
+	root.Flags().Float64VarP(&sensitivity, "sensitivity", "s", 0, "Sensitivity of a binary test, i.e. P(positive | first outcome)")
+	root.Flags().Float64VarP(&specificity, "specificity", "p", 0, "Specificity of a binary test, i.e. P(negative | second outcome)")
+	root.Flags().Float64SliceVarP(&table, "test-table", "t", nil, "Table of a test, with a row of test outcome probabilities per outcome of the variable")
+	root.Flags().StringSliceVarP(&outcomes, "test-outcomes", "o", nil, "Outcomes of a test given by a table")
+
+	root.Flags().SortFlags = false
+
+	return &root
+}
+
+// parseTest creates a test from sensitivity and specificity, or from a table and outcomes.
+// Returns nil if no test is given.
+func parseTest(sensitivity, specificity float64, table []float64, outcomes []string) (*bbn.Test, error) {
+	binary := sensitivity != 0 || specificity != 0
+	if binary && len(table) > 0 {
+		return nil, fmt.Errorf("a test can be given by either sensitivity and specificity, or by a table")
+	}
+	if len(table) > 0 {
+		if len(outcomes) == 0 {
+			return nil, fmt.Errorf("a test given by a table requires test outcomes")
+		}
+		return &bbn.Test{Outcomes: outcomes, Table: table}, nil
+	}
+	if !binary {
+		return nil, nil
+	}
+	if sensitivity <= 0 || sensitivity > 1 || specificity <= 0 || specificity > 1 {
+		return nil, fmt.Errorf("sensitivity and specificity are both required, in the range (0, 1]")
+	}
+	return bbn.NewBinaryTest(sensitivity, specificity), nil
+}
+
+func runValueCommand(path string, variable string, decision string, test *bbn.Test) (*bbn.ValueReport, error) {
+	net, err := bbn.FromFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return net.ValueOfInformation(variable, decision, test)
+}
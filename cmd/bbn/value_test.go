@@ -0,0 +1,49 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunValueCommand(t *testing.T) {
+	report, err := runValueCommand("../../_examples/decision/umbrella.yml", "Weather", "", nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "Umbrella", report.Decision)
+	assert.InDelta(t, 14, report.Perfect, 1e-9)
+
+	test, err := parseTest(0.9, 0.8, nil, nil)
+	assert.Nil(t, err)
+	report, err = runValueCommand("../../_examples/decision/oil.yml", "Oil", "Do drill", nil)
+	assert.Nil(t, err)
+	assert.Nil(t, report.Test)
+
+	_, err = runValueCommand("../../_examples/decision/oil.yml", "Oil", "Do drill", test)
+	assert.NotNil(t, err)
+
+	test, err = parseTest(0, 0, []float64{0.8, 0.2, 0.5, 0.5, 0.2, 0.8}, []string{"low", "high"})
+	assert.Nil(t, err)
+	report, err = runValueCommand("../../_examples/decision/oil.yml", "Oil", "Do drill", test)
+	assert.Nil(t, err)
+	assert.GreaterOrEqual(t, report.Perfect, report.Sample)
+
+	_, err = runValueCommand("../../_examples/decision/missing.yml", "Oil", "", nil)
+	assert.NotNil(t, err)
+}
+
+func TestParseTest(t *testing.T) {
+	test, err := parseTest(0, 0, nil, nil)
+	assert.Nil(t, err)
+	assert.Nil(t, test)
+
+	test, err = parseTest(0.9, 0.8, nil, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"positive", "negative"}, test.Outcomes)
+
+	_, err = parseTest(0.9, 0, nil, nil)
+	assert.NotNil(t, err)
+	_, err = parseTest(0.9, 0.8, []float64{1, 0}, []string{"a", "b"})
+	assert.NotNil(t, err)
+	_, err = parseTest(0, 0, []float64{1, 0}, nil)
+	assert.NotNil(t, err)
+}
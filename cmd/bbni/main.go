@@ -19,6 +19,7 @@ func main() {
 // rootCommand sets up the CLI for the TUI.
 func rootCommand() *cobra.Command {
 	evidence := []string{}
+	do := []string{}
 	var training string
 	var noData string
 	var delim string
@@ -38,17 +39,22 @@ func rootCommand() *cobra.Command {
 			if err != nil {
 				return err
 			}
+			doEv, err := tui.ParseEvidence(do)
+			if err != nil {
+				return err
+			}
 
 			delimRunes := []rune(delim)
 			if len(delimRunes) != 1 {
 				return fmt.Errorf("argument for --delim must be a single rune; got '%s'", delim)
 			}
 
-			a := tui.New(args[0], ev, training, noData, delimRunes[0])
+			a := tui.New(args[0], ev, doEv, training, noData, delimRunes[0])
 			return a.Run()
 		},
 	}
 	root.Flags().StringSliceVarP(&evidence, "evidence", "e", []string{}, "Evidence in the format:\n    k1=v1,k2=v2,k3=v3")
+	root.Flags().StringSliceVar(&do, "do", []string{}, "Interventions (do-operator) in the format:\n    k1=v1,k2=v2,k3=v3")
 	root.Flags().StringVarP(&training, "train", "t", "", "train the network from the given file")
 	root.Flags().StringVarP(&noData, "no-data", "n", "", "Value for missing data (default \"\")")
 	root.Flags().StringVarP(&delim, "delim", "d", ",", "CSV delimiter for training file")
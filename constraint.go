@@ -0,0 +1,136 @@
+package bbn
+
+import (
+	"slices"
+
+	"github.com/mlange-42/bbn/ve"
+)
+
+// checkAllowed checks the allowed outcomes of a variable, called from prepareVariables.
+func checkAllowed(v *Variable) error {
+	f := v.Factor
+	if f.Allowed == nil {
+		return nil
+	}
+	if v.NodeType != ve.DecisionNode {
+		return newVariableError(v.Name, ErrUnsupported, "allowed outcomes are only supported for decision variables, but %s is not a decision", v.Name)
+	}
+	rows := product(f.outcomes)
+	if len(f.Allowed) != rows*f.columns {
+		return newVariableError(v.Name, ErrTableShape,
+			"allowed outcomes of decision %s have %d entries, but %d are required", v.Name, len(f.Allowed), rows*f.columns)
+	}
+	for r := 0; r < rows; r++ {
+		if !slices.Contains(f.Allowed[r*f.columns:(r+1)*f.columns], true) {
+			return newVariableError(v.Name, ErrTableShape, "row %d of allowed outcomes of decision %s allows no outcome", r, v.Name)
+		}
+	}
+	return nil
+}
+
+// allowedOutcomes returns which outcomes of a decision are allowed, given outcomes of its declared parents.
+// Returns nil if the decision is not constrained, or if an outcome of a declared parent is missing.
+func (n *Network) allowedOutcomes(decision string, values map[string]string) []bool {
+	v, ok := n.variable(decision)
+	if !ok || v.Factor == nil || v.Factor.Allowed == nil {
+		return nil
+	}
+	indices := make([]int, len(v.Factor.Given))
+	for i, p := range v.Factor.Given {
+		value, ok := values[p]
+		if !ok {
+			return nil
+		}
+		pv, _ := n.variable(p)
+		if indices[i], ok = pv.Outcome(value); !ok {
+			return nil
+		}
+	}
+	row, ok, err := v.Factor.rowIndex(indices)
+	if !ok || err != nil {
+		return nil
+	}
+	start := row * v.Factor.columns
+	return v.Factor.Allowed[start : start+v.Factor.columns]
+}
+
+// policyAllowed returns the allowed outcomes of a decision in the layout of a policy with the given parents.
+// Returns nil if the decision is not constrained.
+func (n *Network) policyAllowed(decision string, given []string) []bool {
+	v, _ := n.variable(decision)
+	if v.Factor == nil || v.Factor.Allowed == nil {
+		return nil
+	}
+	outcomes := make([]int, len(given))
+	for i, p := range given {
+		pv, _ := n.variable(p)
+		outcomes[i] = len(pv.Outcomes)
+	}
+	indices := make([]int, len(given))
+	values := make(map[string]string, len(given))
+	allowed := make([]bool, 0, product(outcomes)*len(v.Outcomes))
+	for r := 0; r < product(outcomes); r++ {
+		for i, p := range given {
+			pv, _ := n.variable(p)
+			values[p] = pv.Outcomes[indices[i]]
+		}
+		row := n.allowedOutcomes(decision, values)
+		if row == nil {
+			// policy does not depend on all parents of the constraint
+			return nil
+		}
+		allowed = append(allowed, row...)
+		nextIndices(indices, outcomes)
+	}
+	return allowed
+}
+
+// constrainDecisions adds the allowed outcomes of unsolved decisions to a VE instance.
+func (n *Network) constrainDecisions(solver *ve.VE, varNames map[string]*variable) error {
+	for i := range n.variables {
+		v := &n.variables[i]
+		if v.Factor == nil || v.Factor.Allowed == nil {
+			continue
+		}
+		decision, ok := varNames[v.Name]
+		if !ok || decision.VeVariable.NodeType() != ve.DecisionNode {
+			continue
+		}
+		variables, err := givenVariables(v.Factor, varNames)
+		if err != nil {
+			return err
+		}
+		variables = append(variables, decision.VeVariable)
+
+		data := make([]float64, len(v.Factor.Allowed))
+		for j, a := range v.Factor.Allowed {
+			if a {
+				data[j] = 1
+			}
+		}
+		allowed := solver.Variables().CreateFactor(variables, data)
+		solver.Constrain(decision.VeVariable, &allowed)
+	}
+	return nil
+}
+
+// updateAllowed applies a table modification to allowed outcomes, represented as a table of ones and zeros.
+// Outcomes with a positive value in the modified table are allowed.
+// Returns nil if allowed is nil.
+func updateAllowed(allowed []bool, fn func(table []float64) []float64) []bool {
+	if allowed == nil {
+		return nil
+	}
+	table := make([]float64, len(allowed))
+	for i, a := range allowed {
+		if a {
+			table[i] = 1
+		}
+	}
+	table = fn(table)
+	result := make([]bool, len(table))
+	for i, v := range table {
+		result[i] = v > 0
+	}
+	return result
+}
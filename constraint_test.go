@@ -0,0 +1,198 @@
+package bbn_test
+
+import (
+	"testing"
+
+	"github.com/mlange-42/bbn"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNetworkConstraints(t *testing.T) {
+	net, err := bbn.FromFile("_examples/decision/oil-permit.yml")
+	assert.Nil(t, err)
+
+	policies, err := net.SolvePolicies(true)
+	assert.Nil(t, err)
+	assert.Equal(t, []float64{1, 0}, policies["Apply for permit"].Table)
+
+	drill := policies["Do drill"]
+	assert.Equal(t, []string{"Apply for permit", "Permit"}, drill.Given)
+	assert.Equal(t, []bool{true, true, false, true, true, true, false, true}, drill.Allowed)
+	assert.Equal(t, 0.0, drill.Table[2])
+	assert.Equal(t, 0.0, drill.Table[6])
+
+	utility, err := net.SolveUtility(nil, nil, "", false)
+	assert.Nil(t, err)
+	assert.InDelta(t, 0.8*20-5, utility.Data()[0], 1e-9)
+
+	reports, err := net.PolicyReports()
+	assert.Nil(t, err)
+	denied := reports[1].Rows[1]
+	assert.Equal(t, []bool{false, true}, denied.Feasible)
+	assert.Equal(t, "no", denied.Action)
+	assert.Nil(t, reports[0].Rows[0].Feasible)
+
+	tree, err := net.DecisionTree(map[string]string{"Permit": "denied"}, 0)
+	assert.Nil(t, err)
+	assert.Equal(t, "Apply for permit", tree.Variable)
+	assert.Equal(t, 1, len(tree.Branches[0].Node.Branches))
+	assert.Equal(t, "no", tree.Branches[0].Node.Branches[0].Outcome)
+
+	net.SetLIMID(true)
+	solution, err := net.SolveLIMID(100)
+	assert.Nil(t, err)
+	assert.True(t, solution.Converged)
+	assert.Equal(t, []float64{1, 0, 0, 1}, solution.Policies["Do drill"].Table)
+	assert.Equal(t, []bool{true, true, false, true}, solution.Policies["Do drill"].Allowed)
+
+	utility, err = net.SolveUtility(nil, nil, "", false)
+	assert.Nil(t, err)
+	assert.InDelta(t, 0.8*20-5, utility.Data()[0], 1e-9)
+}
+
+func TestBuilderConstraints(t *testing.T) {
+	builder := func(allowed []bool) *bbn.Builder {
+		return bbn.NewBuilder("Umbrella", "").
+			AddChance("Rain", "yes", "no").
+			AddChance("Forecast", "rain", "sun").
+			AddDecision("Umbrella", "yes", "no").
+			AddUtility("Wet").
+			AddEdge("Rain", "Forecast").
+			AddEdge("Forecast", "Umbrella").
+			AddEdge("Rain", "Wet").
+			AddEdge("Umbrella", "Wet").
+			SetTable("Rain", []float64{0.3, 0.7}).
+			SetTable("Forecast", []float64{0.8, 0.2, 0.1, 0.9}).
+			SetTable("Wet", []float64{-5, -100, -20, 0}).
+			SetAllowed("Umbrella", allowed)
+	}
+
+	net, err := builder([]bool{true, true, true, false}).Build()
+	assert.Nil(t, err)
+	policies, err := net.SolvePolicies(false)
+	assert.Nil(t, err)
+	assert.Equal(t, []float64{1, 0, 1, 0}, policies["Umbrella"].Table)
+
+	yml, err := bbn.ToYAML(net)
+	assert.Nil(t, err)
+	assert.Contains(t, string(yml), "allowed:")
+	net2, err := bbn.FromYAML(yml)
+	assert.Nil(t, err)
+	policies, err = net2.SolvePolicies(false)
+	assert.Nil(t, err)
+	assert.Equal(t, []float64{1, 0, 1, 0}, policies["Umbrella"].Table)
+
+	_, err = builder([]bool{true, true, true}).Build()
+	assert.ErrorIs(t, err, bbn.ErrTableShape)
+	_, err = builder([]bool{true, true, false, false}).Build()
+	assert.ErrorIs(t, err, bbn.ErrTableShape)
+
+	_, err = bbn.NewBuilder("Test", "").
+		AddChance("A", "yes", "no").
+		SetTable("A", []float64{0.5, 0.5}).
+		SetAllowed("A", []bool{true, false}).
+		Build()
+	assert.ErrorIs(t, err, bbn.ErrUnsupported)
+
+	_, err = bbn.NewBuilder("Test", "").SetAllowed("A", nil).Build()
+	assert.ErrorIs(t, err, bbn.ErrUnknownVariable)
+}
+
+func TestNetworkConstraintsMutation(t *testing.T) {
+	net, err := bbn.NewBuilder("Test", "").
+		AddChance("A", "a1", "a2").
+		AddChance("B", "b1", "b2").
+		AddDecision("D", "yes", "no").
+		AddEdge("A", "D").
+		SetTable("A", []float64{0.5, 0.5}).
+		SetTable("B", []float64{0.5, 0.5}).
+		SetAllowed("D", []bool{true, true, false, true}).
+		Build()
+	assert.Nil(t, err)
+
+	allowed := func() []bool {
+		for _, v := range net.Variables() {
+			if v.Name == "D" {
+				return v.Factor.Allowed
+			}
+		}
+		return nil
+	}
+
+	assert.Nil(t, net.AddEdge("B", "D"))
+	assert.Equal(t, []bool{true, true, true, true, false, true, false, true}, allowed())
+
+	assert.Nil(t, net.AddOutcome("A", "a3"))
+	assert.Equal(t, []bool{true, true, true, true, false, true, false, true, true, true, true, true}, allowed())
+
+	assert.Nil(t, net.RemoveOutcome("A", "a2"))
+	assert.Equal(t, []bool{true, true, true, true, true, true, true, true}, allowed())
+
+	assert.Nil(t, net.AddOutcome("D", "maybe"))
+	assert.Equal(t, 12, len(allowed()))
+	assert.True(t, allowed()[2])
+
+	assert.Nil(t, net.RemoveEdge("B", "D"))
+	assert.Equal(t, []bool{true, true, true, true, true, true}, allowed())
+
+	net, err = bbn.NewBuilder("Test", "").
+		AddChance("A", "a1", "a2").
+		AddDecision("D", "yes", "no").
+		AddEdge("A", "D").
+		SetTable("A", []float64{0.5, 0.5}).
+		SetAllowed("D", []bool{true, true, false, true}).
+		Build()
+	assert.Nil(t, err)
+	assert.ErrorIs(t, net.RemoveOutcome("D", "no"), bbn.ErrTableShape)
+	assert.Nil(t, net.RemoveEdge("A", "D"))
+	assert.Equal(t, []bool{true, true}, allowed())
+}
+
+func TestFromYAMLConstraints(t *testing.T) {
+	yml := `
+name: Test
+variables:
+- variable: D
+  type: decision
+  outcomes: [yes, no]
+  allowed:
+  - [true]
+`
+	_, err := bbn.FromYAML([]byte(yml))
+	assert.NotNil(t, err)
+}
+
+func TestNetworkConstraintsBins(t *testing.T) {
+	net, err := bbn.FromYAML([]byte(`
+name: Test
+variables:
+- variable: Depth
+  outcomes: [shallow, deep]
+  bins: [-inf, 100, inf]
+  table: [[0.5, 0.5]]
+- variable: Drill
+  type: decision
+  outcomes: ["yes", "no"]
+  given: [Depth]
+  allowed:
+  - [true, true]
+  - [false, true]
+- variable: Utility
+  type: utility
+  outcomes: [expected]
+  given: [Drill]
+  table: [[10], [0]]
+`))
+	assert.Nil(t, err)
+	_, err = net.SolvePolicies(true)
+	assert.Nil(t, err)
+
+	exp, err := net.ExplainDecision(map[string]string{"Depth": "150"}, "Drill")
+	assert.Nil(t, err)
+	assert.Equal(t, []bool{false, true}, exp.Feasible)
+	assert.Equal(t, "no", exp.Action)
+
+	exp, err = net.ExplainDecision(map[string]string{"Depth": "deep"}, "Drill")
+	assert.Nil(t, err)
+	assert.Equal(t, []bool{false, true}, exp.Feasible)
+}
@@ -0,0 +1,146 @@
+package bbn
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/mlange-42/bbn/ve"
+)
+
+// TwinSuffix is appended to variable names to name their counterfactual twins in a twin network.
+const TwinSuffix = "*"
+
+// TwinNetwork creates a twin network for counterfactual queries about interventions on the given variables.
+//
+// The twin network contains the original variables, representing the factual world,
+// and a twin for each intervened variable and each of its descendants, representing the counterfactual world.
+// Twins are named by appending [TwinSuffix]. Parents that are not affected by the interventions
+// are shared between both worlds. Particularly, root variables act as shared exogenous variables.
+//
+// Counterfactuals are exact if all uncertainty is in the shared variables,
+// i.e. if all twinned variables except the intervened ones are deterministic given their parents.
+// Otherwise, the twins' randomness is treated as independent of the factual world.
+//
+// Only networks without decision and utility variables are supported.
+func (n *Network) TwinNetwork(intervened ...string) (*Network, error) {
+	for i := range n.variables {
+		if n.variables[i].NodeType != ve.ChanceNode {
+			return nil, fmt.Errorf("counterfactuals are only supported for networks without decision and utility variables; found %s", n.variables[i].Name)
+		}
+	}
+	twins, err := n.twinSet(intervened)
+	if err != nil {
+		return nil, err
+	}
+
+	variables := make([]Variable, 0, len(n.variables)*2)
+	factors := make([]Factor, 0, len(n.factors)*2)
+	for i := range n.variables {
+		v := n.variables[i]
+		v.Outcomes = slices.Clone(v.Outcomes)
+		v.Factor = nil
+		variables = append(variables, v)
+	}
+	for _, f := range n.factors {
+		factors = append(factors, Factor{For: f.For, Given: slices.Clone(f.Given), Table: slices.Clone(f.Table), Noisy: f.Noisy, Allowed: slices.Clone(f.Allowed)})
+	}
+
+	for i := range n.variables {
+		if !twins[i] {
+			continue
+		}
+		v := n.variables[i]
+		v.Name += TwinSuffix
+		v.Outcomes = slices.Clone(v.Outcomes)
+		v.Factor = nil
+		variables = append(variables, v)
+
+		f := n.variables[i].Factor
+		if f == nil {
+			continue
+		}
+		given := make([]string, len(f.Given))
+		for j, p := range f.Given {
+			given[j] = n.twinName(p, twins)
+		}
+		factors = append(factors, Factor{For: v.Name, Given: given, Table: slices.Clone(f.Table), Noisy: f.Noisy, Allowed: slices.Clone(f.Allowed)})
+	}
+
+	twin, err := New(n.name, n.info, variables, factors)
+	if err != nil {
+		return nil, err
+	}
+	twin.limid = n.limid
+	return twin, nil
+}
+
+// SolveCounterfactual solves a counterfactual query, using a twin network (see [Network.TwinNetwork]).
+//
+// Answers the question: given the factual evidence, what would the query variables have been
+// under the interventions in do? Evidence is applied to the factual world,
+// while interventions and queries refer to the counterfactual world.
+//
+// Returns a map of normalized marginal probabilities for each query variable, by (original) variable name.
+func (n *Network) SolveCounterfactual(evidence map[string]string, do map[string]string, query []string) (map[string][]float64, error) {
+	intervened := make([]string, 0, len(do))
+	for name := range do {
+		intervened = append(intervened, name)
+	}
+	twin, err := n.TwinNetwork(intervened...)
+	if err != nil {
+		return nil, err
+	}
+	twins, err := n.twinSet(intervened)
+	if err != nil {
+		return nil, err
+	}
+
+	twinDo := make(map[string]string, len(do))
+	for name, value := range do {
+		twinDo[name+TwinSuffix] = value
+	}
+	twinQuery := make([]string, len(query))
+	for i, q := range query {
+		if _, err := n.variableIdx(q); err != nil {
+			return nil, err
+		}
+		twinQuery[i] = n.twinName(q, twins)
+	}
+
+	result, _, err := twin.SolveIntervention(twinDo, evidence, twinQuery, false)
+	if err != nil {
+		return nil, err
+	}
+	named := make(map[string][]float64, len(query))
+	for i, q := range query {
+		named[q] = result[twinQuery[i]]
+	}
+	return named, nil
+}
+
+// twinSet returns the set of indices of variables that require a counterfactual twin,
+// i.e. the intervened variables and their descendants.
+func (n *Network) twinSet(intervened []string) ([]bool, error) {
+	twins := make([]bool, len(n.variables))
+	for _, name := range intervened {
+		idx, err := n.variableIdx(name)
+		if err != nil {
+			return nil, err
+		}
+		twins[idx] = true
+		for i, desc := range n.descendantIndices(idx) {
+			twins[i] = twins[i] || desc
+		}
+	}
+	return twins, nil
+}
+
+// twinName returns the name of the counterfactual twin of a variable,
+// or the original name if the variable has no twin.
+func (n *Network) twinName(name string, twins []bool) string {
+	idx := slices.IndexFunc(n.variables, func(v Variable) bool { return v.Name == name })
+	if idx >= 0 && twins[idx] {
+		return name + TwinSuffix
+	}
+	return name
+}
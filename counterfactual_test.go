@@ -0,0 +1,76 @@
+package bbn_test
+
+import (
+	"testing"
+
+	"github.com/mlange-42/bbn"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNetworkTwinNetwork(t *testing.T) {
+	net, err := bbn.FromFile("_examples/bbn/firing-squad.yml")
+	assert.Nil(t, err)
+
+	twin, err := net.TwinNetwork("Rifleman A")
+	assert.Nil(t, err)
+	assert.Equal(t, 7, len(twin.Variables()))
+
+	parents, err := twin.Parents("Rifleman A*")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"Captain"}, parents)
+
+	parents, err = twin.Parents("Prisoner Dead*")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"Rifleman A*", "Rifleman B"}, parents)
+
+	_, err = net.TwinNetwork("X")
+	assert.ErrorIs(t, err, bbn.ErrUnknownVariable)
+
+	net, err = bbn.FromFile("_examples/decision/umbrella.yml")
+	assert.Nil(t, err)
+	_, err = net.TwinNetwork("Weather")
+	assert.NotNil(t, err)
+}
+
+func TestNetworkSolveCounterfactual(t *testing.T) {
+	net, err := bbn.FromFile("_examples/bbn/firing-squad.yml")
+	assert.Nil(t, err)
+
+	// The prisoner is dead. Would he be dead if rifleman A had not shot? Yes, B shot.
+	result, err := net.SolveCounterfactual(
+		map[string]string{"Prisoner Dead": "yes"},
+		map[string]string{"Rifleman A": "no"},
+		[]string{"Prisoner Dead", "Rifleman B", "Court Order"},
+	)
+	assert.Nil(t, err)
+	assert.InDeltaSlice(t, []float64{1, 0}, result["Prisoner Dead"], 1e-9)
+	assert.InDeltaSlice(t, []float64{1, 0}, result["Rifleman B"], 1e-9)
+	assert.InDeltaSlice(t, []float64{1, 0}, result["Court Order"], 1e-9)
+
+	// The prisoner is dead. Would he be dead if the captain had not signaled? No.
+	result, err = net.SolveCounterfactual(
+		map[string]string{"Prisoner Dead": "yes"},
+		map[string]string{"Captain": "no"},
+		[]string{"Prisoner Dead"},
+	)
+	assert.Nil(t, err)
+	assert.InDeltaSlice(t, []float64{0, 1}, result["Prisoner Dead"], 1e-9)
+
+	// The prisoner is alive. Would he be dead if rifleman A had shot? Yes.
+	result, err = net.SolveCounterfactual(
+		map[string]string{"Prisoner Dead": "no"},
+		map[string]string{"Rifleman A": "yes"},
+		[]string{"Prisoner Dead", "Rifleman B"},
+	)
+	assert.Nil(t, err)
+	assert.InDeltaSlice(t, []float64{1, 0}, result["Prisoner Dead"], 1e-9)
+	assert.InDeltaSlice(t, []float64{0, 1}, result["Rifleman B"], 1e-9)
+
+	// Without evidence, the counterfactual equals the interventional distribution.
+	result, err = net.SolveCounterfactual(nil, map[string]string{"Rifleman A": "no"}, []string{"Prisoner Dead"})
+	assert.Nil(t, err)
+	assert.InDeltaSlice(t, []float64{0.7, 0.3}, result["Prisoner Dead"], 1e-9)
+
+	_, err = net.SolveCounterfactual(nil, map[string]string{"Rifleman A": "no"}, []string{"X"})
+	assert.ErrorIs(t, err, bbn.ErrUnknownVariable)
+}
@@ -0,0 +1,470 @@
+package bbn
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/mlange-42/bbn/ve"
+)
+
+// previousSuffix marks variables of the previous time slice in a [DBN].
+const previousSuffix = "@t-1"
+
+// sliceWidth is the horizontal offset between time slices in unrolled networks, in terminal cells.
+const sliceWidth = 30
+
+// PreviousName returns the name of a variable in the previous time slice of a [DBN], like "Rain@t-1".
+func PreviousName(name string) string {
+	return name + previousSuffix
+}
+
+// SliceName returns the name of a variable at the given time step in an unrolled [DBN], like "Rain@3".
+func SliceName(name string, t int) string {
+	return name + "@" + strconv.Itoa(t)
+}
+
+// DBN is a dynamic Bayesian network, defined by a prior network for the first time step,
+// and a transition network for all further time steps.
+//
+// Both networks contain the same variables.
+// Additionally, the transition network contains variables of the previous time slice
+// that have children in the current slice, named like "Rain@t-1" (see [PreviousName]).
+// These must be root nodes, and their tables are ignored.
+//
+// Only chance variables are supported.
+type DBN struct {
+	prior      *Network
+	transition *Network
+	interfaces []string // Variables with children in the next time slice, in order of definition.
+}
+
+// NewDBN creates a new [DBN] from a prior and a transition network.
+//
+// Returns an error if the networks don't contain the same variables,
+// or if variables of the previous slice are invalid.
+func NewDBN(prior *Network, transition *Network) (*DBN, error) {
+	slice := map[string]*Variable{}
+	for i := range prior.variables {
+		v := &prior.variables[i]
+		if v.NodeType != ve.ChanceNode {
+			return nil, newVariableError(v.Name, ErrTableShape, "dynamic networks support only chance variables; got %s", v.Name)
+		}
+		if strings.HasSuffix(v.Name, previousSuffix) {
+			return nil, newVariableError(v.Name, ErrUnsupported, "prior network can't contain variables of the previous slice; got %s", v.Name)
+		}
+		slice[v.Name] = v
+	}
+
+	interfaces := []string{}
+	current := 0
+	for i := range transition.variables {
+		v := &transition.variables[i]
+		if name, ok := strings.CutSuffix(v.Name, previousSuffix); ok {
+			if err := checkPreviousVariable(v, slice[name]); err != nil {
+				return nil, err
+			}
+			interfaces = append(interfaces, name)
+			continue
+		}
+		other, ok := slice[v.Name]
+		if !ok {
+			return nil, newVariableError(v.Name, ErrUnknownVariable, "variable %s of transition network not in prior network", v.Name)
+		}
+		if v.NodeType != other.NodeType || !slices.Equal(v.Outcomes, other.Outcomes) {
+			return nil, newVariableError(v.Name, ErrTableShape, "variable %s differs between prior and transition network", v.Name)
+		}
+		current++
+	}
+	if current != len(prior.variables) {
+		return nil, fmt.Errorf("prior network has %d variables, but transition network %d", len(prior.variables), current)
+	}
+	// order interfaces as variables are defined
+	slices.SortStableFunc(interfaces, func(a, b string) int {
+		return slices.IndexFunc(prior.variables, func(v Variable) bool { return v.Name == a }) -
+			slices.IndexFunc(prior.variables, func(v Variable) bool { return v.Name == b })
+	})
+
+	return &DBN{
+		prior:      prior,
+		transition: transition,
+		interfaces: interfaces,
+	}, nil
+}
+
+// checkPreviousVariable checks a variable of the previous time slice in a transition network.
+func checkPreviousVariable(v *Variable, current *Variable) error {
+	if current == nil {
+		return newVariableError(v.Name, ErrUnknownVariable, "no variable for %s in the current time slice", v.Name)
+	}
+	if v.Factor != nil && len(v.Factor.Given) > 0 {
+		return newVariableError(v.Name, ErrTableShape, "variable %s of the previous time slice can't have parents", v.Name)
+	}
+	if v.NodeType != current.NodeType || !slices.Equal(v.Outcomes, current.Outcomes) {
+		return newVariableError(v.Name, ErrTableShape, "variable %s differs from %s", v.Name, current.Name)
+	}
+	return nil
+}
+
+// DBNFromFile reads a [DBN] from a YAML file. See [DBNFromYAML] for the format.
+func DBNFromFile(path string) (*DBN, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	d, err := DBNFromYAML(data)
+	if err != nil {
+		var pErr *ParseError
+		if errors.As(err, &pErr) {
+			pErr.File = path
+		}
+		return nil, err
+	}
+	return d, nil
+}
+
+// Name of the DBN.
+func (d *DBN) Name() string {
+	return d.transition.name
+}
+
+// Info text of the DBN.
+func (d *DBN) Info() string {
+	return d.transition.info
+}
+
+// Variables returns the variables of a single time slice.
+func (d *DBN) Variables() []Variable {
+	return d.prior.Variables()
+}
+
+// Prior returns the network for the first time step.
+func (d *DBN) Prior() *Network {
+	return d.prior
+}
+
+// Transition returns the network for further time steps.
+func (d *DBN) Transition() *Network {
+	return d.transition
+}
+
+// Unroll creates a plain [Network] for the given number of time steps.
+//
+// Variables are named by [SliceName], like "Rain@0", "Rain@1", ...
+func (d *DBN) Unroll(steps int) (*Network, error) {
+	if steps < 1 {
+		return nil, fmt.Errorf("number of steps must be at least 1; got %d", steps)
+	}
+	variables := []Variable{}
+	factors := []Factor{}
+	for t := 0; t < steps; t++ {
+		net := d.transition
+		if t == 0 {
+			net = d.prior
+		}
+		for _, v := range net.variables {
+			if strings.HasSuffix(v.Name, previousSuffix) {
+				continue
+			}
+			name := SliceName(v.Name, t)
+			variables = append(variables, Variable{
+				Name:     name,
+				NodeType: v.NodeType,
+				Outcomes: slices.Clone(v.Outcomes),
+				Bins:     slices.Clone(v.Bins),
+				Position: [2]int{v.Position[0] + t*sliceWidth, v.Position[1]},
+				Color:    v.Color,
+			})
+			if v.Factor != nil {
+				factors = append(factors, sliceFactor(v.Factor, name, t))
+			}
+		}
+	}
+	return New(d.Name(), d.Info(), variables, factors)
+}
+
+// sliceFactor creates a copy of a factor for the given time step of an unrolled network.
+func sliceFactor(f *Factor, name string, t int) Factor {
+	given := make([]string, len(f.Given))
+	for i, g := range f.Given {
+		if prev, ok := strings.CutSuffix(g, previousSuffix); ok {
+			given[i] = SliceName(prev, t-1)
+		} else {
+			given[i] = SliceName(g, t)
+		}
+	}
+	return Factor{
+		For:   name,
+		Given: given,
+		Table: slices.Clone(f.Table),
+		Noisy: f.Noisy,
+	}
+}
+
+// Filter performs forward filtering over a sequence of evidence, with one map per time step.
+//
+// Returns the marginals of all variables at each time step, given the evidence up to that step.
+// Uses an exact forward algorithm over the joint distribution of variables with children in the next time slice.
+func (d *DBN) Filter(evidence []map[string]string) ([]map[string][]float64, error) {
+	return d.forward(evidence, 0)
+}
+
+// Predict performs forward filtering over a sequence of evidence, and predicts the given number of further steps.
+//
+// Returns the marginals of all variables for each time step with evidence, followed by the predicted time steps.
+func (d *DBN) Predict(evidence []map[string]string, steps int) ([]map[string][]float64, error) {
+	return d.forward(evidence, steps)
+}
+
+// Smooth performs smoothing over a sequence of evidence, with one map per time step.
+//
+// Returns the marginals of all variables at each time step, given all evidence.
+// Uses inference in the unrolled network.
+func (d *DBN) Smooth(evidence []map[string]string) ([]map[string][]float64, error) {
+	if err := d.checkEvidence(evidence); err != nil {
+		return nil, err
+	}
+	if len(evidence) == 0 {
+		return []map[string][]float64{}, nil
+	}
+	net, err := d.Unroll(len(evidence))
+	if err != nil {
+		return nil, err
+	}
+	allEvidence := map[string]string{}
+	for t, ev := range evidence {
+		for k, v := range ev {
+			allEvidence[SliceName(k, t)] = v
+		}
+	}
+
+	result := make([]map[string][]float64, len(evidence))
+	for t, ev := range evidence {
+		name := func(v string) string { return SliceName(v, t) }
+		if result[t], err = d.sliceMarginals(net, allEvidence, ev, name); err != nil {
+			return nil, fmt.Errorf("time step %d: %w", t, err)
+		}
+	}
+	return result, nil
+}
+
+// checkEvidence checks that all evidence variables are variables of a time slice.
+func (d *DBN) checkEvidence(evidence []map[string]string) error {
+	for t, ev := range evidence {
+		for name := range ev {
+			if !slices.ContainsFunc(d.prior.variables, func(v Variable) bool { return v.Name == name }) {
+				return newVariableError(name, ErrUnknownVariable, "time step %d: evidence variable %s not found", t, name)
+			}
+		}
+	}
+	return nil
+}
+
+// forward performs forward filtering, and predicts the given number of further steps.
+func (d *DBN) forward(evidence []map[string]string, predict int) ([]map[string][]float64, error) {
+	if predict < 0 {
+		return nil, fmt.Errorf("number of steps to predict must not be negative; got %d", predict)
+	}
+	if err := d.checkEvidence(evidence); err != nil {
+		return nil, err
+	}
+	steps := len(evidence) + predict
+	result := make([]map[string][]float64, 0, steps)
+	var belief []float64
+	for t := 0; t < steps; t++ {
+		ev := map[string]string{}
+		if t < len(evidence) {
+			ev = evidence[t]
+		}
+		net := d.prior
+		if t > 0 {
+			var err error
+			if net, err = d.stepNetwork(belief); err != nil {
+				return nil, err
+			}
+		}
+		marginals, err := d.sliceMarginals(net, ev, ev, func(v string) string { return v })
+		if err != nil {
+			return nil, fmt.Errorf("time step %d: %w", t, err)
+		}
+		result = append(result, marginals)
+		if belief, err = d.interfaceBelief(net, ev); err != nil {
+			return nil, fmt.Errorf("time step %d: %w", t, err)
+		}
+	}
+	return result, nil
+}
+
+// sliceMarginals calculates the marginals of all variables of a time slice, with one query per variable.
+// A single query over all variables would create a joint factor that grows exponentially with the slice size.
+//
+// Argument evidence is the evidence in the network, and sliceEvidence the evidence of the time slice.
+// Argument name converts the name of a slice variable to its name in the network.
+func (d *DBN) sliceMarginals(net *Network, evidence map[string]string, sliceEvidence map[string]string, name func(string) string) (map[string][]float64, error) {
+	result := map[string][]float64{}
+	for _, v := range d.prior.variables {
+		if value, ok := sliceEvidence[v.Name]; ok {
+			probs, err := evidenceVector(&v, value)
+			if err != nil {
+				return nil, err
+			}
+			result[v.Name] = probs
+			continue
+		}
+		query := name(v.Name)
+		marginals, _, err := net.SolveQuery(evidence, []string{query}, false)
+		if err != nil {
+			return nil, err
+		}
+		result[v.Name] = marginals[query]
+	}
+	return result, nil
+}
+
+// interfaceBelief calculates the normalized joint distribution of the interface variables in the current time slice.
+// Variables are in the order of d.interfaces, with the last one varying fastest.
+func (d *DBN) interfaceBelief(net *Network, evidence map[string]string) ([]float64, error) {
+	outcomes := make([]int, len(d.interfaces))
+	observed := make([]int, len(d.interfaces))
+	query := []string{}
+	size := 1
+	for i, name := range d.interfaces {
+		v := d.prior.variables[slices.IndexFunc(d.prior.variables, func(v Variable) bool { return v.Name == name })]
+		outcomes[i] = len(v.Outcomes)
+		size *= outcomes[i]
+		observed[i] = -1
+		if value, ok := evidence[name]; ok {
+			idx, ok := v.Outcome(value)
+			if !ok {
+				return nil, newVariableError(name, ErrUnknownOutcome, "outcome %s for evidence variable %s not found", value, name)
+			}
+			observed[i] = idx
+			continue
+		}
+		query = append(query, name)
+	}
+
+	joint := []float64{1}
+	if len(query) > 0 {
+		_, f, err := net.SolveQuery(evidence, query, false)
+		if err != nil {
+			return nil, err
+		}
+		ordered, err := net.TryRearrange(f, query)
+		if err != nil {
+			return nil, err
+		}
+		joint = slices.Clone(ordered.Data())
+	}
+	if err := normalizeTable(joint, len(joint)); err != nil {
+		return nil, fmt.Errorf("evidence has zero probability")
+	}
+
+	// expand to all interface variables, with observed ones fixed
+	belief := make([]float64, size)
+	indices := make([]int, len(outcomes))
+	for i := range belief {
+		j, consistent := 0, true
+		for k, idx := range indices {
+			if observed[k] >= 0 {
+				consistent = consistent && idx == observed[k]
+				continue
+			}
+			j = j*outcomes[k] + idx
+		}
+		if consistent {
+			belief[i] = joint[j]
+		}
+		for k := len(indices) - 1; k >= 0; k-- {
+			indices[k]++
+			if indices[k] < outcomes[k] {
+				break
+			}
+			indices[k] = 0
+		}
+	}
+	return belief, nil
+}
+
+// stepNetwork creates a network for a time step, from the transition network
+// and the joint belief over interface variables of the previous time step.
+//
+// The belief is factorized into a chain of conditional tables over the previous slice variables.
+func (d *DBN) stepNetwork(belief []float64) (*Network, error) {
+	variables := slices.Clone(d.transition.variables)
+	factors := make([]Factor, 0, len(variables))
+	for i := range variables {
+		if f := variables[i].Factor; f != nil && !strings.HasSuffix(f.For, previousSuffix) {
+			factors = append(factors, Factor{For: f.For, Given: slices.Clone(f.Given), Table: slices.Clone(f.Table), Noisy: f.Noisy})
+		}
+		variables[i].Factor = nil
+	}
+
+	outcomes := make([]int, len(d.interfaces))
+	given := make([]string, len(d.interfaces))
+	for i, name := range d.interfaces {
+		idx := slices.IndexFunc(variables, func(v Variable) bool { return v.Name == PreviousName(name) })
+		outcomes[i] = len(variables[idx].Outcomes)
+		given[i] = PreviousName(name)
+	}
+
+	size := len(belief)
+	for i := len(d.interfaces) - 1; i >= 0; i-- {
+		// marginal over the first i+1 variables
+		stride := size
+		for _, o := range outcomes[:i+1] {
+			stride /= o
+		}
+		table := make([]float64, size/stride)
+		for j := range table {
+			for k := 0; k < stride; k++ {
+				table[j] += belief[j*stride+k]
+			}
+		}
+		if err := normalizeTable(table, outcomes[i]); err != nil {
+			return nil, err
+		}
+		factors = append(factors, Factor{For: given[i], Given: slices.Clone(given[:i]), Table: table})
+	}
+	return New(d.Name(), d.Info(), variables, factors)
+}
+
+// normalizeTable normalizes each row of a table with the given number of columns.
+// Rows with zero sum are set to uniform.
+// Returns an error if all rows have zero sum.
+func normalizeTable(table []float64, columns int) error {
+	nonZero := false
+	for i := 0; i < len(table); i += columns {
+		row := table[i : i+columns]
+		sum := 0.0
+		for _, v := range row {
+			sum += v
+		}
+		for j := range row {
+			if sum > 0 {
+				row[j] /= sum
+			} else {
+				row[j] = 1 / float64(columns)
+			}
+		}
+		nonZero = nonZero || sum > 0
+	}
+	if !nonZero {
+		return fmt.Errorf("table has zero sum")
+	}
+	return nil
+}
+
+// evidenceVector returns the point mass distribution of an outcome, see also [Network.ToEvidence].
+func evidenceVector(v *Variable, value string) ([]float64, error) {
+	idx, ok := v.Outcome(value)
+	if !ok {
+		return nil, newVariableError(v.Name, ErrUnknownOutcome, "outcome %s for evidence variable %s not found", value, v.Name)
+	}
+	probs := make([]float64, len(v.Outcomes))
+	probs[idx] = 1.0
+	return probs, nil
+}
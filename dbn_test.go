@@ -0,0 +1,189 @@
+package bbn_test
+
+import (
+	"testing"
+
+	"github.com/mlange-42/bbn"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDBNUmbrella(t *testing.T) {
+	dbn, err := bbn.DBNFromFile("_examples/dbn/umbrella.yml")
+	assert.Nil(t, err)
+	assert.Equal(t, "Umbrella world", dbn.Name())
+	assert.Len(t, dbn.Variables(), 2)
+	assert.Len(t, dbn.Transition().Variables(), 3)
+
+	evidence := []map[string]string{{"Umbrella": "yes"}, {"Umbrella": "yes"}}
+
+	// values from Russell & Norvig
+	filtered, err := dbn.Filter(evidence)
+	assert.Nil(t, err)
+	assert.InDelta(t, 0.818, filtered[0]["Rain"][0], 0.001)
+	assert.InDelta(t, 0.883, filtered[1]["Rain"][0], 0.001)
+	assert.Equal(t, []float64{1, 0}, filtered[1]["Umbrella"])
+
+	smoothed, err := dbn.Smooth(evidence)
+	assert.Nil(t, err)
+	assert.InDelta(t, 0.883, smoothed[0]["Rain"][0], 0.001)
+	assert.InDelta(t, 0.883, smoothed[1]["Rain"][0], 0.001)
+
+	predicted, err := dbn.Predict(evidence, 2)
+	assert.Nil(t, err)
+	assert.Len(t, predicted, 4)
+	assert.InDelta(t, 0.653, predicted[2]["Rain"][0], 0.001)
+	assert.InDelta(t, 0.561, predicted[3]["Rain"][0], 0.001)
+
+	filtered, err = dbn.Filter(nil)
+	assert.Nil(t, err)
+	assert.Empty(t, filtered)
+}
+
+func TestDBNUnroll(t *testing.T) {
+	dbn, err := bbn.DBNFromFile("_examples/dbn/monitoring.yml")
+	assert.Nil(t, err)
+
+	net, err := dbn.Unroll(3)
+	assert.Nil(t, err)
+	assert.Len(t, net.Variables(), 12)
+
+	vars := net.Variables()
+	assert.Equal(t, "Health@0", vars[1].Name)
+	assert.Empty(t, vars[1].Factor.Given)
+	assert.Equal(t, "Health@2", vars[9].Name)
+	assert.Equal(t, []string{"Health@1", "Load@1"}, vars[9].Factor.Given)
+	assert.Equal(t, vars[1].Position[0]+60, vars[9].Position[0])
+
+	_, err = dbn.Unroll(0)
+	assert.NotNil(t, err)
+}
+
+func TestDBNFilterMonitoring(t *testing.T) {
+	dbn, err := bbn.DBNFromFile("_examples/dbn/monitoring.yml")
+	assert.Nil(t, err)
+
+	evidence := []map[string]string{
+		{"Alarm": "no", "Throughput": "high"},
+		{"Throughput": "high"},
+		{"Alarm": "yes"},
+		{"Load": "high", "Alarm": "yes", "Throughput": "low"},
+		{},
+	}
+	filtered, err := dbn.Predict(evidence, 2)
+	assert.Nil(t, err)
+	smoothed, err := dbn.Smooth(evidence)
+	assert.Nil(t, err)
+
+	steps := len(evidence) + 2
+	net, err := dbn.Unroll(steps)
+	assert.Nil(t, err)
+
+	names := []string{"Load", "Health", "Alarm", "Throughput"}
+	for t0 := 0; t0 < steps; t0++ {
+		// evidence up to t0, and all evidence
+		partial, all := map[string]string{}, map[string]string{}
+		for t1, ev := range evidence {
+			for k, v := range ev {
+				all[bbn.SliceName(k, t1)] = v
+				if t1 <= t0 {
+					partial[bbn.SliceName(k, t1)] = v
+				}
+			}
+		}
+		for _, name := range names {
+			sliceName := bbn.SliceName(name, t0)
+			if _, ok := partial[sliceName]; ok {
+				continue
+			}
+			expected, _, err := net.SolveQuery(partial, []string{sliceName}, false)
+			assert.Nil(t, err)
+			assert.InDeltaSlice(t, expected[sliceName], filtered[t0][name], 1e-9, "filtered %s", sliceName)
+
+			if t0 >= len(evidence) {
+				continue
+			}
+			expected, _, err = net.SolveQuery(all, []string{sliceName}, false)
+			assert.Nil(t, err)
+			assert.InDeltaSlice(t, expected[sliceName], smoothed[t0][name], 1e-9, "smoothed %s", sliceName)
+		}
+	}
+}
+
+func TestDBNErrors(t *testing.T) {
+	dbn, err := bbn.DBNFromFile("_examples/dbn/umbrella.yml")
+	assert.Nil(t, err)
+
+	_, err = dbn.Filter([]map[string]string{{"Sun": "yes"}})
+	assert.ErrorIs(t, err, bbn.ErrUnknownVariable)
+	_, err = dbn.Smooth([]map[string]string{{"Sun": "yes"}})
+	assert.ErrorIs(t, err, bbn.ErrUnknownVariable)
+	_, err = dbn.Filter([]map[string]string{{"Rain": "maybe"}})
+	assert.ErrorIs(t, err, bbn.ErrUnknownOutcome)
+	_, err = dbn.Predict(nil, -1)
+	assert.NotNil(t, err)
+
+	header := `name: Test
+variables:
+- variable: A
+  given: [A@t-1]
+  outcomes: [yes, no]
+  table: [[1, 0], [0, 1]]
+`
+	tests := []struct {
+		yml  string
+		line int
+		err  string
+	}{
+		{"", 3, "requires a definition in 'prior'"},
+		{"prior:\n- variable: B\n  table: [[1, 1]]\n", 8, "unknown variable B"},
+		{"prior:\n- variable: A\n  table: [[1, 1]]\n- variable: A\n  table: [[1, 1]]\n", 10, "duplicate prior definition"},
+		{"prior:\n- variable: A\n  given: [A@t-1]\n  table: [[1, 0], [0, 1]]\n", 0, "parent variable A@t-1 of A not found"},
+		{"- variable: B\n  given: [C@t-1]\n  outcomes: [yes, no]\n  table: [[1, 0], [0, 1]]\nprior:\n- variable: A\n  table: [[1, 1]]\n- variable: B\n  table: [[1, 1]]\n", 0, "C@t-1"},
+	}
+	for _, tt := range tests {
+		_, err = bbn.DBNFromYAML([]byte(header + tt.yml))
+		assert.ErrorContains(t, err, tt.err, tt.yml)
+		if tt.line > 0 {
+			var parseErr *bbn.ParseError
+			assert.ErrorAs(t, err, &parseErr)
+			assert.Equal(t, tt.line, parseErr.Line, tt.yml)
+		}
+	}
+}
+
+func TestNewDBN(t *testing.T) {
+	prior, err := bbn.NewBuilder("Test", "").
+		AddChance("A", "yes", "no").
+		SetTable("A", []float64{0.5, 0.5}).
+		Build()
+	assert.Nil(t, err)
+
+	transition, err := bbn.NewBuilder("Test", "").
+		AddChance("A", "yes", "no").
+		AddChance(bbn.PreviousName("A"), "yes", "no").
+		AddEdge(bbn.PreviousName("A"), "A").
+		SetTable("A", []float64{1, 0, 0, 1}).
+		Build()
+	assert.Nil(t, err)
+
+	dbn, err := bbn.NewDBN(prior, transition)
+	assert.Nil(t, err)
+	result, err := dbn.Filter([]map[string]string{{"A": "yes"}, {}, {}})
+	assert.Nil(t, err)
+	assert.Equal(t, []float64{1, 0}, result[2]["A"])
+
+	other, err := bbn.NewBuilder("Test", "").
+		AddChance("A", "a", "b", "c").
+		SetTable("A", []float64{1, 1, 1}).
+		Build()
+	assert.Nil(t, err)
+	_, err = bbn.NewDBN(other, transition)
+	assert.ErrorIs(t, err, bbn.ErrTableShape)
+
+	decision, err := bbn.NewBuilder("Test", "").
+		AddDecision("A", "yes", "no").
+		Build()
+	assert.Nil(t, err)
+	_, err = bbn.NewDBN(decision, transition)
+	assert.ErrorIs(t, err, bbn.ErrTableShape)
+}
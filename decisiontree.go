@@ -0,0 +1,292 @@
+package bbn
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/mlange-42/bbn/ve"
+)
+
+// ErrTreeSize is returned when a decision tree exceeds the size limit.
+var ErrTreeSize = errors.New("decision tree exceeds size limit")
+
+// optimalTolerance is the tolerance for detecting ties between optimal decision alternatives.
+const optimalTolerance = 1e-9
+
+// TreeNodeType is the type of a [TreeNode].
+type TreeNodeType string
+
+const (
+	TreeDecision TreeNodeType = "decision" // Decision node.
+	TreeChance   TreeNodeType = "chance"   // Chance node, for an observed chance variable.
+	TreeLeaf     TreeNodeType = "leaf"     // Leaf node, with the expected utility of a path.
+)
+
+// TreeNode is a node of a decision tree. See [Network.DecisionTree].
+type TreeNode struct {
+	Type     TreeNodeType `json:"type"`
+	Variable string       `json:"variable,omitempty"` // Decision or chance variable. Empty for leaves.
+	Utility  float64      `json:"utility"`            // Expected utility at the node.
+	Branches []TreeBranch `json:"branches,omitempty"` // Branches, one per outcome of the variable.
+}
+
+// TreeBranch is a branch of a decision tree node, for an outcome of the node's variable.
+type TreeBranch struct {
+	Outcome     string    `json:"outcome"`
+	Probability float64   `json:"probability,omitempty"` // Conditional probability, for branches of chance nodes.
+	Optimal     bool      `json:"optimal,omitempty"`     // Whether the branch is an optimal alternative, for branches of decision nodes.
+	Node        *TreeNode `json:"node"`
+}
+
+// Size returns the number of nodes in the (sub-)tree.
+func (t *TreeNode) Size() int {
+	size := 1
+	for _, b := range t.Branches {
+		size += b.Node.Size()
+	}
+	return size
+}
+
+// DecisionTree derives the decision tree equivalent to the network's influence diagram.
+//
+// Decisions and the chance variables observed before them appear in information order,
+// i.e. in topological order of the decisions, each preceded by the parents of its policy.
+// Therefore, policies must have been solved by [Network.SolvePolicies] before.
+// Variables with evidence are not part of the tree.
+//
+// Branches of chance nodes with zero probability, and of infeasible decision alternatives, are pruned.
+// Returns an error wrapping [ErrTreeSize] if the tree has more than maxNodes nodes.
+// A maxNodes of zero or less means no limit.
+func (n *Network) DecisionTree(evidence map[string]string, maxNodes int) (*TreeNode, error) {
+	order, err := n.treeOrder(evidence)
+	if err != nil {
+		return nil, err
+	}
+	_, probs, err := n.SolveQuery(evidence, []string{}, true)
+	if err != nil {
+		return nil, err
+	}
+	if probs.Data()[0] <= 0 {
+		return nil, fmt.Errorf("evidence has zero probability")
+	}
+
+	path := make(map[string]string, len(evidence)+len(order))
+	for k, v := range evidence {
+		path[k] = v
+	}
+	b := treeBuilder{net: n, order: order, maxNodes: maxNodes}
+	return b.build(path, 0, probs.Data()[0])
+}
+
+// treeOrder returns the decisions and observed chance variables in information order.
+func (n *Network) treeOrder(evidence map[string]string) ([]*Variable, error) {
+	topological, err := n.TopologicalOrder()
+	if err != nil {
+		return nil, err
+	}
+	placed := map[string]bool{}
+	order := []*Variable{}
+	for _, name := range topological {
+		v, _ := n.variable(name)
+		if v.NodeType != ve.DecisionNode {
+			continue
+		}
+		if _, ok := evidence[name]; ok {
+			return nil, fmt.Errorf("decision variable %s can't be an evidence variable", name)
+		}
+		parents, err := n.policyParents(name)
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range topological {
+			if !parents[p] || placed[p] {
+				continue
+			}
+			if _, ok := evidence[p]; ok {
+				continue
+			}
+			pv, _ := n.variable(p)
+			if pv.NodeType == ve.DecisionNode {
+				return nil, fmt.Errorf("decision %s is observed by decision %s, but does not precede it", p, name)
+			}
+			order = append(order, pv)
+			placed[p] = true
+		}
+		order = append(order, v)
+		placed[name] = true
+	}
+	if len(order) == 0 {
+		return nil, fmt.Errorf("network has no decisions")
+	}
+	return order, nil
+}
+
+// policyParents returns the set of variables the policy of a decision depends on.
+func (n *Network) policyParents(decision string) (map[string]bool, error) {
+	policy, ok := n.policies[decision]
+	if !ok {
+		return nil, newVariableError(decision, ErrNoPolicy, "decision variable %s has no policy; solve policies first", decision)
+	}
+	parents := map[string]bool{}
+	for _, v := range policy.Variables() {
+		name := n.variables[v.Id()].Name
+		if name != decision {
+			parents[name] = true
+		}
+	}
+	return parents, nil
+}
+
+// treeBuilder builds a decision tree recursively.
+type treeBuilder struct {
+	net      *Network
+	order    []*Variable
+	nodes    int
+	maxNodes int
+}
+
+// build builds the sub-tree for the given path, and its probability.
+func (b *treeBuilder) build(path map[string]string, depth int, prob float64) (*TreeNode, error) {
+	b.nodes++
+	if b.maxNodes > 0 && b.nodes > b.maxNodes {
+		return nil, fmt.Errorf("%w of %d nodes", ErrTreeSize, b.maxNodes)
+	}
+	if depth == len(b.order) {
+		return b.leaf(path, prob)
+	}
+	if b.order[depth].NodeType == ve.DecisionNode {
+		return b.decision(path, depth, prob)
+	}
+	return b.chance(path, depth, prob)
+}
+
+// leaf creates a leaf node with the expected utility of the path.
+func (b *treeBuilder) leaf(path map[string]string, prob float64) (*TreeNode, error) {
+	utility, err := b.net.SolveUtility(path, []string{}, "", true)
+	if err != nil {
+		return nil, err
+	}
+	return &TreeNode{Type: TreeLeaf, Utility: utility.Data()[0] / prob}, nil
+}
+
+// decision creates a decision node, with a branch for each feasible alternative.
+func (b *treeBuilder) decision(path map[string]string, depth int, prob float64) (*TreeNode, error) {
+	v := b.order[depth]
+	node := TreeNode{Type: TreeDecision, Variable: v.Name, Utility: math.Inf(-1)}
+	allowed := b.net.allowedOutcomes(v.Name, path)
+	for i, outcome := range v.Outcomes {
+		if allowed != nil && !allowed[i] {
+			continue
+		}
+		path[v.Name] = outcome
+		child, err := b.build(path, depth+1, prob)
+		if err != nil {
+			return nil, err
+		}
+		node.Branches = append(node.Branches, TreeBranch{Outcome: outcome, Node: child})
+		node.Utility = max(node.Utility, child.Utility)
+	}
+	delete(path, v.Name)
+
+	for i := range node.Branches {
+		node.Branches[i].Optimal = node.Branches[i].Node.Utility >= node.Utility-optimalTolerance
+	}
+	return &node, nil
+}
+
+// chance creates a chance node, with a branch for each outcome of non-zero probability.
+func (b *treeBuilder) chance(path map[string]string, depth int, prob float64) (*TreeNode, error) {
+	v := b.order[depth]
+	_, f, err := b.net.SolveQuery(path, []string{v.Name}, true)
+	if err != nil {
+		return nil, err
+	}
+	node := TreeNode{Type: TreeChance, Variable: v.Name}
+	for i, outcome := range v.Outcomes {
+		p := f.Data()[i] / prob
+		if p <= 0 {
+			continue
+		}
+		path[v.Name] = outcome
+		child, err := b.build(path, depth+1, f.Data()[i])
+		if err != nil {
+			return nil, err
+		}
+		node.Branches = append(node.Branches, TreeBranch{Outcome: outcome, Probability: p, Node: child})
+		node.Utility += p * child.Utility
+	}
+	delete(path, v.Name)
+	return &node, nil
+}
+
+// Text returns an indented text representation of the (sub-)tree.
+// Optimal decision branches are marked by '*'.
+func (t *TreeNode) Text() string {
+	b := strings.Builder{}
+	t.writeText(&b, "", 0)
+	return b.String()
+}
+
+func (t *TreeNode) writeText(b *strings.Builder, prefix string, depth int) {
+	label := fmt.Sprintf("EU %.3f", t.Utility)
+	if t.Type != TreeLeaf {
+		label = fmt.Sprintf("%s %s: %s", t.Type, t.Variable, label)
+	}
+	fmt.Fprintf(b, "%s%s%s\n", strings.Repeat("  ", depth), prefix, label)
+	for i := range t.Branches {
+		t.Branches[i].Node.writeText(b, t.branchLabel(i)+": ", depth+1)
+	}
+}
+
+// branchLabel returns a label for a branch, with the probability of chance branches,
+// and optimal decision branches marked by '*'.
+func (t *TreeNode) branchLabel(index int) string {
+	br := &t.Branches[index]
+	if t.Type == TreeChance {
+		return fmt.Sprintf("%s (%.3f)", br.Outcome, br.Probability)
+	}
+	if br.Optimal {
+		return "*" + br.Outcome
+	}
+	return br.Outcome
+}
+
+// DOT returns a representation of the tree in the DOT language of Graphviz.
+// Decision nodes are drawn as boxes, chance nodes as ellipses, and optimal decision branches in bold.
+func (t *TreeNode) DOT() string {
+	b := strings.Builder{}
+	b.WriteString("digraph DecisionTree {\n  rankdir=LR;\n")
+	id := 0
+	t.writeDOT(&b, &id)
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// dotShapes are the shapes of node types in DOT output.
+var dotShapes = map[TreeNodeType]string{
+	TreeDecision: "box",
+	TreeChance:   "ellipse",
+	TreeLeaf:     "plaintext",
+}
+
+func (t *TreeNode) writeDOT(b *strings.Builder, id *int) int {
+	nodeID := *id
+	*id++
+	label := fmt.Sprintf("%s\nEU %.3f", t.Variable, t.Utility)
+	if t.Type == TreeLeaf {
+		label = fmt.Sprintf("%.3f", t.Utility)
+	}
+	fmt.Fprintf(b, "  n%d [shape=%s, label=%q];\n", nodeID, dotShapes[t.Type], label)
+
+	for i := range t.Branches {
+		childID := t.Branches[i].Node.writeDOT(b, id)
+		style := ""
+		if t.Branches[i].Optimal {
+			style = ", style=bold"
+		}
+		fmt.Fprintf(b, "  n%d -> n%d [label=%q%s];\n", nodeID, childID, t.branchLabel(i), style)
+	}
+	return nodeID
+}
@@ -0,0 +1,115 @@
+package bbn_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mlange-42/bbn"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNetworkDecisionTree(t *testing.T) {
+	net, err := bbn.FromFile("_examples/decision/umbrella.yml")
+	assert.Nil(t, err)
+
+	_, err = net.DecisionTree(nil, 0)
+	assert.ErrorIs(t, err, bbn.ErrNoPolicy)
+
+	_, err = net.SolvePolicies(true)
+	assert.Nil(t, err)
+
+	tree, err := net.DecisionTree(nil, 0)
+	assert.Nil(t, err)
+	assert.Equal(t, 10, tree.Size())
+
+	assert.Equal(t, bbn.TreeChance, tree.Type)
+	assert.Equal(t, "Forecast", tree.Variable)
+	assert.InDelta(t, 77, tree.Utility, 1e-9)
+	assert.Equal(t, 3, len(tree.Branches))
+	assert.InDelta(t, 0.7*0.7+0.3*0.15, tree.Branches[0].Probability, 1e-9)
+
+	rainy := tree.Branches[2].Node
+	assert.Equal(t, bbn.TreeDecision, rainy.Type)
+	assert.Equal(t, "Umbrella", rainy.Variable)
+	assert.True(t, rainy.Branches[0].Optimal)
+	assert.False(t, rainy.Branches[1].Optimal)
+	assert.Equal(t, bbn.TreeLeaf, rainy.Branches[0].Node.Type)
+	assert.Equal(t, rainy.Utility, rainy.Branches[0].Node.Utility)
+
+	tree, err = net.DecisionTree(map[string]string{"Forecast": "Rainy"}, 0)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, tree.Size())
+	assert.Equal(t, "Umbrella", tree.Variable)
+	assert.Equal(t, rainy.Utility, tree.Utility)
+
+	_, err = net.DecisionTree(nil, 5)
+	assert.ErrorIs(t, err, bbn.ErrTreeSize)
+
+	_, err = net.DecisionTree(map[string]string{"Umbrella": "Take"}, 0)
+	assert.NotNil(t, err)
+}
+
+func TestNetworkDecisionTreeSequential(t *testing.T) {
+	net, err := bbn.FromFile("_examples/decision/oil.yml")
+	assert.Nil(t, err)
+	_, err = net.SolvePolicies(true)
+	assert.Nil(t, err)
+
+	tree, err := net.DecisionTree(nil, 0)
+	assert.Nil(t, err)
+	assert.Equal(t, 21, tree.Size())
+	assert.Equal(t, "Do test drill", tree.Variable)
+	assert.InDelta(t, 22.5, tree.Utility, 1e-9)
+
+	test := tree.Branches[0].Node
+	assert.Equal(t, "Test result", test.Variable)
+	sum := 0.0
+	for _, b := range test.Branches {
+		sum += b.Probability
+	}
+	assert.InDelta(t, 1, sum, 1e-9)
+}
+
+func TestNetworkDecisionTreePruning(t *testing.T) {
+	net, err := bbn.NewBuilder("Umbrella", "").
+		AddChance("Forecast", "sunny", "rainy", "snow").
+		AddDecision("Umbrella", "yes", "no").
+		AddUtility("Wet").
+		AddEdge("Forecast", "Umbrella").
+		AddEdge("Forecast", "Wet").
+		AddEdge("Umbrella", "Wet").
+		SetTable("Forecast", []float64{0.7, 0.3, 0}).
+		SetTable("Wet", []float64{-5, 0, -5, -100, -5, -100}).
+		Build()
+	assert.Nil(t, err)
+	_, err = net.SolvePolicies(false)
+	assert.Nil(t, err)
+
+	tree, err := net.DecisionTree(nil, 0)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(tree.Branches))
+	assert.Equal(t, 7, tree.Size())
+	assert.InDelta(t, 0.3*-5, tree.Utility, 1e-9)
+}
+
+func TestDecisionTreeExport(t *testing.T) {
+	net, err := bbn.FromFile("_examples/decision/umbrella.yml")
+	assert.Nil(t, err)
+	_, err = net.SolvePolicies(true)
+	assert.Nil(t, err)
+	tree, err := net.DecisionTree(map[string]string{"Forecast": "Rainy"}, 0)
+	assert.Nil(t, err)
+
+	text := tree.Text()
+	assert.Equal(t, []string{
+		"decision Umbrella: EU 56.000",
+		"  *Take: EU 56.000",
+		"  Leave: EU 28.000",
+	}, strings.Split(strings.TrimSpace(text), "\n"))
+
+	dot := tree.DOT()
+	assert.True(t, strings.HasPrefix(dot, "digraph DecisionTree {"))
+	assert.Contains(t, dot, `n0 [shape=box, label="Umbrella\nEU 56.000"];`)
+	assert.Contains(t, dot, `n0 -> n1 [label="*Take", style=bold];`)
+	assert.Contains(t, dot, `n0 -> n2 [label="Leave"];`)
+}
@@ -0,0 +1,205 @@
+package bbn
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+)
+
+// EqualWidthBins proposes bin edges for the given values, with bins of equal width.
+//
+// The outer edges are -inf and inf, so that values outside the range of the data are covered.
+// Use [BinLabels] to derive outcome names.
+func EqualWidthBins(values []float64, bins int) ([]float64, error) {
+	if err := checkDiscretize(values, bins); err != nil {
+		return nil, err
+	}
+	lo, hi := slices.Min(values), slices.Max(values)
+	if lo == hi {
+		return []float64{math.Inf(-1), math.Inf(1)}, nil
+	}
+	edges := make([]float64, 0, bins+1)
+	edges = append(edges, math.Inf(-1))
+	width := (hi - lo) / float64(bins)
+	for i := 1; i < bins; i++ {
+		edges = append(edges, lo+float64(i)*width)
+	}
+	return append(edges, math.Inf(1)), nil
+}
+
+// EqualFrequencyBins proposes bin edges for the given values, with an equal number of values per bin.
+//
+// Edges are placed midway between neighbouring values. As bins can't split tied values,
+// edges are moved to the nearest position between distinct values, and fewer bins than requested may result.
+// The outer edges are -inf and inf.
+func EqualFrequencyBins(values []float64, bins int) ([]float64, error) {
+	if err := checkDiscretize(values, bins); err != nil {
+		return nil, err
+	}
+	sorted := slices.Clone(values)
+	slices.Sort(sorted)
+
+	edges := make([]float64, 0, bins+1)
+	edges = append(edges, math.Inf(-1))
+	for i := 1; i < bins; i++ {
+		pos, ok := nearestBoundary(sorted, i*len(sorted)/bins)
+		if !ok {
+			continue
+		}
+		cut := (sorted[pos-1] + sorted[pos]) / 2
+		if cut > edges[len(edges)-1] {
+			edges = append(edges, cut)
+		}
+	}
+	return append(edges, math.Inf(1)), nil
+}
+
+// nearestBoundary finds the position nearest to pos that lies between two distinct sorted values.
+func nearestBoundary(sorted []float64, pos int) (int, bool) {
+	for d := 0; d < len(sorted); d++ {
+		if p := pos - d; p > 0 && p < len(sorted) && sorted[p-1] != sorted[p] {
+			return p, true
+		}
+		if p := pos + d; p > 0 && p < len(sorted) && sorted[p-1] != sorted[p] {
+			return p, true
+		}
+	}
+	return 0, false
+}
+
+// MDLBins proposes bin edges for the given values, using the supervised method by Fayyad & Irani (1993).
+//
+// Values are split recursively at the cut point that minimizes the class entropy,
+// as long as the split is accepted by the minimum description length (MDL) criterion.
+// Argument classes contains the class label (e.g. the outcome of a target variable) of each value.
+// The outer edges are -inf and inf.
+func MDLBins(values []float64, classes []string) ([]float64, error) {
+	if err := checkDiscretize(values, 1); err != nil {
+		return nil, err
+	}
+	if len(classes) != len(values) {
+		return nil, fmt.Errorf("got %d values, but %d classes", len(values), len(classes))
+	}
+
+	classIDs := map[string]int{}
+	samples := make([]mdlSample, len(values))
+	for i, v := range values {
+		id, ok := classIDs[classes[i]]
+		if !ok {
+			id = len(classIDs)
+			classIDs[classes[i]] = id
+		}
+		samples[i] = mdlSample{value: v, class: id}
+	}
+	sort.Slice(samples, func(i, j int) bool { return samples[i].value < samples[j].value })
+
+	edges := []float64{math.Inf(-1)}
+	edges = mdlSplit(samples, len(classIDs), edges)
+	return append(edges, math.Inf(1)), nil
+}
+
+// mdlSample is a value with its class, for [MDLBins].
+type mdlSample struct {
+	value float64
+	class int
+}
+
+// mdlSplit recursively splits sorted samples, and appends cut points to edges.
+func mdlSplit(samples []mdlSample, classes int, edges []float64) []float64 {
+	total := classCounts(samples, classes)
+	cut, ok := bestCut(samples, classes, total)
+	if !ok {
+		return edges
+	}
+	left, right := samples[:cut], samples[cut:]
+	leftCounts, rightCounts := classCounts(left, classes), classCounts(right, classes)
+
+	n := float64(len(samples))
+	ent, entLeft, entRight := entropy(total), entropy(leftCounts), entropy(rightCounts)
+	gain := ent - (float64(len(left))*entLeft+float64(len(right))*entRight)/n
+
+	k, kLeft, kRight := nonZero(total), nonZero(leftCounts), nonZero(rightCounts)
+	delta := math.Log2(math.Pow(3, k)-2) - (k*ent - kLeft*entLeft - kRight*entRight)
+	if gain <= (math.Log2(n-1)+delta)/n {
+		return edges
+	}
+
+	edges = mdlSplit(left, classes, edges)
+	edges = append(edges, (left[len(left)-1].value+right[0].value)/2)
+	return mdlSplit(right, classes, edges)
+}
+
+// bestCut finds the cut position with minimal class entropy.
+// Returns false if there is no possible cut, i.e. if all values are equal.
+func bestCut(samples []mdlSample, classes int, total []int) (int, bool) {
+	left := make([]int, classes)
+	right := slices.Clone(total)
+	n := float64(len(samples))
+
+	best, bestEntropy := -1, math.Inf(1)
+	for i := 1; i < len(samples); i++ {
+		c := samples[i-1].class
+		left[c]++
+		right[c]--
+		if samples[i-1].value == samples[i].value {
+			continue
+		}
+		e := (float64(i)*entropy(left) + float64(len(samples)-i)*entropy(right)) / n
+		if e < bestEntropy {
+			best, bestEntropy = i, e
+		}
+	}
+	return best, best >= 0
+}
+
+func classCounts(samples []mdlSample, classes int) []int {
+	counts := make([]int, classes)
+	for _, s := range samples {
+		counts[s.class]++
+	}
+	return counts
+}
+
+// entropy calculates the entropy of class counts, in bits.
+func entropy(counts []int) float64 {
+	total := 0
+	for _, c := range counts {
+		total += c
+	}
+	e := 0.0
+	for _, c := range counts {
+		if c == 0 {
+			continue
+		}
+		p := float64(c) / float64(total)
+		e -= p * math.Log2(p)
+	}
+	return e
+}
+
+// nonZero counts the classes that occur in counts.
+func nonZero(counts []int) float64 {
+	k := 0
+	for _, c := range counts {
+		if c > 0 {
+			k++
+		}
+	}
+	return float64(k)
+}
+
+func checkDiscretize(values []float64, bins int) error {
+	if len(values) == 0 {
+		return fmt.Errorf("no values to discretize")
+	}
+	if bins < 1 {
+		return fmt.Errorf("number of bins must be at least 1; got %d", bins)
+	}
+	for _, v := range values {
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return fmt.Errorf("invalid value %f for discretization", v)
+		}
+	}
+	return nil
+}
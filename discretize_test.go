@@ -0,0 +1,67 @@
+package bbn_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/mlange-42/bbn"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEqualWidthBins(t *testing.T) {
+	bins, err := bbn.EqualWidthBins([]float64{3, 0, 6, 2, 1}, 3)
+	assert.Nil(t, err)
+	assert.Equal(t, []float64{math.Inf(-1), 2, 4, math.Inf(1)}, bins)
+
+	bins, err = bbn.EqualWidthBins([]float64{1, 1}, 3)
+	assert.Nil(t, err)
+	assert.Equal(t, []float64{math.Inf(-1), math.Inf(1)}, bins)
+
+	_, err = bbn.EqualWidthBins([]float64{}, 3)
+	assert.NotNil(t, err)
+	_, err = bbn.EqualWidthBins([]float64{1, 2}, 0)
+	assert.NotNil(t, err)
+	_, err = bbn.EqualWidthBins([]float64{1, math.NaN()}, 2)
+	assert.NotNil(t, err)
+}
+
+func TestEqualFrequencyBins(t *testing.T) {
+	bins, err := bbn.EqualFrequencyBins([]float64{6, 5, 4, 3, 2, 1}, 3)
+	assert.Nil(t, err)
+	assert.Equal(t, []float64{math.Inf(-1), 2.5, 4.5, math.Inf(1)}, bins)
+
+	bins, err = bbn.EqualFrequencyBins([]float64{1, 1, 1, 2, 3, 4}, 3)
+	assert.Nil(t, err)
+	assert.Equal(t, []float64{math.Inf(-1), 1.5, 2.5, math.Inf(1)}, bins)
+
+	bins, err = bbn.EqualFrequencyBins([]float64{1, 1, 1, 1, 1, 2}, 3)
+	assert.Nil(t, err)
+	assert.Equal(t, []float64{math.Inf(-1), 1.5, math.Inf(1)}, bins)
+}
+
+func TestMDLBins(t *testing.T) {
+	values := []float64{}
+	classes := []string{}
+	for i := 0; i < 60; i++ {
+		values = append(values, float64(i))
+		switch {
+		case i < 20:
+			classes = append(classes, "a")
+		case i < 40:
+			classes = append(classes, "b")
+		default:
+			classes = append(classes, "a")
+		}
+	}
+	bins, err := bbn.MDLBins(values, classes)
+	assert.Nil(t, err)
+	assert.Equal(t, []float64{math.Inf(-1), 19.5, 39.5, math.Inf(1)}, bins)
+
+	// no relation between values and classes
+	bins, err = bbn.MDLBins([]float64{1, 2, 3, 4}, []string{"a", "b", "a", "b"})
+	assert.Nil(t, err)
+	assert.Equal(t, []float64{math.Inf(-1), math.Inf(1)}, bins)
+
+	_, err = bbn.MDLBins([]float64{1, 2}, []string{"a"})
+	assert.NotNil(t, err)
+}
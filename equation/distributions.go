@@ -0,0 +1,187 @@
+package equation
+
+import (
+	"fmt"
+	"math"
+	"slices"
+)
+
+// distribution is a probability distribution over numeric values.
+type distribution interface {
+	// cdf returns the probability P(X <= x).
+	cdf(x float64) float64
+	// discrete returns whether the distribution is over integers.
+	discrete() bool
+}
+
+// distributionType creates a distribution from numeric arguments.
+type distributionType struct {
+	args   int
+	create func(args []float64) (distribution, error)
+}
+
+var distributions = map[string]distributionType{
+	"binomial": {2, newBinomial},
+	"poisson":  {1, newPoisson},
+	"normal":   {2, newNormal},
+}
+
+// probabilities fills a row with the probabilities of the child's outcomes under a distribution.
+// The row is not normalized.
+func probabilities(d distribution, child *Variable, values []float64, row []float64) {
+	if len(child.Bins) == len(child.Outcomes)+1 {
+		for i := range row {
+			row[i] = interval(d, child.Bins[i], child.Bins[i+1], i == len(row)-1)
+		}
+		return
+	}
+	if d.discrete() {
+		for i, v := range values {
+			if v == math.Floor(v) {
+				row[i] = d.cdf(v) - d.cdf(v-1)
+			}
+		}
+		return
+	}
+	// cells between the midpoints of sorted values
+	order := make([]int, len(values))
+	for i := range order {
+		order[i] = i
+	}
+	slices.SortStableFunc(order, func(a, b int) int {
+		return cmpFloat(values[a], values[b])
+	})
+	lo := math.Inf(-1)
+	for j, idx := range order {
+		hi := math.Inf(1)
+		if j < len(order)-1 {
+			hi = (values[idx] + values[order[j+1]]) / 2
+		}
+		row[idx] = interval(d, lo, hi, false)
+		lo = hi
+	}
+}
+
+// interval returns the probability of the interval [lo, hi), or [lo, hi] if closed.
+func interval(d distribution, lo, hi float64, closed bool) float64 {
+	if !d.discrete() {
+		return d.cdf(hi) - d.cdf(lo)
+	}
+	upper := math.Ceil(hi) - 1
+	if closed {
+		upper = math.Floor(hi)
+	}
+	return d.cdf(upper) - d.cdf(math.Ceil(lo)-1)
+}
+
+func cmpFloat(a, b float64) int {
+	if a < b {
+		return -1
+	}
+	if a > b {
+		return 1
+	}
+	return 0
+}
+
+// binomial distribution of the number of successes in n trials with success probability p.
+type binomial struct {
+	n int
+	p float64
+}
+
+func newBinomial(args []float64) (distribution, error) {
+	n, p := args[0], args[1]
+	if n < 0 || n != math.Floor(n) {
+		return nil, fmt.Errorf("number of trials must be a non-negative integer; got %f", n)
+	}
+	if p < 0 || p > 1 || math.IsNaN(p) {
+		return nil, fmt.Errorf("probability must be in range [0, 1]; got %f", p)
+	}
+	return &binomial{n: int(n), p: p}, nil
+}
+
+func (d *binomial) discrete() bool { return true }
+
+func (d *binomial) cdf(x float64) float64 {
+	if x < 0 {
+		return 0
+	}
+	if x >= float64(d.n) {
+		return 1
+	}
+	sum := 0.0
+	for k := 0; k <= int(math.Floor(x)); k++ {
+		sum += d.pmf(k)
+	}
+	return math.Min(sum, 1)
+}
+
+func (d *binomial) pmf(k int) float64 {
+	if d.p == 0 || d.p == 1 {
+		if k == int(d.p)*d.n {
+			return 1
+		}
+		return 0
+	}
+	lgN, _ := math.Lgamma(float64(d.n + 1))
+	lgK, _ := math.Lgamma(float64(k + 1))
+	lgNK, _ := math.Lgamma(float64(d.n - k + 1))
+	return math.Exp(lgN - lgK - lgNK + float64(k)*math.Log(d.p) + float64(d.n-k)*math.Log(1-d.p))
+}
+
+// poisson distribution with rate lambda.
+type poisson struct {
+	lambda float64
+}
+
+func newPoisson(args []float64) (distribution, error) {
+	if args[0] < 0 || math.IsNaN(args[0]) || math.IsInf(args[0], 0) {
+		return nil, fmt.Errorf("rate must be finite and non-negative; got %f", args[0])
+	}
+	return &poisson{lambda: args[0]}, nil
+}
+
+func (d *poisson) discrete() bool { return true }
+
+func (d *poisson) cdf(x float64) float64 {
+	if x < 0 {
+		return 0
+	}
+	if math.IsInf(x, 1) {
+		return 1
+	}
+	if d.lambda == 0 || x > d.lambda+20*math.Sqrt(d.lambda)+50 {
+		// remaining tail mass is negligible
+		return 1
+	}
+	sum := 0.0
+	logLambda := math.Log(d.lambda)
+	for k := 0; k <= int(math.Floor(x)); k++ {
+		lgK, _ := math.Lgamma(float64(k + 1))
+		sum += math.Exp(float64(k)*logLambda - d.lambda - lgK)
+	}
+	return math.Min(sum, 1)
+}
+
+// normal distribution with mean and standard deviation.
+type normal struct {
+	mean float64
+	sd   float64
+}
+
+func newNormal(args []float64) (distribution, error) {
+	if args[1] <= 0 || math.IsNaN(args[1]) || math.IsInf(args[1], 0) {
+		return nil, fmt.Errorf("standard deviation must be finite and positive; got %f", args[1])
+	}
+	if math.IsNaN(args[0]) || math.IsInf(args[0], 0) {
+		return nil, fmt.Errorf("mean must be finite; got %f", args[0])
+	}
+	return &normal{mean: args[0], sd: args[1]}, nil
+}
+
+func (d *normal) discrete() bool { return false }
+
+func (d *normal) cdf(x float64) float64 {
+	return 0.5 * math.Erfc(-(x-d.mean)/(d.sd*math.Sqrt2))
+}
@@ -0,0 +1,4 @@
+// Package equation provides arithmetic equations over parent variables, for generating CPTs.
+//
+// See [Equation] for the syntax and semantics.
+package equation
@@ -0,0 +1,239 @@
+package equation
+
+import (
+	"fmt"
+	"math"
+	"slices"
+
+	"github.com/mlange-42/bbn/ordinal"
+)
+
+// Equation is an arithmetic equation over parent variables, compiled by [Parse].
+// It defines a conditional probability distribution of a child variable.
+//
+// Variables are referred to by name, and evaluate to the numeric value of their outcome.
+// For variables with interval outcomes (bins), this is the midpoint of the interval,
+// or the finite edge for unbounded intervals. Otherwise, values are determined by [ordinal.Values].
+//
+// Equations support numbers, parentheses and the following operators, in order of increasing precedence:
+//   - comparisons <, <=, >, >=, ==, !=, evaluating to 1 or 0
+//   - addition + and subtraction -
+//   - multiplication * and division /
+//   - negation -
+//   - power ^, right-associative
+//
+// Further, the constants pi and e, as well as these functions are supported:
+// exp, log, sqrt, abs, floor, ceil, round, min, max, pow and if(condition, then, else).
+//
+// The result of an equation is interpreted in one of these ways:
+//   - A distribution over the child's outcome values, if the equation is a call to binomial(n, p),
+//     poisson(lambda) or normal(mean, sd). Discrete distributions give the probability mass at each outcome value,
+//     or within each interval for children with bins. The normal distribution is discretized over the intervals,
+//     or over cells between the midpoints of outcome values for children without bins.
+//   - An unnormalized weight of each of the child's outcomes, if the equation refers to the child itself,
+//     like "exp(-abs(Child - Parent))".
+//   - The probability of the first outcome of a child with two outcomes, otherwise,
+//     like "1 - exp(-0.1 * Age)".
+//
+// Rows of the resulting table are normalized.
+//
+// Names that contain spaces or other special characters can be quoted with single quotes,
+// like 'Machine Age'.
+type Equation struct {
+	source string
+	root   node
+}
+
+// Variable holds the information on a variable required to evaluate an [Equation].
+type Variable struct {
+	Name     string    // Name of the variable.
+	Outcomes []string  // Outcomes of the variable.
+	Bins     []float64 // Interval edges of the variable's outcomes, optional.
+}
+
+// SyntaxError is returned by [Parse] and [Equation.Table] for invalid equations.
+type SyntaxError struct {
+	Equation string // The erroneous equation.
+	Position int    // Position of the offending token in the equation, in characters starting at 0.
+	Token    string // The offending token. Empty at the end of the equation.
+	Message  string // Description of the error.
+}
+
+// Error implements the error interface.
+func (e *SyntaxError) Error() string {
+	if e.Token == "" {
+		return fmt.Sprintf("%s at end of equation \"%s\"", e.Message, e.Equation)
+	}
+	return fmt.Sprintf("%s at position %d ('%s') in equation \"%s\"", e.Message, e.Position+1, e.Token, e.Equation)
+}
+
+// Parse parses an equation. See [Equation] for the syntax.
+//
+// Returns a [*SyntaxError] for invalid equations.
+func Parse(expr string) (*Equation, error) {
+	tokens, err := tokenize(expr)
+	if err != nil {
+		return nil, err
+	}
+	p := parser{source: expr, tokens: tokens}
+	root, err := p.parseComparison()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != tokenEnd {
+		return nil, p.error(tok, "unexpected token")
+	}
+	if tok, ok := nestedDistribution(root, true); ok {
+		return nil, p.error(tok, "distributions are only allowed as the result of an equation")
+	}
+	return &Equation{source: expr, root: root}, nil
+}
+
+// Variables returns the names of all variables referenced by the equation, in order of first occurrence.
+func (e *Equation) Variables() []string {
+	names := []string{}
+	e.root.variables(&names)
+	return names
+}
+
+// String returns the source of the equation.
+func (e *Equation) String() string {
+	return e.source
+}
+
+// Table returns the CPT for the given child variable and its parents.
+//
+// Returns a [*SyntaxError] for unknown variables.
+func (e *Equation) Table(child Variable, parents []Variable) ([]float64, error) {
+	if len(child.Outcomes) == 0 {
+		return nil, fmt.Errorf("child variable %s has no outcomes", child.Name)
+	}
+	ctx := evalContext{
+		equation: e,
+		names:    make([]string, len(parents)+1),
+		values:   make([][]float64, len(parents)+1),
+		current:  make([]float64, len(parents)+1),
+	}
+	for i, p := range parents {
+		ctx.names[i] = p.Name
+		ctx.values[i] = Values(p)
+	}
+	ctx.names[len(parents)], ctx.values[len(parents)] = child.Name, Values(child)
+	if err := e.root.check(&ctx); err != nil {
+		return nil, err
+	}
+	weighted := slices.Contains(e.Variables(), child.Name)
+	if !weighted && !isDistribution(e.root) && len(child.Outcomes) != 2 {
+		return nil, fmt.Errorf("equation \"%s\" gives a probability, and requires a child with 2 outcomes; got %d", e.source, len(child.Outcomes))
+	}
+
+	rows := 1
+	for _, p := range parents {
+		rows *= len(p.Outcomes)
+	}
+	cols := len(child.Outcomes)
+	table := make([]float64, rows*cols)
+	indices := make([]int, len(parents))
+	for row := 0; row < rows; row++ {
+		for i, idx := range indices {
+			ctx.current[i] = ctx.values[i][idx]
+		}
+		if err := e.row(&ctx, &child, weighted, table[row*cols:(row+1)*cols]); err != nil {
+			return nil, fmt.Errorf("equation \"%s\" in row %d: %s", e.source, row, err.Error())
+		}
+		for i := len(indices) - 1; i >= 0; i-- {
+			indices[i]++
+			if indices[i] < len(parents[i].Outcomes) {
+				break
+			}
+			indices[i] = 0
+		}
+	}
+	return table, nil
+}
+
+// row evaluates the equation for a single row of the table, with parent values already set in the context.
+func (e *Equation) row(ctx *evalContext, child *Variable, weighted bool, row []float64) error {
+	childIndex := len(ctx.current) - 1
+	if weighted {
+		for i, v := range ctx.values[childIndex] {
+			ctx.current[childIndex] = v
+			w, err := e.root.eval(ctx)
+			if err != nil {
+				return err
+			}
+			if w.number < 0 || math.IsNaN(w.number) || math.IsInf(w.number, 0) {
+				return fmt.Errorf("weight must be finite and non-negative; got %f", w.number)
+			}
+			row[i] = w.number
+		}
+		return normalize(row)
+	}
+
+	v, err := e.root.eval(ctx)
+	if err != nil {
+		return err
+	}
+	if v.dist != nil {
+		probabilities(v.dist, child, ctx.values[childIndex], row)
+		return normalize(row)
+	}
+	if v.number < 0 || v.number > 1 || math.IsNaN(v.number) {
+		return fmt.Errorf("probability must be in range [0, 1]; got %f", v.number)
+	}
+	row[0], row[1] = v.number, 1-v.number
+	return nil
+}
+
+// Values returns the numeric values of a variable's outcomes.
+//
+// For variables with bins, these are the midpoints of the intervals, or the finite edge for unbounded intervals.
+// Otherwise, values are determined by [ordinal.Values].
+func Values(v Variable) []float64 {
+	if len(v.Bins) != len(v.Outcomes)+1 {
+		return ordinal.Values(v.Outcomes)
+	}
+	values := make([]float64, len(v.Outcomes))
+	for i := range values {
+		lo, hi := v.Bins[i], v.Bins[i+1]
+		switch {
+		case math.IsInf(lo, 0) && math.IsInf(hi, 0):
+			values[i] = 0
+		case math.IsInf(lo, 0):
+			values[i] = hi
+		case math.IsInf(hi, 0):
+			values[i] = lo
+		default:
+			values[i] = (lo + hi) / 2
+		}
+	}
+	return values
+}
+
+func normalize(row []float64) error {
+	sum := 0.0
+	for _, v := range row {
+		sum += v
+	}
+	if sum <= 0 {
+		return fmt.Errorf("all outcomes have zero probability")
+	}
+	for i := range row {
+		row[i] /= sum
+	}
+	return nil
+}
+
+// evalContext holds variable information for checking and evaluating equations.
+// The child variable is the last one.
+type evalContext struct {
+	equation *Equation
+	names    []string
+	values   [][]float64
+	current  []float64
+}
+
+// error creates a [*SyntaxError] for the given token.
+func (c *evalContext) error(tok token, format string, args ...any) error {
+	return &SyntaxError{Equation: c.equation.source, Position: tok.pos, Token: tok.text, Message: fmt.Sprintf(format, args...)}
+}
@@ -0,0 +1,179 @@
+package equation_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/mlange-42/bbn/equation"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		expr      string
+		variables []string
+	}{
+		{"1 - exp(-0.1 * Age)", []string{"Age"}},
+		{"2 ^ -x ^ 2 + 'Machine Age' / 1e-3", []string{"x", "Machine Age"}},
+		{"if(A >= 2, pi, e) * B", []string{"A", "B"}},
+		{"binomial(N, 0.5)", []string{"N"}},
+		{"max(A, min(B, C))", []string{"A", "B", "C"}},
+	}
+	for _, tt := range tests {
+		e, err := equation.Parse(tt.expr)
+		assert.Nil(t, err, tt.expr)
+		assert.Equal(t, tt.variables, e.Variables(), tt.expr)
+		assert.Equal(t, tt.expr, e.String())
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		expr     string
+		position int
+		message  string
+	}{
+		{"1 +", 3, "unexpected end of equation"},
+		{"(A + B", 6, "expected ')'"},
+		{"A B", 2, "unexpected token"},
+		{"A $ B", 2, "invalid character"},
+		{"'A + B", 0, "unterminated quote"},
+		{"foo(A)", 0, "unknown function"},
+		{"exp(A, B)", 0, "wrong number of arguments"},
+		{"1 - normal(A, 1)", 4, "distributions are only allowed"},
+		{"A < B < C", 6, "unexpected token"},
+		{"1..2", 0, "invalid number"},
+	}
+	for _, tt := range tests {
+		_, err := equation.Parse(tt.expr)
+		var syntaxErr *equation.SyntaxError
+		if assert.ErrorAs(t, err, &syntaxErr, tt.expr) {
+			assert.Equal(t, tt.position, syntaxErr.Position, tt.expr)
+			assert.Contains(t, syntaxErr.Message, tt.message, tt.expr)
+		}
+	}
+}
+
+func TestEquationProbability(t *testing.T) {
+	e, err := equation.Parse("1 - exp(-0.1 * Age)")
+	assert.Nil(t, err)
+
+	table, err := e.Table(
+		equation.Variable{Name: "Failure", Outcomes: []string{"yes", "no"}},
+		[]equation.Variable{{Name: "Age", Outcomes: []string{"0", "10", "20"}}},
+	)
+	assert.Nil(t, err)
+	assert.InDeltaSlice(t, []float64{
+		0, 1,
+		1 - math.Exp(-1), math.Exp(-1),
+		1 - math.Exp(-2), math.Exp(-2),
+	}, table, 1e-12)
+
+	// interval outcomes use midpoints, and finite edges for unbounded intervals
+	table, err = e.Table(
+		equation.Variable{Name: "Failure", Outcomes: []string{"yes", "no"}},
+		[]equation.Variable{{Name: "Age", Outcomes: []string{"a", "b"}, Bins: []float64{0, 10, math.Inf(1)}}},
+	)
+	assert.Nil(t, err)
+	assert.InDeltaSlice(t, []float64{
+		1 - math.Exp(-0.5), math.Exp(-0.5),
+		1 - math.Exp(-1), math.Exp(-1),
+	}, table, 1e-12)
+
+	_, err = e.Table(
+		equation.Variable{Name: "Failure", Outcomes: []string{"a", "b", "c"}},
+		[]equation.Variable{{Name: "Age", Outcomes: []string{"0", "10"}}},
+	)
+	assert.NotNil(t, err)
+
+	_, err = e.Table(
+		equation.Variable{Name: "Failure", Outcomes: []string{"yes", "no"}},
+		[]equation.Variable{{Name: "Age", Outcomes: []string{"-20", "0"}}},
+	)
+	assert.Contains(t, err.Error(), "range [0, 1]")
+
+	_, err = e.Table(
+		equation.Variable{Name: "Failure", Outcomes: []string{"yes", "no"}},
+		[]equation.Variable{{Name: "Weight", Outcomes: []string{"0", "10"}}},
+	)
+	var syntaxErr *equation.SyntaxError
+	assert.ErrorAs(t, err, &syntaxErr)
+	assert.Contains(t, err.Error(), "unknown variable Age")
+}
+
+func TestEquationWeights(t *testing.T) {
+	e, err := equation.Parse("if(Y == X, 2, 1)")
+	assert.Nil(t, err)
+
+	table, err := e.Table(
+		equation.Variable{Name: "Y", Outcomes: []string{"low", "mid", "high"}},
+		[]equation.Variable{{Name: "X", Outcomes: []string{"low", "mid"}}},
+	)
+	assert.Nil(t, err)
+	assert.InDeltaSlice(t, []float64{
+		0.5, 0.25, 0.25,
+		0.25, 0.5, 0.25,
+	}, table, 1e-12)
+
+	e, err = equation.Parse("Y - 1")
+	assert.Nil(t, err)
+	_, err = e.Table(equation.Variable{Name: "Y", Outcomes: []string{"0", "1"}}, nil)
+	assert.Contains(t, err.Error(), "non-negative")
+
+	e, err = equation.Parse("0 * Y")
+	assert.Nil(t, err)
+	_, err = e.Table(equation.Variable{Name: "Y", Outcomes: []string{"0", "1"}}, nil)
+	assert.Contains(t, err.Error(), "zero probability")
+}
+
+func TestEquationDistributions(t *testing.T) {
+	count := []string{"0", "1", "2", "3"}
+
+	e, err := equation.Parse("binomial(N, 0.5)")
+	assert.Nil(t, err)
+	table, err := e.Table(
+		equation.Variable{Name: "K", Outcomes: count},
+		[]equation.Variable{{Name: "N", Outcomes: []string{"1", "3"}}},
+	)
+	assert.Nil(t, err)
+	assert.InDeltaSlice(t, []float64{
+		0.5, 0.5, 0, 0,
+		0.125, 0.375, 0.375, 0.125,
+	}, table, 1e-12)
+
+	// truncated, normalized
+	e, err = equation.Parse("poisson(1)")
+	assert.Nil(t, err)
+	table, err = e.Table(equation.Variable{Name: "K", Outcomes: []string{"0", "1"}}, nil)
+	assert.Nil(t, err)
+	assert.InDeltaSlice(t, []float64{0.5, 0.5}, table, 1e-12)
+
+	// intervals, with last one closed
+	table, err = e.Table(equation.Variable{Name: "K", Outcomes: []string{"0..1", "1..3"}, Bins: []float64{0, 1, 3}}, nil)
+	assert.Nil(t, err)
+	p0, p1, p2, p3 := math.Exp(-1), math.Exp(-1), math.Exp(-1)/2, math.Exp(-1)/6
+	assert.InDeltaSlice(t, []float64{p0 / (p0 + p1 + p2 + p3), (p1 + p2 + p3) / (p0 + p1 + p2 + p3)}, table, 1e-12)
+
+	e, err = equation.Parse("normal(X, 1)")
+	assert.Nil(t, err)
+	table, err = e.Table(
+		equation.Variable{Name: "Y", Outcomes: []string{"low", "high"}, Bins: []float64{math.Inf(-1), 0, math.Inf(1)}},
+		[]equation.Variable{{Name: "X", Outcomes: []string{"0"}}},
+	)
+	assert.Nil(t, err)
+	assert.InDeltaSlice(t, []float64{0.5, 0.5}, table, 1e-12)
+
+	// cells between midpoints of unsorted values
+	table, err = e.Table(
+		equation.Variable{Name: "Y", Outcomes: []string{"2", "-2", "0"}},
+		[]equation.Variable{{Name: "X", Outcomes: []string{"0"}}},
+	)
+	assert.Nil(t, err)
+	tail := 0.5 * math.Erfc(1/math.Sqrt2)
+	assert.InDeltaSlice(t, []float64{tail, tail, 1 - 2*tail}, table, 1e-12)
+
+	e, err = equation.Parse("binomial(N, 1.5)")
+	assert.Nil(t, err)
+	_, err = e.Table(equation.Variable{Name: "K", Outcomes: count}, []equation.Variable{{Name: "N", Outcomes: []string{"1"}}})
+	assert.Contains(t, err.Error(), "binomial")
+}
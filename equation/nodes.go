@@ -0,0 +1,232 @@
+package equation
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+)
+
+// value is the result of evaluating a node, either a number or a distribution.
+type value struct {
+	number float64
+	dist   distribution
+}
+
+// node is a node in the syntax tree of an equation.
+type node interface {
+	check(ctx *evalContext) error
+	eval(ctx *evalContext) (value, error)
+	variables(names *[]string)
+}
+
+// numberNode is a numeric constant.
+type numberNode struct {
+	value float64
+}
+
+func (n *numberNode) check(ctx *evalContext) error         { return nil }
+func (n *numberNode) eval(ctx *evalContext) (value, error) { return value{number: n.value}, nil }
+func (n *numberNode) variables(names *[]string)            {}
+
+// nameNode is a reference to a variable, or a named constant.
+type nameNode struct {
+	name  token
+	index int
+}
+
+var constants = map[string]float64{
+	"pi": math.Pi,
+	"e":  math.E,
+}
+
+func (n *nameNode) check(ctx *evalContext) error {
+	n.index = slices.Index(ctx.names, n.name.text)
+	if n.index >= 0 {
+		return nil
+	}
+	if _, ok := constants[strings.ToLower(n.name.text)]; ok {
+		return nil
+	}
+	return ctx.error(n.name, "unknown variable %s; variables are %v", n.name.text, ctx.names)
+}
+
+func (n *nameNode) eval(ctx *evalContext) (value, error) {
+	if n.index < 0 {
+		return value{number: constants[strings.ToLower(n.name.text)]}, nil
+	}
+	return value{number: ctx.current[n.index]}, nil
+}
+
+func (n *nameNode) variables(names *[]string) {
+	if _, ok := constants[strings.ToLower(n.name.text)]; ok {
+		return
+	}
+	if !slices.Contains(*names, n.name.text) {
+		*names = append(*names, n.name.text)
+	}
+}
+
+// negateNode is an arithmetic negation.
+type negateNode struct {
+	operand node
+}
+
+func (n *negateNode) check(ctx *evalContext) error { return n.operand.check(ctx) }
+func (n *negateNode) variables(names *[]string)    { n.operand.variables(names) }
+
+func (n *negateNode) eval(ctx *evalContext) (value, error) {
+	v, err := n.operand.eval(ctx)
+	return value{number: -v.number}, err
+}
+
+// binaryNode is a binary arithmetic operation or comparison.
+type binaryNode struct {
+	op    token
+	left  node
+	right node
+}
+
+func (n *binaryNode) check(ctx *evalContext) error {
+	if err := n.left.check(ctx); err != nil {
+		return err
+	}
+	return n.right.check(ctx)
+}
+
+func (n *binaryNode) eval(ctx *evalContext) (value, error) {
+	a, err := n.left.eval(ctx)
+	if err != nil {
+		return value{}, err
+	}
+	b, err := n.right.eval(ctx)
+	if err != nil {
+		return value{}, err
+	}
+	return value{number: binaryOperators[n.op.text](a.number, b.number)}, nil
+}
+
+func (n *binaryNode) variables(names *[]string) {
+	n.left.variables(names)
+	n.right.variables(names)
+}
+
+var binaryOperators = map[string]func(a, b float64) float64{
+	"+":  func(a, b float64) float64 { return a + b },
+	"-":  func(a, b float64) float64 { return a - b },
+	"*":  func(a, b float64) float64 { return a * b },
+	"/":  func(a, b float64) float64 { return a / b },
+	"^":  math.Pow,
+	"<":  func(a, b float64) float64 { return boolToFloat(a < b) },
+	"<=": func(a, b float64) float64 { return boolToFloat(a <= b) },
+	">":  func(a, b float64) float64 { return boolToFloat(a > b) },
+	">=": func(a, b float64) float64 { return boolToFloat(a >= b) },
+	"==": func(a, b float64) float64 { return boolToFloat(a == b) },
+	"!=": func(a, b float64) float64 { return boolToFloat(a != b) },
+}
+
+func boolToFloat(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// function is a numeric function with a fixed number of arguments.
+type function struct {
+	args int
+	fn   func(args []float64) float64
+}
+
+var functions = map[string]function{
+	"exp":   {1, func(a []float64) float64 { return math.Exp(a[0]) }},
+	"log":   {1, func(a []float64) float64 { return math.Log(a[0]) }},
+	"sqrt":  {1, func(a []float64) float64 { return math.Sqrt(a[0]) }},
+	"abs":   {1, func(a []float64) float64 { return math.Abs(a[0]) }},
+	"floor": {1, func(a []float64) float64 { return math.Floor(a[0]) }},
+	"ceil":  {1, func(a []float64) float64 { return math.Ceil(a[0]) }},
+	"round": {1, func(a []float64) float64 { return math.Round(a[0]) }},
+	"min":   {2, func(a []float64) float64 { return math.Min(a[0], a[1]) }},
+	"max":   {2, func(a []float64) float64 { return math.Max(a[0], a[1]) }},
+	"pow":   {2, func(a []float64) float64 { return math.Pow(a[0], a[1]) }},
+	"if": {3, func(a []float64) float64 {
+		if a[0] != 0 {
+			return a[1]
+		}
+		return a[2]
+	}},
+}
+
+// callNode is a call of a function or distribution.
+type callNode struct {
+	name token
+	args []node
+}
+
+func (n *callNode) check(ctx *evalContext) error {
+	for _, a := range n.args {
+		if err := a.check(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (n *callNode) eval(ctx *evalContext) (value, error) {
+	args := make([]float64, len(n.args))
+	for i, a := range n.args {
+		v, err := a.eval(ctx)
+		if err != nil {
+			return value{}, err
+		}
+		args[i] = v.number
+	}
+	name := strings.ToLower(n.name.text)
+	if d, ok := distributions[name]; ok {
+		dist, err := d.create(args)
+		if err != nil {
+			return value{}, fmt.Errorf("%s: %s", name, err.Error())
+		}
+		return value{dist: dist}, nil
+	}
+	return value{number: functions[name].fn(args)}, nil
+}
+
+func (n *callNode) variables(names *[]string) {
+	for _, a := range n.args {
+		a.variables(names)
+	}
+}
+
+// isDistribution checks whether a node is a call of a distribution.
+func isDistribution(n node) bool {
+	call, ok := n.(*callNode)
+	if !ok {
+		return false
+	}
+	_, ok = distributions[strings.ToLower(call.name.text)]
+	return ok
+}
+
+// nestedDistribution returns the token of the first distribution that is not at the top level.
+func nestedDistribution(n node, top bool) (token, bool) {
+	if !top && isDistribution(n) {
+		return n.(*callNode).name, true
+	}
+	switch n := n.(type) {
+	case *negateNode:
+		return nestedDistribution(n.operand, false)
+	case *binaryNode:
+		if tok, ok := nestedDistribution(n.left, false); ok {
+			return tok, true
+		}
+		return nestedDistribution(n.right, false)
+	case *callNode:
+		for _, a := range n.args {
+			if tok, ok := nestedDistribution(a, false); ok {
+				return tok, true
+			}
+		}
+	}
+	return token{}, false
+}
@@ -0,0 +1,278 @@
+package equation
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+type tokenKind uint8
+
+const (
+	tokenEnd tokenKind = iota
+	tokenNumber
+	tokenName
+	tokenOperator
+	tokenOpen
+	tokenClose
+	tokenComma
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+var operators = []string{"<=", ">=", "==", "!=", "<", ">", "+", "-", "*", "/", "^"}
+
+// tokenize splits an equation into tokens.
+func tokenize(expr string) ([]token, error) {
+	runes := []rune(expr)
+	tokens := []token{}
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(' || r == ')' || r == ',':
+			kind := map[rune]tokenKind{'(': tokenOpen, ')': tokenClose, ',': tokenComma}[r]
+			tokens = append(tokens, token{kind: kind, text: string(r), pos: i})
+			i++
+		case r == '\'':
+			end := slices.Index(runes[i+1:], '\'')
+			if end < 0 {
+				return nil, &SyntaxError{Equation: expr, Position: i, Token: string(runes[i:]), Message: "unterminated quote"}
+			}
+			tokens = append(tokens, token{kind: tokenName, text: string(runes[i+1 : i+1+end]), pos: i})
+			i += end + 2
+		case unicode.IsDigit(r) || r == '.':
+			var tok token
+			tok, i = numberToken(runes, i)
+			tokens = append(tokens, tok)
+		case unicode.IsLetter(r) || r == '_':
+			var tok token
+			tok, i = nameToken(runes, i)
+			tokens = append(tokens, tok)
+		default:
+			op, ok := operatorAt(runes, i)
+			if !ok {
+				return nil, &SyntaxError{Equation: expr, Position: i, Token: string(r), Message: "invalid character"}
+			}
+			tokens = append(tokens, token{kind: tokenOperator, text: op, pos: i})
+			i += len(op)
+		}
+	}
+	return append(tokens, token{kind: tokenEnd, pos: len(runes)}), nil
+}
+
+// nameToken reads a name starting at position start.
+// Returns the token and the position after it.
+func nameToken(runes []rune, start int) (token, int) {
+	i := start
+	for i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]) || runes[i] == '_') {
+		i++
+	}
+	return token{kind: tokenName, text: string(runes[start:i]), pos: start}, i
+}
+
+// numberToken reads a number, with optional fraction and exponent, starting at position start.
+// Returns the token and the position after it.
+func numberToken(runes []rune, start int) (token, int) {
+	i := start
+	for i < len(runes) && (unicode.IsDigit(runes[i]) || runes[i] == '.') {
+		i++
+	}
+	if i < len(runes) && (runes[i] == 'e' || runes[i] == 'E') {
+		j := i + 1
+		if j < len(runes) && (runes[j] == '+' || runes[j] == '-') {
+			j++
+		}
+		if j < len(runes) && unicode.IsDigit(runes[j]) {
+			i = j
+			for i < len(runes) && unicode.IsDigit(runes[i]) {
+				i++
+			}
+		}
+	}
+	return token{kind: tokenNumber, text: string(runes[start:i]), pos: start}, i
+}
+
+// operatorAt returns the operator starting at position i, if any.
+func operatorAt(runes []rune, i int) (string, bool) {
+	for _, op := range operators {
+		if strings.HasPrefix(string(runes[i:min(i+2, len(runes))]), op) {
+			return op, true
+		}
+	}
+	return "", false
+}
+
+// parser is a recursive descent parser for equations.
+type parser struct {
+	source string
+	tokens []token
+	pos    int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokenEnd {
+		p.pos++
+	}
+	return tok
+}
+
+// accept consumes the next token if it is one of the given operators.
+func (p *parser) accept(ops ...string) (token, bool) {
+	if tok := p.peek(); tok.kind == tokenOperator && slices.Contains(ops, tok.text) {
+		p.pos++
+		return tok, true
+	}
+	return token{}, false
+}
+
+func (p *parser) error(tok token, message string) error {
+	return &SyntaxError{Equation: p.source, Position: tok.pos, Token: tok.text, Message: message}
+}
+
+// parseComparison parses a non-associative comparison.
+func (p *parser) parseComparison() (node, error) {
+	left, err := p.parseAdditive()
+	if err != nil {
+		return nil, err
+	}
+	op, ok := p.accept("<", "<=", ">", ">=", "==", "!=")
+	if !ok {
+		return left, nil
+	}
+	right, err := p.parseAdditive()
+	if err != nil {
+		return nil, err
+	}
+	return &binaryNode{op: op, left: left, right: right}, nil
+}
+
+func (p *parser) parseAdditive() (node, error) {
+	return p.parseBinary(p.parseMultiplicative, "+", "-")
+}
+
+func (p *parser) parseMultiplicative() (node, error) {
+	return p.parseBinary(p.parseUnary, "*", "/")
+}
+
+// parseBinary parses a left-associative chain of binary operators.
+func (p *parser) parseBinary(operand func() (node, error), ops ...string) (node, error) {
+	left, err := operand()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op, ok := p.accept(ops...)
+		if !ok {
+			return left, nil
+		}
+		right, err := operand()
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryNode{op: op, left: left, right: right}
+	}
+}
+
+func (p *parser) parseUnary() (node, error) {
+	if _, ok := p.accept("-"); ok {
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &negateNode{operand: operand}, nil
+	}
+	return p.parsePower()
+}
+
+// parsePower parses a right-associative power.
+func (p *parser) parsePower() (node, error) {
+	base, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	op, ok := p.accept("^")
+	if !ok {
+		return base, nil
+	}
+	exponent, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	return &binaryNode{op: op, left: base, right: exponent}, nil
+}
+
+func (p *parser) parsePrimary() (node, error) {
+	tok := p.next()
+	switch tok.kind {
+	case tokenOpen:
+		node, err := p.parseComparison()
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.next(); closing.kind != tokenClose {
+			return nil, p.error(closing, "expected ')'")
+		}
+		return node, nil
+	case tokenNumber:
+		v, err := strconv.ParseFloat(tok.text, 64)
+		if err != nil {
+			return nil, p.error(tok, "invalid number")
+		}
+		return &numberNode{value: v}, nil
+	case tokenName:
+		if p.peek().kind == tokenOpen {
+			return p.parseCall(tok)
+		}
+		return &nameNode{name: tok}, nil
+	case tokenEnd:
+		return nil, p.error(tok, "unexpected end of equation")
+	default:
+		return nil, p.error(tok, "expected number, variable, function or '('")
+	}
+}
+
+// parseCall parses the arguments of a function or distribution call.
+func (p *parser) parseCall(name token) (node, error) {
+	lower := strings.ToLower(name.text)
+	args := -1
+	if f, ok := functions[lower]; ok {
+		args = f.args
+	} else if d, ok := distributions[lower]; ok {
+		args = d.args
+	} else {
+		return nil, p.error(name, "unknown function")
+	}
+	p.pos++ // opening parenthesis
+
+	call := &callNode{name: name}
+	for {
+		arg, err := p.parseComparison()
+		if err != nil {
+			return nil, err
+		}
+		call.args = append(call.args, arg)
+		tok := p.next()
+		if tok.kind == tokenClose {
+			break
+		}
+		if tok.kind != tokenComma {
+			return nil, p.error(tok, "expected ',' or ')'")
+		}
+	}
+	if len(call.args) != args {
+		return nil, p.error(name, "wrong number of arguments; expected "+strconv.Itoa(args))
+	}
+	return call, nil
+}
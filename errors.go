@@ -0,0 +1,61 @@
+package bbn
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/mlange-42/bbn/ve"
+)
+
+// Sentinel errors, shared with package [ve].
+// Use [errors.Is] to check for them, and [errors.As] with [*VariableError]
+// to get the name of the affected variable.
+var (
+	ErrUnknownVariable = ve.ErrUnknownVariable              // A variable was not found.
+	ErrUnknownOutcome  = ve.ErrUnknownOutcome               // An outcome was not found.
+	ErrTableShape      = ve.ErrTableShape                   // A table or list of indices has the wrong size.
+	ErrDefinition      = errors.New("invalid definition")   // A variable definition, like a logic expression or an equation, is invalid.
+	ErrNoPolicy        = ve.ErrNoPolicy                     // A decision has no policy, or no policy can be derived.
+	ErrUnsupported     = errors.New("unsupported variable") // A variable exists, but its type or role is not supported by the operation.
+)
+
+// VariableError is an error related to a certain variable.
+// It wraps one of the sentinel errors, like [ErrUnknownVariable],
+// and optionally the error that caused it.
+type VariableError struct {
+	Variable string // Name of the affected variable.
+	Err      error  // The wrapped sentinel error.
+	cause    error
+	message  string
+}
+
+// newVariableError creates a new [VariableError].
+func newVariableError(variable string, err error, format string, args ...any) *VariableError {
+	return &VariableError{
+		Variable: variable,
+		Err:      err,
+		message:  fmt.Sprintf(format, args...),
+	}
+}
+
+// causedVariableError creates a new [VariableError] that also wraps the error that caused it.
+func causedVariableError(variable string, err error, cause error, format string, args ...any) *VariableError {
+	e := newVariableError(variable, err, format, args...)
+	e.cause = cause
+	return e
+}
+
+func (e *VariableError) Error() string {
+	if e.message == "" {
+		return fmt.Sprintf("%s: %s", e.Err.Error(), e.Variable)
+	}
+	return e.message
+}
+
+// Unwrap returns the wrapped sentinel error, and the error that caused it, if any.
+func (e *VariableError) Unwrap() []error {
+	if e.cause == nil {
+		return []error{e.Err}
+	}
+	return []error{e.Err, e.cause}
+}
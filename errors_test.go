@@ -0,0 +1,66 @@
+package bbn_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/mlange-42/bbn"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestErrors(t *testing.T) {
+	net, err := bbn.FromFile("_examples/bbn/sprinkler.yml")
+	assert.Nil(t, err)
+
+	_, _, err = net.SolveQuery(map[string]string{"Rain": "maybe"}, []string{"Sprinkler"}, false)
+	assert.ErrorIs(t, err, bbn.ErrUnknownOutcome)
+	assert.EqualError(t, err, "outcome maybe for evidence variable Rain not found")
+
+	_, _, err = net.SolveQuery(map[string]string{}, []string{"Snow"}, false)
+	assert.ErrorIs(t, err, bbn.ErrUnknownVariable)
+
+	var vErr *bbn.VariableError
+	assert.True(t, errors.As(err, &vErr))
+	assert.Equal(t, "Snow", vErr.Variable)
+
+	_, f, err := net.SolveQuery(map[string]string{}, []string{"Rain"}, false)
+	assert.Nil(t, err)
+
+	_, err = net.TryMarginal(f, "Snow")
+	assert.ErrorIs(t, err, bbn.ErrUnknownVariable)
+	_, err = net.TryMarginal(f, "Sprinkler")
+	assert.ErrorIs(t, err, bbn.ErrUnknownVariable)
+	m, err := net.TryMarginal(f, "Rain")
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(m.Data()))
+
+	_, err = net.TryRearrange(f, []string{"Sprinkler"})
+	assert.ErrorIs(t, err, bbn.ErrUnknownVariable)
+
+	_, err = net.ToEvidence("Rain", "maybe")
+	assert.ErrorIs(t, err, bbn.ErrUnknownOutcome)
+}
+
+func TestErrorTableShape(t *testing.T) {
+	vars := []bbn.Variable{
+		{Name: "A", Outcomes: []string{"yes", "no"}},
+		{Name: "B", Outcomes: []string{"yes", "no"}},
+	}
+	factors := []bbn.Factor{
+		{For: "A", Table: []float64{0.5, 0.5}},
+		{For: "B", Given: []string{"A"}, Table: []float64{0.5, 0.5}},
+	}
+
+	net, err := bbn.New("shape", "", vars, factors)
+	assert.Nil(t, err)
+
+	_, _, err = net.SolveQuery(map[string]string{}, []string{"B"}, false)
+	assert.ErrorIs(t, err, bbn.ErrTableShape)
+
+	var vErr *bbn.VariableError
+	assert.True(t, errors.As(err, &vErr))
+	assert.Equal(t, "B", vErr.Variable)
+
+	_, _, err = vars[1].Factor.TryRow([]int{0, 1})
+	assert.ErrorIs(t, err, bbn.ErrTableShape)
+}
@@ -0,0 +1,189 @@
+package bbn
+
+import (
+	"fmt"
+	"maps"
+	"math"
+	"slices"
+
+	"github.com/mlange-42/bbn/ve"
+)
+
+// Explanation of the impact of evidence on the posterior of a target variable.
+// See [Network.ExplainEvidence].
+type Explanation struct {
+	Target    string            // Name of the target variable.
+	Outcomes  []string          // Outcomes of the target variable.
+	Prior     []float64         // Target marginals without any evidence.
+	Posterior []float64         // Target marginals given all evidence.
+	Findings  []FindingImpact   // Impact of individual findings, by decreasing impact.
+	Conflict  float64           // Data conflict measure of the evidence. Positive values indicate conflicting evidence.
+	Evidence  map[string]string // The evidence.
+}
+
+// FindingImpact is the impact of a single finding on the posterior of a target variable.
+type FindingImpact struct {
+	Variable string    // Name of the observed variable.
+	Outcome  string    // Observed outcome, resolved from numeric values for binned variables.
+	Without  []float64 // Target marginals given all evidence except this finding.
+	// Impact as total variation distance between the target marginals with and without the finding.
+	Impact float64
+	// Log-likelihood ratio of the finding for each target outcome, given the remaining evidence.
+	// It is the change of the outcome's log-odds caused by the finding.
+	// Zero for deterministic outcomes, see Deterministic.
+	LogLikelihoodRatio []float64
+	// Whether the finding makes each target outcome certain or impossible.
+	// The log-likelihood ratio of such outcomes is infinite, and reported as zero.
+	Deterministic []bool
+}
+
+// ExplainEvidence explains the posterior of a target variable, given evidence.
+//
+// For each finding, it reports the target posterior when that finding is removed (leave-one-out),
+// and the finding's log-likelihood ratio for each target outcome, given the remaining evidence.
+// Further, it reports Jensen's data conflict measure log(P(e1)...P(en) / P(e)) for the whole evidence.
+//
+// Policies from [Network.SolvePolicies] are used for decisions.
+// Continuous variables are not supported as target or evidence.
+func (n *Network) ExplainEvidence(evidence map[string]string, target string) (*Explanation, error) {
+	if err := n.checkExplanation(evidence, target); err != nil {
+		return nil, err
+	}
+	prior, _, err := n.SolveQuery(map[string]string{}, []string{target}, false)
+	if err != nil {
+		return nil, err
+	}
+	posterior, _, err := n.SolveQuery(evidence, []string{target}, false)
+	if err != nil {
+		return nil, err
+	}
+	probEvidence, err := n.evidenceProbability(evidence)
+	if err != nil {
+		return nil, err
+	}
+
+	v, _ := n.variable(target)
+	result := Explanation{
+		Target:    target,
+		Outcomes:  v.Outcomes,
+		Prior:     prior[target],
+		Posterior: posterior[target],
+		Evidence:  evidence,
+	}
+	names := make([]string, 0, len(evidence))
+	for name := range evidence {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	conflict := -math.Log(probEvidence)
+	for _, name := range names {
+		finding, probFinding, err := n.explainFinding(evidence, name, target, result.Posterior)
+		if err != nil {
+			return nil, err
+		}
+		conflict += math.Log(probFinding)
+		result.Findings = append(result.Findings, finding)
+	}
+	if len(evidence) == 0 {
+		conflict = 0
+	}
+	result.Conflict = conflict
+
+	slices.SortStableFunc(result.Findings, func(a, b FindingImpact) int {
+		if a.Impact > b.Impact {
+			return -1
+		}
+		if a.Impact < b.Impact {
+			return 1
+		}
+		return 0
+	})
+	return &result, nil
+}
+
+// checkExplanation checks the arguments of [Network.ExplainEvidence].
+func (n *Network) checkExplanation(evidence map[string]string, target string) error {
+	v, ok := n.variable(target)
+	if !ok {
+		return newVariableError(target, ErrUnknownVariable, "target variable %s not found", target)
+	}
+	if v.NodeType == ve.ContinuousNode || v.NodeType == ve.UtilityNode {
+		return newVariableError(target, ErrUnsupported, "target variable %s must be discrete", target)
+	}
+	if _, ok := evidence[target]; ok {
+		return newVariableError(target, ErrUnsupported, "target variable %s can't be an evidence variable", target)
+	}
+	for name := range evidence {
+		v, ok := n.variable(name)
+		if !ok {
+			return newVariableError(name, ErrUnknownVariable, "evidence variable %s not found", name)
+		}
+		if v.NodeType == ve.ContinuousNode {
+			return newVariableError(name, ErrUnsupported, "continuous evidence variable %s is not supported", name)
+		}
+	}
+	return nil
+}
+
+// explainFinding calculates the impact of a single finding.
+// Further, it returns the prior probability of the finding.
+func (n *Network) explainFinding(evidence map[string]string, name string, target string, posterior []float64) (FindingImpact, float64, error) {
+	value := evidence[name]
+	others := maps.Clone(evidence)
+	delete(others, name)
+
+	without, _, err := n.SolveQuery(others, []string{target}, false)
+	if err != nil {
+		return FindingImpact{}, 0, err
+	}
+	prior, _, err := n.SolveQuery(map[string]string{}, []string{name}, false)
+	if err != nil {
+		return FindingImpact{}, 0, err
+	}
+	v, _ := n.variable(name)
+	idx, ok := v.Outcome(value)
+	if !ok {
+		return FindingImpact{}, 0, newVariableError(name, ErrUnknownOutcome, "outcome %s for evidence variable %s not found", value, name)
+	}
+
+	impact := 0.0
+	llr := make([]float64, len(posterior))
+	deterministic := make([]bool, len(posterior))
+	for i, p := range posterior {
+		q := without[target][i]
+		impact += math.Abs(p-q) / 2
+		if p == q {
+			continue
+		}
+		if p <= 0 || p >= 1 || q <= 0 || q >= 1 {
+			deterministic[i] = true
+			continue
+		}
+		llr[i] = logOdds(p) - logOdds(q)
+	}
+	return FindingImpact{
+		Variable:           name,
+		Outcome:            v.Outcomes[idx],
+		Without:            without[target],
+		Impact:             impact,
+		LogLikelihoodRatio: llr,
+		Deterministic:      deterministic,
+	}, prior[name][idx], nil
+}
+
+// evidenceProbability calculates the probability of the evidence.
+func (n *Network) evidenceProbability(evidence map[string]string) (float64, error) {
+	_, f, err := n.SolveQuery(evidence, []string{}, false)
+	if err != nil {
+		return 0, err
+	}
+	if len(f.Data()) != 1 {
+		return 0, fmt.Errorf("unexpected factor size %d for evidence probability", len(f.Data()))
+	}
+	return f.Data()[0], nil
+}
+
+func logOdds(p float64) float64 {
+	return math.Log(p) - math.Log(1-p)
+}
@@ -0,0 +1,117 @@
+package bbn_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/mlange-42/bbn"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNetworkExplainEvidence(t *testing.T) {
+	net, err := bbn.FromFile("_examples/bbn/sprinkler.yml")
+	assert.Nil(t, err)
+
+	evidence := map[string]string{"GrassWet": "yes", "Sprinkler": "yes"}
+	exp, err := net.ExplainEvidence(evidence, "Rain")
+	assert.Nil(t, err)
+
+	assert.Equal(t, "Rain", exp.Target)
+	assert.Equal(t, []string{"yes", "no"}, exp.Outcomes)
+	assert.InDelta(t, 0.2, exp.Prior[0], 1e-9)
+	assert.InDelta(t, 0.00198/0.14598, exp.Posterior[0], 1e-9)
+	assert.Len(t, exp.Findings, 2)
+
+	for _, f := range exp.Findings {
+		assert.InDelta(t, math.Abs(exp.Posterior[0]-f.Without[0]), f.Impact, 1e-9)
+		logOdds := math.Log(exp.Posterior[0]/exp.Posterior[1]) - math.Log(f.Without[0]/f.Without[1])
+		assert.InDelta(t, logOdds, f.LogLikelihoodRatio[0], 1e-9)
+		assert.InDelta(t, -logOdds, f.LogLikelihoodRatio[1], 1e-9)
+	}
+	// Sprinkler explains away rain.
+	assert.Equal(t, "Sprinkler", exp.Findings[0].Variable)
+	assert.InDelta(t, 0.16038/0.30438, exp.Findings[0].Without[0], 1e-9)
+	assert.Equal(t, "GrassWet", exp.Findings[1].Variable)
+	assert.InDelta(t, 0.002/0.162, exp.Findings[1].Without[0], 1e-9)
+
+	// log(P(GrassWet=yes) P(Sprinkler=yes) / P(GrassWet=yes, Sprinkler=yes))
+	assert.InDelta(t, math.Log(0.30438*0.162/0.14598), exp.Conflict, 1e-9)
+
+	exp, err = net.ExplainEvidence(map[string]string{}, "Rain")
+	assert.Nil(t, err)
+	assert.Equal(t, exp.Prior, exp.Posterior)
+	assert.Empty(t, exp.Findings)
+	assert.Equal(t, 0.0, exp.Conflict)
+}
+
+func TestNetworkExplainEvidenceConflict(t *testing.T) {
+	net, err := bbn.FromFile("_examples/bbn/sprinkler.yml")
+	assert.Nil(t, err)
+
+	exp, err := net.ExplainEvidence(map[string]string{"Rain": "no", "GrassWet": "yes"}, "Sprinkler")
+	assert.Nil(t, err)
+	// log(P(Rain=no) P(GrassWet=yes) / P(Rain=no, GrassWet=yes))
+	assert.InDelta(t, math.Log(0.8*0.30438/0.144), exp.Conflict, 1e-9)
+	assert.Greater(t, exp.Conflict, 0.0)
+}
+
+func TestNetworkExplainEvidenceErrors(t *testing.T) {
+	net, err := bbn.FromFile("_examples/bbn/sprinkler.yml")
+	assert.Nil(t, err)
+
+	_, err = net.ExplainEvidence(map[string]string{}, "Foo")
+	assert.ErrorIs(t, err, bbn.ErrUnknownVariable)
+
+	_, err = net.ExplainEvidence(map[string]string{"Foo": "yes"}, "Rain")
+	assert.ErrorIs(t, err, bbn.ErrUnknownVariable)
+
+	_, err = net.ExplainEvidence(map[string]string{"Rain": "yes"}, "Rain")
+	assert.ErrorIs(t, err, bbn.ErrUnsupported)
+
+	_, err = net.ExplainEvidence(map[string]string{"GrassWet": "maybe"}, "Rain")
+	assert.ErrorIs(t, err, bbn.ErrUnknownOutcome)
+}
+
+func TestNetworkExplainEvidenceBins(t *testing.T) {
+	net, err := bbn.FromFile("_examples/bbn/weather-untrained.yml")
+	assert.Nil(t, err)
+
+	numeric, err := net.ExplainEvidence(map[string]string{"Temperature": "18.5", "Humidity": "90"}, "Play")
+	assert.Nil(t, err)
+	labels, err := net.ExplainEvidence(map[string]string{"Temperature": "mild", "Humidity": "86.5..inf"}, "Play")
+	assert.Nil(t, err)
+	assert.Equal(t, labels.Findings, numeric.Findings)
+	assert.Equal(t, labels.Conflict, numeric.Conflict)
+}
+
+func TestNetworkExplainEvidenceDeterministic(t *testing.T) {
+	net, err := bbn.NewBuilder("Test", "").
+		AddChance("A", "yes", "no").
+		AddChance("B", "yes", "no").
+		AddChance("C", "yes", "no").
+		AddEdge("A", "B").
+		SetTable("A", []float64{0.5, 0.5}).
+		SetTable("B", []float64{1, 0, 0, 1}).
+		SetTable("C", []float64{0.5, 0.5}).
+		Build()
+	assert.Nil(t, err)
+
+	exp, err := net.ExplainEvidence(map[string]string{"B": "yes", "C": "no"}, "A")
+	assert.Nil(t, err)
+	assert.Equal(t, []float64{1, 0}, exp.Posterior)
+
+	finding := exp.Findings[0]
+	assert.Equal(t, "B", finding.Variable)
+	assert.Equal(t, []bool{true, true}, finding.Deterministic)
+	assert.Equal(t, []float64{0, 0}, finding.LogLikelihoodRatio)
+
+	finding = exp.Findings[1]
+	assert.Equal(t, "C", finding.Variable)
+	assert.Equal(t, []bool{false, false}, finding.Deterministic)
+	for _, f := range exp.Findings {
+		for _, llr := range f.LogLikelihoodRatio {
+			assert.False(t, math.IsInf(llr, 0) || math.IsNaN(llr))
+		}
+	}
+	assert.False(t, math.IsInf(exp.Conflict, 0) || math.IsNaN(exp.Conflict))
+}
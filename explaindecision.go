@@ -0,0 +1,280 @@
+package bbn
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"math"
+	"slices"
+
+	"github.com/mlange-42/bbn/ve"
+)
+
+// DecisionExplanation explains the optimal action of a decision, given evidence.
+// See [Network.ExplainDecision].
+type DecisionExplanation struct {
+	Decision      string                // Name of the decision variable.
+	Alternatives  []string              // Outcomes of the decision variable.
+	Utilities     []float64             // Expected utility of each alternative.
+	Feasible      []bool                // Whether each alternative is allowed. Nil if the decision is not constrained.
+	Action        string                // Optimal action.
+	RunnerUp      string                // Feasible alternative with the second-highest expected utility. Empty if there is none.
+	Margin        float64               // Expected utility difference between the optimal action and the runner-up.
+	Contributions []UtilityContribution // Contributions of utility variables, by decreasing absolute difference. Nil for a utility function.
+	Thresholds    []FlipThreshold       // Probability changes that flip the decision, by increasing distance.
+	Evidence      map[string]string     // The evidence.
+}
+
+// UtilityContribution is the contribution of a utility variable to the expected utility of decision alternatives.
+type UtilityContribution struct {
+	Variable   string    // Name of the utility variable.
+	Utilities  []float64 // Expected utility of the variable for each alternative, weighted for the total utility.
+	Difference float64   // Difference between the optimal action and the runner-up.
+}
+
+// FlipThreshold is the probability of an outcome of an uncertain variable at which the decision flips.
+type FlipThreshold struct {
+	Variable    string  // Name of the uncertain chance variable.
+	Outcome     string  // Outcome of the variable.
+	Probability float64 // Current probability of the outcome, given the evidence.
+	Threshold   float64 // Probability of the outcome at which the decision flips.
+	Action      string  // Action that becomes optimal beyond the threshold.
+}
+
+// ExplainDecision explains the optimal action of a decision, given evidence.
+//
+// It reports the expected utility of each alternative, and the contribution of each utility variable
+// to the difference between the optimal action and the runner-up.
+// Contributions are weighted by the total utility variable, if present.
+// They are not reported if the total utility variable has a utility function, as they are not additive then.
+//
+// Further, it reports the uncertain chance variables whose change would flip the decision.
+// For each outcome of a chance variable that is neither observed nor a descendant of the decision,
+// the expected utility of each alternative is linear in the outcome's probability,
+// with the probabilities of the other outcomes scaled proportionally.
+// The threshold is the probability at which another alternative becomes optimal.
+// Only the nearest threshold is reported for each variable.
+//
+// Policies must have been solved by [Network.SolvePolicies] before. Later decisions follow their policies.
+func (n *Network) ExplainDecision(evidence map[string]string, decision string) (*DecisionExplanation, error) {
+	v, err := n.checkDecisionExplanation(evidence, decision)
+	if err != nil {
+		return nil, err
+	}
+	prob, err := n.evidenceProbability(evidence)
+	if err != nil {
+		return nil, err
+	}
+	if prob <= 0 {
+		return nil, fmt.Errorf("evidence has zero probability")
+	}
+
+	exp := DecisionExplanation{
+		Decision:     decision,
+		Alternatives: v.Outcomes,
+		Feasible:     n.allowedOutcomes(decision, evidence),
+		Evidence:     evidence,
+	}
+	if exp.Utilities, err = n.alternativeUtilities(evidence, decision, "", prob); err != nil {
+		return nil, err
+	}
+	best, second := bestAlternatives(exp.Utilities, exp.Feasible)
+	exp.Action = v.Outcomes[best]
+	if second >= 0 {
+		exp.RunnerUp = v.Outcomes[second]
+		exp.Margin = exp.Utilities[best] - exp.Utilities[second]
+	}
+
+	if exp.Contributions, err = n.utilityContributions(evidence, decision, prob, best, second); err != nil {
+		return nil, err
+	}
+	if exp.Thresholds, err = n.flipThresholds(&exp, best); err != nil {
+		return nil, err
+	}
+	return &exp, nil
+}
+
+// checkDecisionExplanation checks the arguments of [Network.ExplainDecision].
+func (n *Network) checkDecisionExplanation(evidence map[string]string, decision string) (*Variable, error) {
+	v, ok := n.variable(decision)
+	if !ok {
+		return nil, newVariableError(decision, ErrUnknownVariable, "decision variable %s not found", decision)
+	}
+	if v.NodeType != ve.DecisionNode {
+		return nil, newVariableError(decision, ErrUnsupported, "variable %s is not a decision variable", decision)
+	}
+	if _, ok := evidence[decision]; ok {
+		return nil, fmt.Errorf("decision variable %s can't be an evidence variable", decision)
+	}
+	if _, ok := n.policies[decision]; !ok {
+		return nil, newVariableError(decision, ErrNoPolicy, "decision variable %s has no policy; solve policies first", decision)
+	}
+	return v, nil
+}
+
+// alternativeUtilities calculates the expected utility of each alternative of a decision, given evidence of probability prob.
+// If utilityVar is not empty, only the utility of that variable is calculated.
+func (n *Network) alternativeUtilities(evidence map[string]string, decision string, utilityVar string, prob float64) ([]float64, error) {
+	v, _ := n.variable(decision)
+	ev := maps.Clone(evidence)
+	if ev == nil {
+		ev = map[string]string{}
+	}
+	utilities := make([]float64, len(v.Outcomes))
+	for i, alt := range v.Outcomes {
+		ev[decision] = alt
+		utility, err := n.SolveUtility(ev, []string{}, utilityVar, true)
+		if err != nil {
+			return nil, err
+		}
+		utilities[i] = utility.Data()[0] / prob
+	}
+	return utilities, nil
+}
+
+// bestAlternatives returns the indices of the best and the second-best feasible alternative.
+// The second index is -1 if there is only one feasible alternative.
+// If feasible is nil, all alternatives are feasible.
+func bestAlternatives(utilities []float64, feasible []bool) (int, int) {
+	best, second := -1, -1
+	for i, u := range utilities {
+		if feasible != nil && !feasible[i] {
+			continue
+		}
+		if best < 0 || u > utilities[best] {
+			best, second = i, best
+		} else if second < 0 || u > utilities[second] {
+			second = i
+		}
+	}
+	return best, second
+}
+
+// utilityContributions calculates the weighted expected utility of each utility variable for each alternative.
+// Returns nil if the network has a utility function.
+func (n *Network) utilityContributions(evidence map[string]string, decision string, prob float64, best, second int) ([]UtilityContribution, error) {
+	if n.hasUtilityFunction() {
+		return nil, nil
+	}
+	contributions := []UtilityContribution{}
+	for i := range n.variables {
+		v := &n.variables[i]
+		if v.NodeType != ve.UtilityNode || i == n.totalUtilityIndex {
+			continue
+		}
+		utilities, err := n.alternativeUtilities(evidence, decision, v.Name, prob)
+		if err != nil {
+			return nil, err
+		}
+		weight := n.utilityWeight(v.Name)
+		for j := range utilities {
+			utilities[j] *= weight
+		}
+		c := UtilityContribution{Variable: v.Name, Utilities: utilities}
+		if second >= 0 {
+			c.Difference = utilities[best] - utilities[second]
+		}
+		contributions = append(contributions, c)
+	}
+	slices.SortStableFunc(contributions, func(a, b UtilityContribution) int {
+		return -cmp.Compare(math.Abs(a.Difference), math.Abs(b.Difference))
+	})
+	return contributions, nil
+}
+
+// utilityWeight returns the weight of a utility variable in the total utility. 1 if there is no total utility variable.
+func (n *Network) utilityWeight(name string) float64 {
+	if n.totalUtilityIndex < 0 {
+		return 1
+	}
+	total := n.variables[n.totalUtilityIndex].Factor
+	return total.Table[slices.Index(total.Given, name)]
+}
+
+// flipThresholds calculates the nearest probability threshold that flips the decision, for each uncertain chance variable.
+func (n *Network) flipThresholds(exp *DecisionExplanation, best int) ([]FlipThreshold, error) {
+	decisionIdx, _ := n.variableIdx(exp.Decision)
+	descendants := n.descendantIndices(decisionIdx)
+
+	thresholds := []FlipThreshold{}
+	for i := range n.variables {
+		v := &n.variables[i]
+		if _, ok := exp.Evidence[v.Name]; ok || v.NodeType != ve.ChanceNode || descendants[i] {
+			continue
+		}
+		threshold, ok, err := n.flipThreshold(exp, best, v)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			thresholds = append(thresholds, threshold)
+		}
+	}
+	slices.SortStableFunc(thresholds, func(a, b FlipThreshold) int {
+		return cmp.Compare(math.Abs(a.Threshold-a.Probability), math.Abs(b.Threshold-b.Probability))
+	})
+	return thresholds, nil
+}
+
+// flipThreshold calculates the nearest probability threshold of the variable's outcomes that flips the decision.
+// Returns false if no change of a single outcome's probability flips the decision.
+func (n *Network) flipThreshold(exp *DecisionExplanation, best int, v *Variable) (FlipThreshold, bool, error) {
+	ev := maps.Clone(exp.Evidence)
+	if ev == nil {
+		ev = map[string]string{}
+	}
+	prob, err := n.evidenceProbability(exp.Evidence)
+	if err != nil {
+		return FlipThreshold{}, false, err
+	}
+
+	result := FlipThreshold{Variable: v.Name}
+	found := false
+	for _, outcome := range v.Outcomes {
+		ev[v.Name] = outcome
+		probOutcome, err := n.evidenceProbability(ev)
+		if err != nil {
+			return FlipThreshold{}, false, err
+		}
+		p := probOutcome / prob
+		if p <= 0 || p >= 1 {
+			continue
+		}
+		given, err := n.alternativeUtilities(ev, exp.Decision, "", probOutcome)
+		if err != nil {
+			return FlipThreshold{}, false, err
+		}
+		threshold, alt, ok := exp.threshold(best, given, p)
+		if ok && (!found || math.Abs(threshold-p) < math.Abs(result.Threshold-result.Probability)) {
+			result.Outcome, result.Probability, result.Threshold, result.Action = outcome, p, threshold, exp.Alternatives[alt]
+			found = true
+		}
+	}
+	return result, found, nil
+}
+
+// threshold calculates the probability of an outcome at which another alternative becomes optimal,
+// from the expected utilities given the outcome, and the outcome's current probability p.
+// Returns the threshold nearest to p, and the index of the respective alternative.
+func (e *DecisionExplanation) threshold(best int, given []float64, p float64) (float64, int, bool) {
+	nearest, alt := math.NaN(), -1
+	for i := range e.Utilities {
+		if i == best || (e.Feasible != nil && !e.Feasible[i]) {
+			continue
+		}
+		// utility differences to the optimal action, given the outcome and given other outcomes
+		diffGiven := given[best] - given[i]
+		diffOther := ((e.Utilities[best] - p*given[best]) - (e.Utilities[i] - p*given[i])) / (1 - p)
+		if diffGiven == diffOther {
+			continue
+		}
+		t := diffOther / (diffOther - diffGiven)
+		if t < 0 || t > 1 {
+			continue
+		}
+		if alt < 0 || math.Abs(t-p) < math.Abs(nearest-p) {
+			nearest, alt = t, i
+		}
+	}
+	return nearest, alt, alt >= 0
+}
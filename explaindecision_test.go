@@ -0,0 +1,101 @@
+package bbn_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/mlange-42/bbn"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNetworkExplainDecision(t *testing.T) {
+	net, err := bbn.FromFile("_examples/decision/umbrella.yml")
+	assert.Nil(t, err)
+
+	_, err = net.ExplainDecision(nil, "Umbrella")
+	assert.ErrorIs(t, err, bbn.ErrNoPolicy)
+
+	_, err = net.SolvePolicies(true)
+	assert.Nil(t, err)
+
+	exp, err := net.ExplainDecision(map[string]string{"Forecast": "Rainy"}, "Umbrella")
+	assert.Nil(t, err)
+	assert.Equal(t, "Take", exp.Action)
+	assert.Equal(t, "Leave", exp.RunnerUp)
+	assert.InDelta(t, 56, exp.Utilities[0], 1e-9)
+	assert.InDelta(t, 28, exp.Utilities[1], 1e-9)
+	assert.InDelta(t, 28, exp.Margin, 1e-9)
+	assert.Nil(t, exp.Feasible)
+
+	assert.Equal(t, 1, len(exp.Contributions))
+	assert.Equal(t, "Utility", exp.Contributions[0].Variable)
+	assert.InDelta(t, 28, exp.Contributions[0].Difference, 1e-9)
+
+	// Forecast is observed, and Utility is no chance variable
+	assert.Equal(t, 1, len(exp.Thresholds))
+	threshold := exp.Thresholds[0]
+	assert.Equal(t, "Weather", threshold.Variable)
+	assert.Equal(t, "Sunny", threshold.Outcome)
+	assert.InDelta(t, 0.7*0.1/(0.7*0.1+0.3*0.6), threshold.Probability, 1e-9)
+	// -80 p + 70 (1-p) = 0
+	assert.InDelta(t, 70.0/150.0, threshold.Threshold, 1e-9)
+	assert.Equal(t, "Leave", threshold.Action)
+
+	_, err = net.ExplainDecision(nil, "Weather")
+	assert.NotNil(t, err)
+	_, err = net.ExplainDecision(nil, "Missing")
+	assert.ErrorIs(t, err, bbn.ErrUnknownVariable)
+	_, err = net.ExplainDecision(map[string]string{"Umbrella": "Take"}, "Umbrella")
+	assert.NotNil(t, err)
+}
+
+func TestNetworkExplainDecisionSequential(t *testing.T) {
+	net, err := bbn.FromFile("_examples/decision/oil.yml")
+	assert.Nil(t, err)
+	_, err = net.SolvePolicies(true)
+	assert.Nil(t, err)
+
+	exp, err := net.ExplainDecision(nil, "Do test drill")
+	assert.Nil(t, err)
+	assert.Equal(t, "yes", exp.Action)
+	assert.InDelta(t, 2.5, exp.Margin, 1e-9)
+
+	assert.Equal(t, []string{"Drill utility", "Test utility"},
+		[]string{exp.Contributions[0].Variable, exp.Contributions[1].Variable})
+	assert.InDelta(t, 12.5, exp.Contributions[0].Difference, 1e-9)
+	assert.InDelta(t, -10, exp.Contributions[1].Difference, 1e-9)
+
+	// the test result is a descendant of the decision
+	for _, th := range exp.Thresholds {
+		assert.NotEqual(t, "Test result", th.Variable)
+	}
+	assert.Equal(t, "Oil", exp.Thresholds[0].Variable)
+	assert.Equal(t, "no", exp.Thresholds[0].Action)
+}
+
+func TestNetworkExplainDecisionConstrained(t *testing.T) {
+	net, err := bbn.FromFile("_examples/decision/oil-permit.yml")
+	assert.Nil(t, err)
+	_, err = net.SolvePolicies(true)
+	assert.Nil(t, err)
+
+	exp, err := net.ExplainDecision(map[string]string{"Apply for permit": "yes", "Permit": "denied"}, "Do drill")
+	assert.Nil(t, err)
+	assert.Equal(t, []bool{false, true}, exp.Feasible)
+	assert.Equal(t, "no", exp.Action)
+	assert.Equal(t, "", exp.RunnerUp)
+	assert.Empty(t, exp.Thresholds)
+}
+
+func TestNetworkExplainDecisionUtilityFunction(t *testing.T) {
+	net, err := bbn.FromFile("_examples/decision/investment.yml")
+	assert.Nil(t, err)
+	_, err = net.SolvePolicies(true)
+	assert.Nil(t, err)
+
+	exp, err := net.ExplainDecision(nil, "Investment")
+	assert.Nil(t, err)
+	assert.Equal(t, "bonds", exp.Action)
+	assert.InDelta(t, 100*(1-math.Exp(-0.4)), exp.Utilities[1], 1e-9)
+	assert.Nil(t, exp.Contributions)
+}
@@ -0,0 +1,211 @@
+package bbn
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Format of a network file.
+type Format string
+
+const (
+	FormatAuto   Format = ""        // Detect the format from content.
+	FormatYAML   Format = "yaml"    // YAML format, see [FromYAML].
+	FormatBIFXML Format = "bif-xml" // BIF-XML format, see [FromBIFXML].
+)
+
+type formatInfo struct {
+	Format     Format
+	Extensions []string
+	Sniff      func(content []byte) bool
+	Parse      func(content []byte) (*Network, error)
+}
+
+// formats lists all supported formats, in the order they are tried when sniffing content.
+var formats = []formatInfo{
+	{
+		Format:     FormatBIFXML,
+		Extensions: []string{".xml", ".bifxml"},
+		Sniff:      sniffXML,
+		Parse:      FromBIFXML,
+	},
+	{
+		Format:     FormatYAML,
+		Extensions: []string{".yml", ".yaml"},
+		Sniff:      sniffYAML,
+		Parse:      FromYAML,
+	},
+}
+
+// ParseError is returned when a network file can't be parsed.
+// Line and Column are zero if the position is unknown.
+type ParseError struct {
+	File   string // Name of the file, if known.
+	Line   int    // Line of the error, starting at 1.
+	Column int    // Column of the error, starting at 1.
+	Err    error  // The underlying error.
+}
+
+func (e *ParseError) Error() string {
+	pos := e.File
+	if e.Line > 0 {
+		if pos == "" {
+			pos = "line " + strconv.Itoa(e.Line)
+		} else {
+			pos += ":" + strconv.Itoa(e.Line)
+		}
+		if e.Column > 0 {
+			pos += ":" + strconv.Itoa(e.Column)
+		}
+	}
+	if pos == "" {
+		return e.Err.Error()
+	}
+	return fmt.Sprintf("%s: %s", pos, e.Err.Error())
+}
+
+// Unwrap returns the underlying error.
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// FromReader reads a [Network] from an [io.Reader], in the given format.
+//
+// With [FormatAuto], the format is detected from the content.
+func FromReader(r io.Reader, format Format) (*Network, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	return parse("", data, format)
+}
+
+// FromFS reads a [Network] from a file in a [fs.FS], like an [embed.FS].
+//
+// The format is detected from the content, with the file extension as fallback.
+// See [DetectFormat].
+func FromFS(fsys fs.FS, path string) (*Network, error) {
+	data, err := fs.ReadFile(fsys, path)
+	if err != nil {
+		return nil, err
+	}
+	return parse(path, data, FormatAuto)
+}
+
+// DetectFormat detects the format of a network file.
+//
+// Argument path is used for the file extension and may be empty.
+// Content sniffing takes precedence over the extension,
+// so that files with a missing or wrong extension are detected correctly.
+// The extension is only used if the content is not recognized.
+func DetectFormat(path string, content []byte) (Format, error) {
+	for _, f := range formats {
+		if f.Sniff(content) {
+			return f.Format, nil
+		}
+	}
+	ext := strings.ToLower(filepath.Ext(path))
+	for _, f := range formats {
+		for _, e := range f.Extensions {
+			if e == ext {
+				return f.Format, nil
+			}
+		}
+	}
+	if ext == "" {
+		return FormatAuto, fmt.Errorf("unable to detect file format")
+	}
+	return FormatAuto, fmt.Errorf("unsupported file format '%s'", ext)
+}
+
+// parse parses a network from content in the given format.
+// Parse errors get the file name attached.
+func parse(path string, content []byte, format Format) (*Network, error) {
+	if format == FormatAuto {
+		var err error
+		format, err = DetectFormat(path, content)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for _, f := range formats {
+		if f.Format != format {
+			continue
+		}
+		net, err := f.Parse(content)
+		if err != nil {
+			var pErr *ParseError
+			if errors.As(err, &pErr) {
+				pErr.File = path
+			}
+			return nil, err
+		}
+		return net, nil
+	}
+	return nil, fmt.Errorf("unsupported format '%s'", format)
+}
+
+// trimContent removes a byte order mark and leading white space.
+func trimContent(content []byte) []byte {
+	content = bytes.TrimPrefix(content, []byte("\xef\xbb\xbf"))
+	return bytes.TrimLeft(content, " \t\r\n")
+}
+
+func sniffXML(content []byte) bool {
+	return bytes.HasPrefix(trimContent(content), []byte("<"))
+}
+
+var yamlKeyRegex = regexp.MustCompile(`^(---|[A-Za-z_][\w-]*\s*:)`)
+
+// sniffYAML checks whether the first line that is not a comment looks like YAML.
+func sniffYAML(content []byte) bool {
+	content = trimContent(content)
+	for len(content) > 0 {
+		line := content
+		if idx := bytes.IndexByte(content, '\n'); idx >= 0 {
+			line, content = content[:idx], content[idx+1:]
+		} else {
+			content = nil
+		}
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 || line[0] == '#' {
+			continue
+		}
+		return yamlKeyRegex.Match(line)
+	}
+	return false
+}
+
+var yamlLineRegex = regexp.MustCompile(`line (\d+): `)
+
+// yamlError converts errors from the YAML parser to a [ParseError].
+func yamlError(err error) error {
+	msg := strings.TrimPrefix(err.Error(), "yaml: ")
+	match := yamlLineRegex.FindStringSubmatchIndex(msg)
+	if match == nil {
+		return &ParseError{Err: errors.New(msg)}
+	}
+	line, _ := strconv.Atoi(msg[match[2]:match[3]])
+	msg = msg[:match[0]] + msg[match[1]:]
+	msg = strings.TrimPrefix(msg, "unmarshal errors:\n  ")
+	return &ParseError{Line: line, Err: errors.New(msg)}
+}
+
+// xmlError converts errors from the XML parser to a [ParseError].
+func xmlError(err error, decoder *xml.Decoder) error {
+	var sErr *xml.SyntaxError
+	if errors.As(err, &sErr) {
+		return &ParseError{Line: sErr.Line, Err: errors.New(sErr.Msg)}
+	}
+	line, col := decoder.InputPos()
+	return &ParseError{Line: line, Column: col, Err: err}
+}
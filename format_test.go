@@ -0,0 +1,124 @@
+package bbn_test
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/mlange-42/bbn"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFromReader(t *testing.T) {
+	yml, err := os.ReadFile("_examples/bbn/sprinkler.yml")
+	assert.Nil(t, err)
+	xml, err := os.ReadFile("_examples/bbn/dog-problem.xml")
+	assert.Nil(t, err)
+
+	net, err := bbn.FromReader(strings.NewReader(string(yml)), bbn.FormatYAML)
+	assert.Nil(t, err)
+	assert.Equal(t, "Sprinkler", net.Name())
+
+	net, err = bbn.FromReader(strings.NewReader(string(yml)), bbn.FormatAuto)
+	assert.Nil(t, err)
+	assert.Equal(t, "Sprinkler", net.Name())
+
+	net, err = bbn.FromReader(strings.NewReader(string(xml)), bbn.FormatAuto)
+	assert.Nil(t, err)
+	assert.Equal(t, "Dog-Problem", net.Name())
+
+	_, err = bbn.FromReader(strings.NewReader(string(xml)), bbn.FormatYAML)
+	assert.NotNil(t, err)
+
+	_, err = bbn.FromReader(strings.NewReader(string(yml)), bbn.Format("json"))
+	assert.NotNil(t, err)
+}
+
+func TestFromFS(t *testing.T) {
+	yml, err := os.ReadFile("_examples/bbn/sprinkler.yml")
+	assert.Nil(t, err)
+	xml, err := os.ReadFile("_examples/bbn/dog-problem.xml")
+	assert.Nil(t, err)
+
+	fsys := fstest.MapFS{
+		"nets/sprinkler.yml": {Data: yml},
+		"nets/sprinkler":     {Data: yml},
+		"nets/dog.yml":       {Data: xml},
+		"nets/unknown.txt":   {Data: []byte("\n\n")},
+	}
+
+	net, err := bbn.FromFS(fsys, "nets/sprinkler.yml")
+	assert.Nil(t, err)
+	assert.Equal(t, "Sprinkler", net.Name())
+
+	net, err = bbn.FromFS(fsys, "nets/sprinkler")
+	assert.Nil(t, err)
+	assert.Equal(t, "Sprinkler", net.Name())
+
+	net, err = bbn.FromFS(fsys, "nets/dog.yml")
+	assert.Nil(t, err)
+	assert.Equal(t, "Dog-Problem", net.Name())
+
+	_, err = bbn.FromFS(fsys, "nets/unknown.txt")
+	assert.EqualError(t, err, "unsupported file format '.txt'")
+
+	_, err = bbn.FromFS(fsys, "nets/missing.yml")
+	assert.NotNil(t, err)
+}
+
+func TestDetectFormat(t *testing.T) {
+	f, err := bbn.DetectFormat("", []byte("\xef\xbb\xbf  <?xml version=\"1.0\"?>"))
+	assert.Nil(t, err)
+	assert.Equal(t, bbn.FormatBIFXML, f)
+
+	f, err = bbn.DetectFormat("", []byte("# comment\n\nname: Test\n"))
+	assert.Nil(t, err)
+	assert.Equal(t, bbn.FormatYAML, f)
+
+	f, err = bbn.DetectFormat("net.yaml", []byte{})
+	assert.Nil(t, err)
+	assert.Equal(t, bbn.FormatYAML, f)
+
+	f, err = bbn.DetectFormat("net.yml", []byte("<BIF></BIF>"))
+	assert.Nil(t, err)
+	assert.Equal(t, bbn.FormatBIFXML, f)
+
+	_, err = bbn.DetectFormat("", []byte("{}"))
+	assert.EqualError(t, err, "unable to detect file format")
+}
+
+func TestParseError(t *testing.T) {
+	fsys := fstest.MapFS{
+		"syntax.yml": {Data: []byte("name: Test\nvariables:\n- variable: A\n  outcomes: [yes, no\n")},
+		"field.yml":  {Data: []byte("name: Test\nvariables:\n- variable: A\n  outcome: [yes, no]\n")},
+		"type.yml":   {Data: []byte("name: Test\nvariables:\n- variable: A\n  outcomes: [yes, no]\n  table: [[1, 1]]\n\n- variable: B\n  type: foo\n  outcomes: [yes, no]\n")},
+		"syntax.xml": {Data: []byte("<BIF>\n<NETWORK>\n<NAME>Test</NAME>\n</BIF>\n")},
+	}
+
+	_, err := bbn.FromFS(fsys, "syntax.yml")
+	var pErr *bbn.ParseError
+	assert.True(t, errors.As(err, &pErr))
+	assert.Equal(t, "syntax.yml", pErr.File)
+	assert.Greater(t, pErr.Line, 0)
+
+	_, err = bbn.FromFS(fsys, "field.yml")
+	assert.True(t, errors.As(err, &pErr))
+	assert.Equal(t, 4, pErr.Line)
+	assert.EqualError(t, err, "field.yml:4: field outcome not found in type bbn.variableYaml")
+
+	_, err = bbn.FromFS(fsys, "type.yml")
+	assert.True(t, errors.As(err, &pErr))
+	assert.Equal(t, 7, pErr.Line)
+	assert.Equal(t, 3, pErr.Column)
+	assert.EqualError(t, err, "type.yml:7:3: unknown node type foo")
+
+	_, err = bbn.FromFS(fsys, "syntax.xml")
+	assert.True(t, errors.As(err, &pErr))
+	assert.Equal(t, "syntax.xml", pErr.File)
+	assert.Equal(t, 4, pErr.Line)
+
+	_, err = bbn.FromYAML([]byte("name: Test\nvariables:\n- variable: A\n  outcome: [yes, no]\n"))
+	assert.EqualError(t, err, "line 4: field outcome not found in type bbn.variableYaml")
+}
@@ -0,0 +1,294 @@
+package bbn
+
+import (
+	"slices"
+)
+
+// Parents returns the names of the parents of a variable, in the order of [Factor.Given].
+func (n *Network) Parents(variable string) ([]string, error) {
+	idx, err := n.variableIdx(variable)
+	if err != nil {
+		return nil, err
+	}
+	return n.names(n.parentIndices(idx)), nil
+}
+
+// Children returns the names of the children of a variable, in the order of variable definition.
+func (n *Network) Children(variable string) ([]string, error) {
+	idx, err := n.variableIdx(variable)
+	if err != nil {
+		return nil, err
+	}
+	return n.names(n.childIndices(idx)), nil
+}
+
+// Ancestors returns the names of all ancestors of a variable, in the order of variable definition.
+func (n *Network) Ancestors(variable string) ([]string, error) {
+	idx, err := n.variableIdx(variable)
+	if err != nil {
+		return nil, err
+	}
+	return n.namesOf(n.ancestorIndices(idx)), nil
+}
+
+// Descendants returns the names of all descendants of a variable, in the order of variable definition.
+func (n *Network) Descendants(variable string) ([]string, error) {
+	idx, err := n.variableIdx(variable)
+	if err != nil {
+		return nil, err
+	}
+	return n.namesOf(n.descendantIndices(idx)), nil
+}
+
+// MarkovBlanket returns the names of the variables in the Markov blanket of a variable,
+// in the order of variable definition.
+//
+// The Markov blanket consists of the variable's parents, its children, and the other parents of its children.
+func (n *Network) MarkovBlanket(variable string) ([]string, error) {
+	idx, err := n.variableIdx(variable)
+	if err != nil {
+		return nil, err
+	}
+	blanket := make([]bool, len(n.variables))
+	for _, p := range n.parentIndices(idx) {
+		blanket[p] = true
+	}
+	for _, c := range n.childIndices(idx) {
+		blanket[c] = true
+		for _, p := range n.parentIndices(c) {
+			blanket[p] = true
+		}
+	}
+	blanket[idx] = false
+	return n.namesOf(blanket), nil
+}
+
+// TopologicalOrder returns the names of all variables in topological order,
+// i.e. parents before their children.
+//
+// The order is stable with respect to the variable definition order.
+// Returns an error wrapping [ErrCycle] if the network contains a cycle.
+func (n *Network) TopologicalOrder() ([]string, error) {
+	if found, problems := n.validateCycles(nil); found {
+		return nil, newVariableError(problems[0].Variable, ErrCycle, "%s", problems[0].Message)
+	}
+	return n.names(n.topologicalIndices()), nil
+}
+
+// IsDSeparated checks whether variables x and y are d-separated, given the variables in given.
+//
+// If x and y are d-separated, they are conditionally independent given the variables in given,
+// for any parametrization of the network.
+// Uses the reachability algorithm from Koller & Friedman (2009), Algorithm 3.1.
+func (n *Network) IsDSeparated(x, y string, given []string) (bool, error) {
+	xIdx, err := n.variableIdx(x)
+	if err != nil {
+		return false, err
+	}
+	yIdx, err := n.variableIdx(y)
+	if err != nil {
+		return false, err
+	}
+	observed := make([]bool, len(n.variables))
+	for _, g := range given {
+		idx, err := n.variableIdx(g)
+		if err != nil {
+			return false, err
+		}
+		observed[idx] = true
+	}
+	if observed[xIdx] || observed[yIdx] {
+		return true, nil
+	}
+	return !n.reachable(xIdx, observed)[yIdx], nil
+}
+
+// trailStep is a step of an active trail search.
+type trailStep struct {
+	index int
+	up    bool // Trail arrives from a child, i.e. travels upwards.
+}
+
+// reachable returns the set of variables reachable from the variable at the given index
+// via active trails, given the observed variables.
+func (n *Network) reachable(start int, observed []bool) []bool {
+	return n.reachableFrom(trailStep{start, true}, observed)
+}
+
+// reachableFrom returns the set of variables reachable via active trails that start with the given step,
+// given the observed variables.
+func (n *Network) reachableFrom(start trailStep, observed []bool) []bool {
+	observedAnc := n.observedAncestors(observed)
+
+	visited := [2][]bool{make([]bool, len(n.variables)), make([]bool, len(n.variables))}
+	result := make([]bool, len(n.variables))
+
+	open := []trailStep{start}
+	for len(open) > 0 {
+		step := open[len(open)-1]
+		open = open[:len(open)-1]
+
+		dir := 0
+		if step.up {
+			dir = 1
+		}
+		if visited[dir][step.index] {
+			continue
+		}
+		visited[dir][step.index] = true
+		if !observed[step.index] {
+			result[step.index] = true
+		}
+		open = n.trailSteps(step, observed[step.index], observedAnc[step.index], open)
+	}
+	return result
+}
+
+// trailSteps appends the steps that continue an active trail to open.
+func (n *Network) trailSteps(step trailStep, observed, observedAnc bool, open []trailStep) []trailStep {
+	if !observed {
+		for _, c := range n.childIndices(step.index) {
+			open = append(open, trailStep{c, false})
+		}
+	}
+	// upwards: trail through an unobserved node; downwards: v-structure with an observed descendant
+	if (step.up && !observed) || (!step.up && observedAnc) {
+		for _, p := range n.parentIndices(step.index) {
+			open = append(open, trailStep{p, true})
+		}
+	}
+	return open
+}
+
+// observedAncestors returns the set of observed variables and all their ancestors.
+func (n *Network) observedAncestors(observed []bool) []bool {
+	result := make([]bool, len(n.variables))
+	for i, obs := range observed {
+		if !obs {
+			continue
+		}
+		result[i] = true
+		for j, anc := range n.ancestorIndices(i) {
+			result[j] = result[j] || anc
+		}
+	}
+	return result
+}
+
+// variableIdx returns the index of the variable with the given name.
+func (n *Network) variableIdx(name string) (int, error) {
+	idx := slices.IndexFunc(n.variables, func(v Variable) bool { return v.Name == name })
+	if idx < 0 {
+		return -1, newVariableError(name, ErrUnknownVariable, "variable %s not found", name)
+	}
+	return idx, nil
+}
+
+// variable returns the variable with the given name.
+func (n *Network) variable(name string) (*Variable, bool) {
+	idx := slices.IndexFunc(n.variables, func(v Variable) bool { return v.Name == name })
+	if idx < 0 {
+		return nil, false
+	}
+	return &n.variables[idx], true
+}
+
+// names returns the names of the variables at the given indices.
+func (n *Network) names(indices []int) []string {
+	result := make([]string, len(indices))
+	for i, idx := range indices {
+		result[i] = n.variables[idx].Name
+	}
+	return result
+}
+
+// namesOf returns the names of the variables in the given index set, in the order of variable definition.
+func (n *Network) namesOf(set []bool) []string {
+	result := []string{}
+	for i, ok := range set {
+		if ok {
+			result = append(result, n.variables[i].Name)
+		}
+	}
+	return result
+}
+
+// parentIndices returns the indices of the parents of the variable at the given index.
+// Parents that are not found are ignored.
+func (n *Network) parentIndices(index int) []int {
+	v := &n.variables[index]
+	if v.Factor == nil {
+		return nil
+	}
+	result := make([]int, 0, len(v.Factor.Given))
+	for _, p := range v.Factor.Given {
+		idx := slices.IndexFunc(n.variables, func(v Variable) bool { return v.Name == p })
+		if idx >= 0 {
+			result = append(result, idx)
+		}
+	}
+	return result
+}
+
+// childIndices returns the indices of the children of the variable at the given index.
+func (n *Network) childIndices(index int) []int {
+	name := n.variables[index].Name
+	result := []int{}
+	for i := range n.variables {
+		f := n.variables[i].Factor
+		if f != nil && slices.Contains(f.Given, name) {
+			result = append(result, i)
+		}
+	}
+	return result
+}
+
+// ancestorIndices returns a set of indices of all ancestors of the variable at the given index.
+func (n *Network) ancestorIndices(index int) []bool {
+	return n.closure(index, n.parentIndices)
+}
+
+// descendantIndices returns a set of indices of all descendants of the variable at the given index.
+func (n *Network) descendantIndices(index int) []bool {
+	return n.closure(index, n.childIndices)
+}
+
+// closure returns a set of indices of all variables reachable from the given index via the given neighbor function.
+// The start index is only included if it is reachable from itself.
+func (n *Network) closure(index int, neighbors func(int) []int) []bool {
+	result := make([]bool, len(n.variables))
+	open := neighbors(index)
+	for len(open) > 0 {
+		idx := open[len(open)-1]
+		open = open[:len(open)-1]
+		if result[idx] {
+			continue
+		}
+		result[idx] = true
+		open = append(open, neighbors(idx)...)
+	}
+	return result
+}
+
+// topologicalIndices returns the indices of all variables in topological order.
+// Requires an acyclic network. Order is stable with respect to the variable definition order.
+func (n *Network) topologicalIndices() []int {
+	visited := make([]bool, len(n.variables))
+	result := make([]int, 0, len(n.variables))
+
+	var visit func(i int)
+	visit = func(i int) {
+		if visited[i] {
+			return
+		}
+		visited[i] = true
+		for _, p := range n.parentIndices(i) {
+			visit(p)
+		}
+		result = append(result, i)
+	}
+	for i := range n.variables {
+		visit(i)
+	}
+	return result
+}
@@ -0,0 +1,105 @@
+package bbn_test
+
+import (
+	"testing"
+
+	"github.com/mlange-42/bbn"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	asia   = "Visit to Asia"
+	smoke  = "Smoker"
+	tub    = "Has Tuberculosis"
+	lung   = "Has Lung Cancer"
+	bronc  = "Has Bronchitis"
+	either = "Tuberculosis or Cancer"
+	xray   = "XRay Result"
+	dysp   = "Dyspnea"
+)
+
+func TestNetworkGraph(t *testing.T) {
+	net, err := bbn.FromFile("_examples/bbn/asia.yml")
+	assert.Nil(t, err)
+
+	parents, err := net.Parents(either)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{tub, lung}, parents)
+
+	children, err := net.Children(smoke)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{lung, bronc}, children)
+
+	ancestors, err := net.Ancestors(xray)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{asia, smoke, tub, lung, either}, ancestors)
+
+	descendants, err := net.Descendants(lung)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{either, xray, dysp}, descendants)
+
+	blanket, err := net.MarkovBlanket(either)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{tub, lung, bronc, xray, dysp}, blanket)
+
+	order, err := net.TopologicalOrder()
+	assert.Nil(t, err)
+	assert.Equal(t, []string{asia, smoke, tub, lung, bronc, either, xray, dysp}, order)
+
+	_, err = net.Parents("X")
+	assert.ErrorIs(t, err, bbn.ErrUnknownVariable)
+}
+
+func TestNetworkTopologicalOrderCycle(t *testing.T) {
+	net, err := bbn.New("Test", "",
+		[]bbn.Variable{
+			{Name: "A", Outcomes: []string{"yes", "no"}},
+			{Name: "B", Outcomes: []string{"yes", "no"}},
+		},
+		[]bbn.Factor{
+			{For: "A", Given: []string{"B"}, Table: []float64{1, 0, 0, 1}},
+			{For: "B", Given: []string{"A"}, Table: []float64{1, 0, 0, 1}},
+		})
+	assert.Nil(t, err)
+
+	_, err = net.TopologicalOrder()
+	assert.ErrorIs(t, err, bbn.ErrCycle)
+}
+
+func TestNetworkIsDSeparated(t *testing.T) {
+	net, err := bbn.FromFile("_examples/bbn/asia.yml")
+	assert.Nil(t, err)
+
+	tests := []struct {
+		x, y  string
+		given []string
+		sep   bool
+	}{
+		{asia, smoke, nil, true},
+		{asia, smoke, []string{either}, false},
+		{asia, smoke, []string{xray}, false},
+		{asia, smoke, []string{bronc}, true},
+		{asia, xray, nil, false},
+		{asia, xray, []string{either}, true},
+		{asia, xray, []string{tub}, true},
+		{lung, bronc, nil, false},
+		{lung, bronc, []string{smoke}, true},
+		{lung, bronc, []string{smoke, dysp}, false},
+		{xray, dysp, []string{either}, true},
+		{tub, lung, []string{tub}, true},
+	}
+	for _, tt := range tests {
+		sep, err := net.IsDSeparated(tt.x, tt.y, tt.given)
+		assert.Nil(t, err)
+		assert.Equal(t, tt.sep, sep, "%s _|_ %s | %v", tt.x, tt.y, tt.given)
+
+		sep, err = net.IsDSeparated(tt.y, tt.x, tt.given)
+		assert.Nil(t, err)
+		assert.Equal(t, tt.sep, sep, "%s _|_ %s | %v", tt.y, tt.x, tt.given)
+	}
+
+	_, err = net.IsDSeparated(asia, "X", nil)
+	assert.ErrorIs(t, err, bbn.ErrUnknownVariable)
+	_, err = net.IsDSeparated(asia, smoke, []string{"X"})
+	assert.ErrorIs(t, err, bbn.ErrUnknownVariable)
+}
@@ -7,6 +7,9 @@ import (
 	"github.com/rivo/tview"
 )
 
+// riskLevel is the probability level for the value at risk in risk profiles.
+const riskLevel = 0.05
+
 type App struct {
 	app          *tview.Application
 	file         string
@@ -14,37 +17,46 @@ type App struct {
 	csvDelimiter rune
 	noData       string
 
-	nodes       []Node
-	nodesByName map[string]int
-	pages       *tview.Pages
-	graph       *tview.TextView
-	tableDialog *tview.Grid
-	table       *tview.Table
-	helpDialog  *tview.Grid
-	help        *tview.TextView
-	infoDialog  *tview.Grid
-	info        *tview.TextView
-	canvas      [][]rune
-	colors      [][]Color
-	network     *bbn.Network
+	nodes         []Node
+	nodesByName   map[string]int
+	pages         *tview.Pages
+	graph         *tview.TextView
+	tableDialog   *tview.Grid
+	table         *tview.Table
+	helpDialog    *tview.Grid
+	help          *tview.TextView
+	infoDialog    *tview.Grid
+	info          *tview.TextView
+	messageDialog *tview.Grid
+	messageFrame  *tview.Grid
+	message       *tview.TextView
+	canvas        [][]rune
+	colors        [][]Color
+	network       *bbn.Network
 
 	evidence      map[string]string
+	do            map[string]string
 	marginals     map[string][]float64
 	selectedNode  int
 	selectedState int
 
-	ignorePolicies bool
+	ignorePolicies   bool
+	interventionMode bool
 }
 
-func New(path string, evidence map[string]string, trainingFile, noData string, csvDelimiter rune) *App {
+func New(path string, evidence map[string]string, do map[string]string, trainingFile, noData string, csvDelimiter rune) *App {
 	if evidence == nil {
 		evidence = map[string]string{}
 	}
+	if do == nil {
+		do = map[string]string{}
+	}
 	return &App{
 		file:         path,
 		trainingFile: trainingFile,
 		csvDelimiter: csvDelimiter,
 		evidence:     evidence,
+		do:           do,
 	}
 }
 
@@ -67,7 +79,10 @@ func (a *App) Run() error {
 	a.nodes = make([]Node, len(nodes))
 	a.nodesByName = make(map[string]int, len(nodes))
 	for i, n := range nodes {
-		a.nodes[i] = NewNode(n)
+		a.nodes[i], err = NewNode(n)
+		if err != nil {
+			return err
+		}
 		a.nodesByName[n.Name] = i
 	}
 
@@ -108,6 +123,9 @@ func (a *App) Run() error {
 	a.infoDialog = a.createInfoPanel()
 	a.pages.AddPage("Info", a.infoDialog, true, false)
 
+	a.messageDialog = a.createMessagePanel()
+	a.pages.AddPage("Message", a.messageDialog, true, false)
+
 	mainPanel.SetInputCapture(a.inputMainPanel)
 	a.graph.SetMouseCapture(a.mouseInputGraph)
 
@@ -120,6 +138,9 @@ func (a *App) Run() error {
 	a.info.SetInputCapture(a.inputInfo)
 	a.info.SetMouseCapture(a.mouseInputInfo)
 
+	a.message.SetInputCapture(a.inputMessage)
+	a.message.SetMouseCapture(a.mouseInputMessage)
+
 	rooted := a.app.SetRoot(a.pages, true)
 
 	if a.network.Info() != "" {
@@ -164,6 +185,8 @@ func (a *App) createWidgets() {
 	a.help = tview.NewTextView().
 		SetWrap(true).
 		SetText(` Set/unset evidence by clicking on the probability bars of nodes.
+ In intervention mode, clicks set/unset interventions (do-operator) instead,
+ which cut the incoming edges of the node. Interventions are marked by >...<.
 
                     Keyboard              Mouse
 
@@ -173,13 +196,18 @@ func (a *App) createWidgets() {
  Navigate bars      Space/Numbers
  Toggle evidence    Enter                 left click
  Show node table    T                     right click
+ Explain node       E
+ Risk profiles      R
  Ignore policies    P
+ Intervention mode  X
  Move node          W/A/S/D
  Save network       Ctrl+S
 `)
 	a.info = tview.NewTextView().
 		SetWrap(true).
 		SetText(a.network.Info())
+	a.message = tview.NewTextView().
+		SetWrap(true)
 }
 
 func (a *App) createMainPanel() *tview.Grid {
@@ -270,3 +298,26 @@ func (a *App) createInfoPanel() *tview.Grid {
 
 	return grid
 }
+
+func (a *App) createMessagePanel() *tview.Grid {
+	grid := tview.NewGrid().
+		SetColumns(0, 72, 0).
+		SetRows(0, 20, 0)
+
+	subGrid := tview.NewGrid().
+		SetColumns(0).
+		SetRows(0, 1)
+	subGrid.SetBorder(true)
+	a.messageFrame = subGrid
+
+	subGrid.AddItem(a.message, 0, 0, 1, 1, 0, 0, true)
+
+	info := tview.NewTextView().
+		SetWrap(false).
+		SetText(" Close: ESC  Scroll: ←→↕")
+	subGrid.AddItem(info, 1, 0, 1, 1, 0, 0, false)
+
+	grid.AddItem(subGrid, 1, 1, 1, 1, 0, 0, false)
+
+	return grid
+}
@@ -0,0 +1,122 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mlange-42/bbn"
+)
+
+// FormatExplanation formats an evidence explanation as text.
+func FormatExplanation(exp *bbn.Explanation) string {
+	b := strings.Builder{}
+
+	fmt.Fprintf(&b, "%-24s", "")
+	for _, o := range exp.Outcomes {
+		fmt.Fprintf(&b, " %10s", o)
+	}
+	b.WriteString("\n")
+	writeProbabilities(&b, "Prior", exp.Prior)
+	writeProbabilities(&b, "Posterior", exp.Posterior)
+
+	if len(exp.Findings) == 0 {
+		b.WriteString("\nNo evidence.\n")
+		return b.String()
+	}
+
+	b.WriteString("\nPosterior without finding (leave-one-out)\n")
+	for _, f := range exp.Findings {
+		writeProbabilities(&b, f.Variable+"="+f.Outcome, f.Without)
+	}
+
+	b.WriteString("\nLog-likelihood ratio of finding\n")
+	for _, f := range exp.Findings {
+		fmt.Fprintf(&b, "%-24s", truncate(f.Variable+"="+f.Outcome, 24))
+		for i, v := range f.LogLikelihoodRatio {
+			if !f.Deterministic[i] {
+				fmt.Fprintf(&b, " %10.3f", v)
+				continue
+			}
+			if f.Without[i] < exp.Posterior[i] {
+				fmt.Fprintf(&b, " %10s", "+inf")
+			} else {
+				fmt.Fprintf(&b, " %10s", "-inf")
+			}
+		}
+		b.WriteString("\n")
+	}
+
+	conflict := "no conflict"
+	if exp.Conflict > 0 {
+		conflict = "possible conflict"
+	}
+	fmt.Fprintf(&b, "\nData conflict: %.3f (%s)\n", exp.Conflict, conflict)
+	return b.String()
+}
+
+// FormatDecisionExplanation formats the explanation of an optimal decision as text.
+func FormatDecisionExplanation(exp *bbn.DecisionExplanation) string {
+	b := strings.Builder{}
+
+	fmt.Fprintf(&b, "Optimal action: %s\n", exp.Action)
+	if exp.RunnerUp != "" {
+		fmt.Fprintf(&b, "Runner-up: %s, margin %.3f\n", exp.RunnerUp, exp.Margin)
+	} else {
+		b.WriteString("No feasible alternative\n")
+	}
+
+	fmt.Fprintf(&b, "\n%-24s %10s\n", "Alternative", "EU")
+	for i, alt := range exp.Alternatives {
+		label := truncate(alt, 24)
+		if exp.Feasible != nil && !exp.Feasible[i] {
+			fmt.Fprintf(&b, "%-24s %10s\n", label, "infeasible")
+			continue
+		}
+		marker := ""
+		if alt == exp.Action {
+			marker = " *"
+		}
+		fmt.Fprintf(&b, "%-24s %10.3f%s\n", label, exp.Utilities[i], marker)
+	}
+
+	if exp.RunnerUp == "" {
+		return b.String()
+	}
+
+	if exp.Contributions != nil {
+		fmt.Fprintf(&b, "\nUtility contributions (%s vs. %s)\n", exp.Action, exp.RunnerUp)
+		for _, c := range exp.Contributions {
+			fmt.Fprintf(&b, "%-24s %10.3f\n", truncate(c.Variable, 24), c.Difference)
+		}
+	}
+
+	b.WriteString("\nDecision flips if\n")
+	if len(exp.Thresholds) == 0 {
+		b.WriteString("  no single uncertain variable can flip the decision\n")
+	}
+	for _, t := range exp.Thresholds {
+		direction := "rises"
+		if t.Threshold < t.Probability {
+			direction = "falls"
+		}
+		fmt.Fprintf(&b, "  P(%s=%s) %s from %.3f to %.3f: %s\n",
+			t.Variable, t.Outcome, direction, t.Probability, t.Threshold, t.Action)
+	}
+	return b.String()
+}
+
+func writeProbabilities(b *strings.Builder, label string, probs []float64) {
+	fmt.Fprintf(b, "%-24s", truncate(label, 24))
+	for _, p := range probs {
+		fmt.Fprintf(b, " %9.3f%%", p*100)
+	}
+	b.WriteString("\n")
+}
+
+func truncate(s string, length int) string {
+	runes := []rune(s)
+	if len(runes) <= length {
+		return s
+	}
+	return string(runes[:length-1]) + "…"
+}
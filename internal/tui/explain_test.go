@@ -0,0 +1,68 @@
+package tui_test
+
+import (
+	"testing"
+
+	"github.com/mlange-42/bbn"
+	"github.com/mlange-42/bbn/internal/tui"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatExplanation(t *testing.T) {
+	net, err := bbn.FromFile("../../_examples/bbn/sprinkler.yml")
+	assert.Nil(t, err)
+
+	exp, err := net.ExplainEvidence(map[string]string{"GrassWet": "yes", "Sprinkler": "yes"}, "Rain")
+	assert.Nil(t, err)
+
+	text := tui.FormatExplanation(exp)
+	assert.Contains(t, text, "Posterior without finding")
+	assert.Contains(t, text, "Sprinkler=yes")
+	assert.Contains(t, text, "Data conflict: -1.085 (no conflict)")
+
+	exp, err = net.ExplainEvidence(map[string]string{}, "Rain")
+	assert.Nil(t, err)
+	assert.Contains(t, tui.FormatExplanation(exp), "No evidence.")
+
+	exp, err = net.ExplainEvidence(map[string]string{"Rain": "no", "GrassWet": "yes"}, "Sprinkler")
+	assert.Nil(t, err)
+	text = tui.FormatExplanation(exp)
+	assert.Contains(t, text, "+inf")
+	assert.Contains(t, text, "-inf")
+	assert.NotContains(t, text, "NaN")
+}
+
+func TestFormatDecisionExplanation(t *testing.T) {
+	net, err := bbn.FromFile("../../_examples/decision/umbrella.yml")
+	assert.Nil(t, err)
+	_, err = net.SolvePolicies(true)
+	assert.Nil(t, err)
+
+	exp, err := net.ExplainDecision(map[string]string{"Forecast": "Rainy"}, "Umbrella")
+	assert.Nil(t, err)
+
+	text := tui.FormatDecisionExplanation(exp)
+	assert.Contains(t, text, "Optimal action: Take")
+	assert.Contains(t, text, "Runner-up: Leave, margin 28.000")
+	assert.Contains(t, text, "Utility contributions (Take vs. Leave)")
+	assert.Contains(t, text, "P(Weather=Sunny) rises from 0.280 to 0.467: Leave")
+
+	exp.Thresholds = nil
+	assert.Contains(t, tui.FormatDecisionExplanation(exp), "no single uncertain variable")
+
+	exp.Feasible = []bool{true, false}
+	exp.RunnerUp = ""
+	text = tui.FormatDecisionExplanation(exp)
+	assert.Contains(t, text, "infeasible")
+	assert.NotContains(t, text, "Utility contributions")
+
+	net, err = bbn.FromFile("../../_examples/decision/investment.yml")
+	assert.Nil(t, err)
+	_, err = net.SolvePolicies(true)
+	assert.Nil(t, err)
+	exp, err = net.ExplainDecision(nil, "Investment")
+	assert.Nil(t, err)
+	text = tui.FormatDecisionExplanation(exp)
+	assert.Contains(t, text, "Runner-up: stocks")
+	assert.NotContains(t, text, "Utility contributions")
+}
@@ -1,6 +1,7 @@
 package tui
 
 import (
+	"fmt"
 	"os"
 	"path"
 	"strconv"
@@ -14,52 +15,72 @@ import (
 )
 
 func (a *App) inputMainPanel(event *tcell.EventKey) *tcell.EventKey {
-	if event.Key() == tcell.KeyEsc {
+	switch event.Key() {
+	case tcell.KeyEsc:
 		// Exit program.
 		a.app.Stop()
 		return nil
-	} else if event.Key() == tcell.KeyTAB {
+	case tcell.KeyTAB:
 		// Tab through nodes.
 		a.selectNextNode()
 		return nil
-	} else if event.Key() == tcell.KeyBacktab {
+	case tcell.KeyBacktab:
 		// Tab through nodes, backwards.
 		a.selectPreviousNode()
 		return nil
-	} else if event.Rune() == ' ' {
-		// Cycle through states.
-		a.selectedState = (a.selectedState + 1) % len(a.nodes[a.selectedNode].Node().Outcomes)
-		a.render(true)
-		return nil
-	} else if unicode.IsDigit(event.Rune()) {
-		// Select states by index/number keys.
-		a.selectNodeOutcome(string(event.Rune()))
-		return nil
-	} else if event.Key() == tcell.KeyEnter {
+	case tcell.KeyEnter:
 		// Set selected state as evidence.
 		if err := a.inputEnter(); err != nil {
-			panic(err)
+			a.showError(err)
 		}
 		a.render(true)
 		return nil
-	} else if event.Rune() == 'h' {
-		a.showHelp()
+	case tcell.KeyCtrlS:
+		if err := a.saveNetwork(); err != nil {
+			a.showError(err)
+		}
 		return nil
-	} else if event.Rune() == 'i' {
-		a.showInfo()
+	}
+	if a.inputRune(event.Rune()) {
 		return nil
-	} else if event.Rune() == 't' {
+	}
+	return a.moveNode(event)
+}
+
+// inputRune handles character keys in the main panel.
+// Returns false if the key was not handled.
+func (a *App) inputRune(r rune) bool {
+	switch r {
+	case ' ':
+		// Cycle through states.
+		if outcomes := len(a.nodes[a.selectedNode].Node().Outcomes); outcomes > 0 {
+			a.selectedState = (a.selectedState + 1) % outcomes
+		}
+		a.render(true)
+	case 'h':
+		a.showHelp()
+	case 'i':
+		a.showInfo()
+	case 't':
 		a.showTable()
-		return nil
-	} else if event.Rune() == 'p' {
+	case 'e':
+		a.showExplanation()
+	case 'r':
+		a.showRiskProfiles()
+	case 'p':
 		a.toggleIgnorePolicy()
-		return nil
-	} else if event.Key() == tcell.KeyCtrlS {
-		a.saveNetwork()
-		return nil
-	} else {
-		return a.moveNode(event)
+	case 'x':
+		a.toggleInterventionMode()
+	default:
+		if !unicode.IsDigit(r) {
+			return false
+		}
+		// Select states by index/number keys.
+		if err := a.selectNodeOutcome(string(r)); err != nil {
+			a.showError(err)
+		}
 	}
+	return true
 }
 
 func (a *App) toggleIgnorePolicy() {
@@ -71,23 +92,32 @@ func (a *App) toggleIgnorePolicy() {
 		a.graph.SetBorderColor(tcell.ColorDefault)
 	}
 
-	a.updateMarginals()
+	if err := a.updateMarginals(); err != nil {
+		a.showError(err)
+	}
 	a.render(true)
 }
 
-func (a *App) saveNetwork() {
+func (a *App) toggleInterventionMode() {
+	a.interventionMode = !a.interventionMode
+
+	if a.interventionMode {
+		a.graph.SetTitle(" Intervention mode ")
+	} else {
+		a.graph.SetTitle("")
+	}
+}
+
+func (a *App) saveNetwork() error {
 	yml, err := bbn.ToYAML(a.network)
 	if err != nil {
-		panic(err)
+		return err
 	}
 
 	ext := path.Ext(a.file)
 	saveFile := strings.TrimSuffix(a.file, ext) + "-save.yml"
 
-	err = os.WriteFile(saveFile, yml, 0644)
-	if err != nil {
-		panic(err)
-	}
+	return os.WriteFile(saveFile, yml, 0644)
 }
 
 func (a *App) moveNode(event *tcell.EventKey) *tcell.EventKey {
@@ -132,24 +162,28 @@ func (a *App) moveNode(event *tcell.EventKey) *tcell.EventKey {
 	return event
 }
 
-// inputEnter adds the currently selected node and state to the evidence.
+// inputEnter adds the currently selected node and state to the evidence,
+// or to the interventions when in intervention mode.
 func (a *App) inputEnter() error {
 	node := a.nodes[a.selectedNode]
-	if node.Node().NodeType == ve.UtilityNode {
+	if node.Node().NodeType == ve.UtilityNode || node.Node().NodeType == ve.ContinuousNode {
 		return nil
 	}
 
+	name := node.Node().Name
 	value := node.Node().Outcomes[a.selectedState]
 
+	target, other := a.evidence, a.do
+	if a.interventionMode {
+		target, other = a.do, a.evidence
+	}
+	delete(other, name)
+
 	// Add/clear selected state
-	if oldValue, ok := a.evidence[node.Node().Name]; ok {
-		if oldValue == value {
-			delete(a.evidence, node.Node().Name)
-		} else {
-			a.evidence[node.Node().Name] = value
-		}
+	if oldValue, ok := target[name]; ok && oldValue == value {
+		delete(target, name)
 	} else {
-		a.evidence[node.Node().Name] = value
+		target[name] = value
 	}
 
 	return a.updateMarginals()
@@ -157,7 +191,7 @@ func (a *App) inputEnter() error {
 
 func (a *App) updateMarginals() error {
 	var err error
-	a.marginals, err = Solve(a.network, a.evidence, a.nodes, a.ignorePolicies)
+	a.marginals, err = Solve(a.network, a.evidence, a.do, a.nodes, a.ignorePolicies)
 	if err != nil {
 		return err
 	}
@@ -177,6 +211,54 @@ func (a *App) showTable() {
 	a.app.SetFocus(a.table)
 }
 
+// showExplanation shows the impact of the evidence on the selected node,
+// or the explanation of the optimal action for a decision node without evidence.
+func (a *App) showExplanation() {
+	node := a.nodes[a.selectedNode].Node()
+	if len(a.do) > 0 {
+		a.showError(fmt.Errorf("explanations are not available with interventions"))
+		return
+	}
+	if _, isEvidence := a.evidence[node.Name]; node.NodeType == ve.DecisionNode && !isEvidence {
+		exp, err := a.network.ExplainDecision(a.evidence, node.Name)
+		if err != nil {
+			a.showError(err)
+			return
+		}
+		a.showMessage("Decision "+node.Name, FormatDecisionExplanation(exp))
+		return
+	}
+	exp, err := a.network.ExplainEvidence(a.evidence, node.Name)
+	if err != nil {
+		a.showError(err)
+		return
+	}
+	a.showMessage("Evidence impact on "+node.Name, FormatExplanation(exp))
+}
+
+// showRiskProfiles shows risk profiles for the alternatives of the selected decision node,
+// or the risk profile under the current policies for other nodes.
+func (a *App) showRiskProfiles() {
+	node := a.nodes[a.selectedNode].Node()
+	_, isEvidence := a.evidence[node.Name]
+	_, isDo := a.do[node.Name]
+	if node.NodeType == ve.DecisionNode && !isEvidence && !isDo {
+		profiles, err := a.network.DecisionRiskProfiles(a.evidence, node.Name)
+		if err != nil {
+			a.showError(err)
+			return
+		}
+		a.showMessage("Risk profiles of "+node.Name, FormatRiskProfiles(a.network, node.Outcomes, profiles, riskLevel))
+		return
+	}
+	profile, err := a.network.RiskProfile(a.evidence, a.do)
+	if err != nil {
+		a.showError(err)
+		return
+	}
+	a.showMessage("Risk profile", FormatRiskProfiles(a.network, []string{"Policy"}, []bbn.RiskProfile{*profile}, riskLevel))
+}
+
 func (a *App) showHelp() {
 	a.pages.ShowPage("Help")
 	a.app.SetFocus(a.help)
@@ -187,6 +269,18 @@ func (a *App) showInfo() {
 	a.app.SetFocus(a.info)
 }
 
+func (a *App) showError(err error) {
+	a.showMessage("Error", err.Error())
+}
+
+func (a *App) showMessage(title string, text string) {
+	a.message.SetText(text)
+	a.message.ScrollToBeginning()
+	a.messageFrame.SetTitle(" " + title + " ")
+	a.pages.ShowPage("Message")
+	a.app.SetFocus(a.message)
+}
+
 func (a *App) mouseInputGraph(action tview.MouseAction, event *tcell.EventMouse) (tview.MouseAction, *tcell.EventMouse) {
 	if action == tview.MouseLeftClick || action == tview.MouseRightClick {
 		front, _ := a.pages.GetFrontPage()
@@ -199,6 +293,9 @@ func (a *App) mouseInputGraph(action tview.MouseAction, event *tcell.EventMouse)
 		} else if front == "Info" {
 			a.pages.HidePage("Info")
 			return tview.MouseConsumed, nil
+		} else if front == "Message" {
+			a.pages.HidePage("Message")
+			return tview.MouseConsumed, nil
 		}
 	}
 
@@ -210,7 +307,7 @@ func (a *App) mouseInputGraph(action tview.MouseAction, event *tcell.EventMouse)
 				if outcome, ok := node.SelectedOutcome(x, y); ok {
 					a.selectedState = outcome
 					if err := a.inputEnter(); err != nil {
-						panic(err)
+						a.showError(err)
 					}
 				}
 				a.render(true)
@@ -258,6 +355,14 @@ func (a *App) mouseInputInfo(action tview.MouseAction, event *tcell.EventMouse)
 	return action, event
 }
 
+func (a *App) mouseInputMessage(action tview.MouseAction, event *tcell.EventMouse) (tview.MouseAction, *tcell.EventMouse) {
+	if action == tview.MouseLeftClick || action == tview.MouseRightClick {
+		a.pages.HidePage("Message")
+		return tview.MouseConsumed, nil
+	}
+	return action, event
+}
+
 func (a *App) inputTable(event *tcell.EventKey) *tcell.EventKey {
 	if event.Key() == tcell.KeyEsc || event.Key() == tcell.KeyEnter {
 		a.pages.HidePage("Table")
@@ -282,6 +387,14 @@ func (a *App) inputInfo(event *tcell.EventKey) *tcell.EventKey {
 	return event
 }
 
+func (a *App) inputMessage(event *tcell.EventKey) *tcell.EventKey {
+	if event.Key() == tcell.KeyEsc || event.Key() == tcell.KeyEnter {
+		a.pages.HidePage("Message")
+		return nil
+	}
+	return event
+}
+
 func (a *App) mousePosInGraph(x, y int) (int, int) {
 	boxX, boxY, _, _ := a.graph.GetInnerRect()
 	scrollY, scrollX := a.graph.GetScrollOffset()
@@ -303,14 +416,15 @@ func (a *App) selectPreviousNode() {
 	a.render(true)
 }
 
-func (a *App) selectNodeOutcome(index string) {
+func (a *App) selectNodeOutcome(index string) error {
 	idx, err := strconv.Atoi(index)
 	if err != nil {
-		panic(err)
+		return fmt.Errorf("invalid outcome number %s", index)
 	}
 	idx -= 1
 	if idx >= 0 && idx < len(a.nodes[a.selectedNode].Node().Outcomes) {
 		a.selectedState = idx
 		a.render(true)
 	}
+	return nil
 }
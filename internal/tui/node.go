@@ -15,10 +15,19 @@ const maxStateLabelWidth = 16
 const maxBars = 10
 const extraUtilityWidth = 5
 
+// EvidenceType of a node, for rendering.
+type EvidenceType uint8
+
+const (
+	NoEvidence   EvidenceType = iota // Node has no evidence.
+	Observed                         // Node is observed, i.e. has conventional evidence.
+	Intervention                     // Node is set by an intervention (do-operator).
+)
+
 type Node interface {
 	Node() *bbn.Variable
 	Bounds() *Bounds
-	Render(probs []float64, selected bool, state int, evidence bool) ([][]rune, [][]Color)
+	Render(probs []float64, selected bool, state int, evidence EvidenceType) ([][]rune, [][]Color)
 	SelectedOutcome(x, y int) (int, bool)
 }
 
@@ -30,9 +39,15 @@ type node struct {
 	barsX  int
 }
 
-func NewNode(n bbn.Variable) Node {
+// continuousLabels are the row labels of continuous nodes.
+var continuousLabels = []string{"mean", "variance"}
+
+// NewNode creates a new [Node] for a variable.
+// Returns an error if the variable's color is unknown.
+func NewNode(n bbn.Variable) (Node, error) {
+	labels := nodeLabels(&n)
 	maxStateLen := 0
-	for _, state := range n.Outcomes {
+	for _, state := range labels {
 		cnt := utf8.RuneCountInString(state)
 		if cnt > maxStateLen {
 			maxStateLen = cnt
@@ -43,7 +58,7 @@ func NewNode(n bbn.Variable) Node {
 	}
 	titleLength := min(utf8.RuneCountInString(n.Name), maxNodeLabelWidth)
 	bars := maxBars
-	if n.NodeType == ve.UtilityNode {
+	if n.NodeType == ve.UtilityNode || n.NodeType == ve.ContinuousNode {
 		bars = extraUtilityWidth
 	}
 
@@ -51,7 +66,7 @@ func NewNode(n bbn.Variable) Node {
 		X: n.Position[0],
 		Y: n.Position[1],
 		W: max(maxStateLen+bars+9, titleLength) + 4,
-		H: len(n.Outcomes) + 3,
+		H: len(labels) + 3,
 	}
 
 	if n.NodeType == ve.UtilityNode {
@@ -60,7 +75,7 @@ func NewNode(n bbn.Variable) Node {
 
 	color, err := nodeColor(&n)
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
 
 	runes := make([][]rune, bounds.H)
@@ -88,7 +103,15 @@ func NewNode(n bbn.Variable) Node {
 	node.drawTitle()
 	node.drawStateLabels()
 
-	return &node
+	return &node, nil
+}
+
+// nodeLabels returns the row labels of a node.
+func nodeLabels(n *bbn.Variable) []string {
+	if n.NodeType == ve.ContinuousNode {
+		return continuousLabels
+	}
+	return n.Outcomes
 }
 
 func nodeColor(n *bbn.Variable) (Color, error) {
@@ -101,6 +124,8 @@ func nodeColor(n *bbn.Variable) (Color, error) {
 			color = Green
 		case ve.DecisionNode:
 			color = Blue
+		case ve.ContinuousNode:
+			color = Teal
 		}
 	} else {
 		var ok bool
@@ -120,9 +145,9 @@ func (n *node) Bounds() *Bounds {
 	return &n.bounds
 }
 
-func (n *node) Render(probs []float64, selected bool, state int, evidence bool) ([][]rune, [][]Color) {
+func (n *node) Render(probs []float64, selected bool, state int, evidence EvidenceType) ([][]rune, [][]Color) {
 	n.drawBorder(selected)
-	if n.node.NodeType == ve.UtilityNode {
+	if n.node.NodeType == ve.UtilityNode || n.node.NodeType == ve.ContinuousNode {
 		n.drawUtility(probs)
 	} else {
 		n.drawBars(probs, selected, state, evidence)
@@ -170,11 +195,13 @@ func (n *node) drawTitle() {
 		n.runes[1][n.bounds.W-3] = '!'
 	} else if n.node.NodeType == ve.UtilityNode {
 		n.runes[1][n.bounds.W-3] = '$'
+	} else if n.node.NodeType == ve.ContinuousNode {
+		n.runes[1][n.bounds.W-3] = '~'
 	}
 }
 
 func (n *node) drawStateLabels() {
-	for i, label := range n.node.Outcomes {
+	for i, label := range nodeLabels(&n.node) {
 		copy(n.runes[i+2][2:n.barsX-1], []rune(label))
 	}
 	if n.node.NodeType == ve.UtilityNode {
@@ -182,7 +209,7 @@ func (n *node) drawStateLabels() {
 	}
 }
 
-func (n *node) drawBars(probs []float64, selected bool, state int, evidence bool) {
+func (n *node) drawBars(probs []float64, selected bool, state int, evidence EvidenceType) {
 	for i, p := range probs {
 		var full, frac float64
 		if !math.IsNaN(p) {
@@ -212,9 +239,12 @@ func (n *node) drawBars(probs []float64, selected bool, state int, evidence bool
 		if selected && state == i {
 			n.runes[i+2][1] = SelectionStart
 			n.runes[i+2][n.bounds.W-2] = SelectionEnd
-		} else if evidence {
+		} else if evidence == Observed {
 			n.runes[i+2][1] = EvidenceStart
 			n.runes[i+2][n.bounds.W-2] = EvidenceEnd
+		} else if evidence == Intervention {
+			n.runes[i+2][1] = InterventionStart
+			n.runes[i+2][n.bounds.W-2] = InterventionEnd
 		} else {
 			n.runes[i+2][1] = Empty
 			n.runes[i+2][n.bounds.W-2] = Empty
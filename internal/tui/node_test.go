@@ -16,9 +16,10 @@ func TestNodeRender(t *testing.T) {
 		Position: [2]int{0, 0},
 	}
 
-	uiNode := tui.NewNode(node)
+	uiNode, err := tui.NewNode(node)
+	assert.Nil(t, err)
 
-	runes, _ := uiNode.Render([]float64{0.1, 0.2, 0.7}, true, 1, false)
+	runes, _ := uiNode.Render([]float64{0.1, 0.2, 0.7}, true, 1, tui.NoEvidence)
 
 	lines := make([]string, len(runes))
 	for i, line := range runes {
@@ -34,3 +35,8 @@ func TestNodeRender(t *testing.T) {
 ║ maybe ███████░░░  70.000 ║
 ╚══════════════════════════╝`, text)
 }
+
+func TestNodeUnknownColor(t *testing.T) {
+	_, err := tui.NewNode(bbn.Variable{Name: "TestNode", Outcomes: []string{"yes", "no"}, Color: "no-color"})
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,122 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mlange-42/bbn"
+)
+
+// policyColumnWidth is the width of columns in policy reports.
+const policyColumnWidth = 12
+
+// FormatPolicyReport formats the policy report of a decision as text.
+//
+// Lists the expected utility of each alternative, the optimal action and its margin to the runner-up,
+// for each combination of parent outcomes, followed by a summary of the strategy.
+// Infeasible alternatives of constrained decisions are shown as such.
+func FormatPolicyReport(report *bbn.PolicyReport) string {
+	b := strings.Builder{}
+	fmt.Fprintf(&b, "Decision %s\n\n", report.Decision)
+
+	for _, p := range report.Parents {
+		fmt.Fprintf(&b, "%-*s ", policyColumnWidth, truncate(p, policyColumnWidth))
+	}
+	b.WriteString("|")
+	for _, alt := range report.Alternatives {
+		fmt.Fprintf(&b, " %*s", policyColumnWidth, truncate(alt, policyColumnWidth))
+	}
+	fmt.Fprintf(&b, " | %-*s %*s\n", policyColumnWidth, "Action", policyColumnWidth, "Margin")
+
+	for i := range report.Rows {
+		row := &report.Rows[i]
+		for _, o := range row.Outcomes {
+			fmt.Fprintf(&b, "%-*s ", policyColumnWidth, truncate(o, policyColumnWidth))
+		}
+		b.WriteString("|")
+		if !row.Possible {
+			for range report.Alternatives {
+				fmt.Fprintf(&b, " %*s", policyColumnWidth, "-")
+			}
+			b.WriteString(" | impossible\n")
+			continue
+		}
+		for j, u := range row.Utilities {
+			if row.Feasible != nil && !row.Feasible[j] {
+				fmt.Fprintf(&b, " %*s", policyColumnWidth, "infeasible")
+				continue
+			}
+			fmt.Fprintf(&b, " %*.3f", policyColumnWidth, u)
+		}
+		action := truncate(row.Action, policyColumnWidth)
+		if row.Tie {
+			action += " (tie)"
+		}
+		fmt.Fprintf(&b, " | %-*s %*.3f\n", policyColumnWidth, action, policyColumnWidth, row.Margin)
+	}
+
+	b.WriteString("\nStrategy\n")
+	for _, line := range policyStrategy(report) {
+		fmt.Fprintf(&b, "  %s\n", line)
+	}
+	return b.String()
+}
+
+// policyStrategy summarizes a policy in human-readable form.
+// The most frequent action is used as the default, and the conditions for other actions are listed.
+func policyStrategy(report *bbn.PolicyReport) []string {
+	conditions := map[string][]string{}
+	counts := map[string]int{}
+	for i := range report.Rows {
+		row := &report.Rows[i]
+		if !row.Possible {
+			continue
+		}
+		counts[row.Action]++
+		terms := make([]string, len(row.Outcomes))
+		for j, o := range row.Outcomes {
+			terms[j] = fmt.Sprintf("%s=%s", report.Parents[j], o)
+		}
+		conditions[row.Action] = append(conditions[row.Action], strings.Join(terms, " and "))
+	}
+
+	fallback := ""
+	for _, alt := range report.Alternatives {
+		if counts[alt] > counts[fallback] {
+			fallback = alt
+		}
+	}
+	if len(counts) == 0 {
+		return []string{"no situation can occur"}
+	}
+	if len(counts) == 1 {
+		return []string{fmt.Sprintf("always %s", fallback)}
+	}
+
+	lines := []string{}
+	for _, alt := range report.Alternatives {
+		if alt == fallback || counts[alt] == 0 {
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("%s if %s", alt, conditions[alt][0]))
+		indent := strings.Repeat(" ", len([]rune(alt))+1)
+		for _, c := range conditions[alt][1:] {
+			lines = append(lines, fmt.Sprintf("%sor %s", indent, c))
+		}
+	}
+	return append(lines, fmt.Sprintf("%s otherwise", fallback))
+}
+
+// FormatLIMIDSolution formats a summary of the solution of a limited-memory influence diagram (LIMID).
+func FormatLIMIDSolution(solution *bbn.LIMIDSolution) string {
+	convergence := fmt.Sprintf("converged after %d iterations", solution.Iterations)
+	if !solution.Converged {
+		convergence = fmt.Sprintf("not converged after %d iterations", solution.Iterations)
+	}
+	optimality := "solution is optimal (soluble LIMID)"
+	if !solution.Soluble {
+		optimality = "solution is only locally optimal (LIMID not soluble)"
+	}
+	return fmt.Sprintf("LIMID solved by single policy update, updating %s\n%s; %s",
+		strings.Join(solution.Order, ", "), convergence, optimality)
+}
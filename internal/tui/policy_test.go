@@ -0,0 +1,54 @@
+package tui_test
+
+import (
+	"testing"
+
+	"github.com/mlange-42/bbn"
+	"github.com/mlange-42/bbn/internal/tui"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatPolicyReport(t *testing.T) {
+	report := bbn.PolicyReport{
+		Decision:     "Umbrella",
+		Alternatives: []string{"yes", "no"},
+		Parents:      []string{"Forecast"},
+		Rows: []bbn.PolicyRow{
+			{Outcomes: []string{"sunny"}, Possible: true, Action: "yes", Utilities: []float64{0, 0}, Tie: true},
+			{Outcomes: []string{"rainy"}, Possible: true, Action: "yes", Utilities: []float64{-5, -100}, Margin: 95},
+			{Outcomes: []string{"snow"}, Possible: false},
+		},
+	}
+	text := tui.FormatPolicyReport(&report)
+	assert.Contains(t, text, "Decision Umbrella")
+	assert.Contains(t, text, "yes (tie)")
+	assert.Contains(t, text, "95.000")
+	assert.Contains(t, text, "impossible")
+	assert.Contains(t, text, "always yes")
+
+	report.Rows[1].Action = "no"
+	text = tui.FormatPolicyReport(&report)
+	assert.Contains(t, text, "no if Forecast=rainy")
+	assert.Contains(t, text, "yes otherwise")
+
+	report.Rows[1].Feasible = []bool{false, true}
+	text = tui.FormatPolicyReport(&report)
+	assert.Contains(t, text, "infeasible")
+
+	report.Rows = report.Rows[2:]
+	text = tui.FormatPolicyReport(&report)
+	assert.Contains(t, text, "no situation can occur")
+}
+
+func TestFormatLIMIDSolution(t *testing.T) {
+	solution := bbn.LIMIDSolution{Order: []string{"B", "A"}, Iterations: 2, Converged: true, Soluble: true}
+	text := tui.FormatLIMIDSolution(&solution)
+	assert.Contains(t, text, "updating B, A")
+	assert.Contains(t, text, "converged after 2 iterations")
+	assert.Contains(t, text, "solution is optimal")
+
+	solution.Converged, solution.Soluble = false, false
+	text = tui.FormatLIMIDSolution(&solution)
+	assert.Contains(t, text, "not converged")
+	assert.Contains(t, text, "only locally optimal")
+}
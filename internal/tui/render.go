@@ -16,8 +16,7 @@ func (a *App) render(isUpdate bool) {
 func (a *App) renderNodes() {
 	for i, node := range a.nodes {
 		data := a.marginals[node.Node().Name]
-		_, hasEvidence := a.evidence[node.Node().Name]
-		runes, colors := node.Render(data, i == a.selectedNode, a.selectedState, hasEvidence)
+		runes, colors := node.Render(data, i == a.selectedNode, a.selectedState, a.evidenceType(node.Node().Name))
 		b := node.Bounds()
 		for i, line := range runes {
 			copy(a.canvas[b.Y+i][b.X:], line)
@@ -26,6 +25,16 @@ func (a *App) renderNodes() {
 	}
 }
 
+func (a *App) evidenceType(name string) EvidenceType {
+	if _, ok := a.do[name]; ok {
+		return Intervention
+	}
+	if _, ok := a.evidence[name]; ok {
+		return Observed
+	}
+	return NoEvidence
+}
+
 func (a *App) renderEdges() {
 	for i, node := range a.nodes {
 		for _, p := range node.Node().Factor.Given {
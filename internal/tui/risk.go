@@ -0,0 +1,104 @@
+package tui
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+
+	"github.com/mlange-42/bbn"
+)
+
+// FormatRiskProfiles formats risk profiles of alternatives as text.
+// Argument level is the probability level for the value at risk.
+//
+// If the network has a utility function, expected utilities and certainty equivalents are reported.
+func FormatRiskProfiles(network *bbn.Network, labels []string, profiles []bbn.RiskProfile, level float64) string {
+	b := strings.Builder{}
+
+	hasFunction := false
+	if idx := network.TotalUtilityIndex(); idx >= 0 {
+		hasFunction = network.Variables()[idx].Factor.Utility != nil
+	}
+
+	fmt.Fprintf(&b, "%-16s %10s %10s %10s", "Alternative", "Mean", "Std.dev.", fmt.Sprintf("VaR %g%%", level*100))
+	if hasFunction {
+		fmt.Fprintf(&b, " %10s %10s", "EU", "Cert.eq.")
+	}
+	b.WriteString("\n")
+	for i := range profiles {
+		p := &profiles[i]
+		fmt.Fprintf(&b, "%-16s %10.3f %10.3f %10.3f", truncate(labels[i], 16), p.Mean(), math.Sqrt(p.Variance()), p.Quantile(level))
+		if hasFunction {
+			fmt.Fprintf(&b, " %10.3f", p.ExpectedUtility)
+			if ce, ok := network.CertaintyEquivalent(p.ExpectedUtility); ok {
+				fmt.Fprintf(&b, " %10.3f", ce)
+			} else {
+				fmt.Fprintf(&b, " %10s", "-")
+			}
+		}
+		b.WriteString("\n")
+	}
+
+	utilities := []float64{}
+	for i := range profiles {
+		utilities = append(utilities, profiles[i].Utilities...)
+	}
+	slices.Sort(utilities)
+	utilities = slices.Compact(utilities)
+
+	b.WriteString("\nRisk profile P(U = u)\n")
+	writeProfileTable(&b, labels, profiles, utilities, false)
+	b.WriteString("\nCumulative risk profile P(U <= u)\n")
+	writeProfileTable(&b, labels, profiles, utilities, true)
+
+	b.WriteString("\nStochastic dominance\n")
+	dominance := false
+	for i := range profiles {
+		for j := range profiles {
+			if i == j {
+				continue
+			}
+			order := ""
+			if profiles[i].FirstOrderDominates(&profiles[j]) {
+				order = "first"
+			} else if profiles[i].SecondOrderDominates(&profiles[j]) {
+				order = "second"
+			}
+			if order != "" {
+				fmt.Fprintf(&b, "%s dominates %s (%s order)\n", labels[i], labels[j], order)
+				dominance = true
+			}
+		}
+	}
+	if !dominance {
+		b.WriteString("none\n")
+	}
+	return b.String()
+}
+
+func writeProfileTable(b *strings.Builder, labels []string, profiles []bbn.RiskProfile, utilities []float64, cumulative bool) {
+	fmt.Fprintf(b, "%16s", "Utility")
+	for _, l := range labels {
+		fmt.Fprintf(b, " %10s", truncate(l, 10))
+	}
+	b.WriteString("\n")
+	for _, u := range utilities {
+		fmt.Fprintf(b, "%16.3f", u)
+		for i := range profiles {
+			fmt.Fprintf(b, " %9.3f%%", 100*profileValue(&profiles[i], u, cumulative))
+		}
+		b.WriteString("\n")
+	}
+}
+
+// profileValue returns P(U = u), or P(U <= u) if cumulative.
+func profileValue(p *bbn.RiskProfile, u float64, cumulative bool) float64 {
+	value := 0.0
+	for i, v := range p.Utilities {
+		if v == u || (cumulative && v < u) {
+			value += p.Probabilities[i]
+		}
+	}
+	return value
+}
@@ -0,0 +1,43 @@
+package tui_test
+
+import (
+	"testing"
+
+	"github.com/mlange-42/bbn"
+	"github.com/mlange-42/bbn/internal/tui"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatRiskProfiles(t *testing.T) {
+	net, err := bbn.FromFile("../../_examples/decision/umbrella.yml")
+	assert.Nil(t, err)
+
+	profiles := []bbn.RiskProfile{
+		{Utilities: []float64{10, 20}, Probabilities: []float64{0.5, 0.5}},
+		{Utilities: []float64{0, 20}, Probabilities: []float64{0.5, 0.5}},
+	}
+	text := tui.FormatRiskProfiles(net, []string{"A", "B"}, profiles, 0.05)
+	assert.Contains(t, text, "VaR 5%")
+	assert.Contains(t, text, "Cumulative risk profile")
+	assert.Contains(t, text, "A dominates B (first order)")
+	assert.NotContains(t, text, "B dominates A")
+	assert.NotContains(t, text, "Cert.eq.")
+
+	text = tui.FormatRiskProfiles(net, []string{"A"}, profiles[:1], 0.05)
+	assert.Contains(t, text, "none")
+}
+
+func TestFormatRiskProfilesUtility(t *testing.T) {
+	net, err := bbn.FromFile("../../_examples/decision/investment.yml")
+	assert.Nil(t, err)
+	_, err = net.SolvePolicies(true)
+	assert.Nil(t, err)
+
+	profiles, err := net.DecisionRiskProfiles(nil, "Investment")
+	assert.Nil(t, err)
+	text := tui.FormatRiskProfiles(net, []string{"stocks", "bonds"}, profiles, 0.05)
+	assert.Contains(t, text, "Cert.eq.")
+	assert.Contains(t, text, "40.000")
+	assert.Contains(t, text, "32.968")
+	assert.NotContains(t, text, "bonds dominates stocks")
+}
@@ -8,16 +8,27 @@ import (
 	"github.com/mlange-42/bbn/ve"
 )
 
-func Solve(network *bbn.Network, evidence map[string]string, nodes []Node, ignorePolicies bool) (map[string][]float64, error) {
+// Solve solves marginals for all nodes, under the given evidence and interventions.
+func Solve(network *bbn.Network, evidence map[string]string, do map[string]string, nodes []Node, ignorePolicies bool) (map[string][]float64, error) {
 	queries := []string{}
 
+	continuous := []string{}
 	for _, n := range nodes {
 		if n.Node().NodeType == ve.UtilityNode {
 			continue
 		}
+		if n.Node().NodeType == ve.ContinuousNode {
+			if _, ok := evidence[n.Node().Name]; !ok {
+				continuous = append(continuous, n.Node().Name)
+			}
+			continue
+		}
 		if _, ok := evidence[n.Node().Name]; ok {
 			continue
 		}
+		if _, ok := do[n.Node().Name]; ok {
+			continue
+		}
 		queries = append(queries, n.Node().Name)
 
 	}
@@ -27,13 +38,22 @@ func Solve(network *bbn.Network, evidence map[string]string, nodes []Node, ignor
 	if err != nil {
 		return nil, err
 	}
+	err = solveEvidence(network, do, result)
+	if err != nil {
+		return nil, err
+	}
+
+	totalProb, err := solveQueries(network, evidence, do, queries, ignorePolicies, result)
+	if err != nil {
+		return nil, err
+	}
 
-	totalProb, err := solveQueries(network, evidence, queries, ignorePolicies, result)
+	err = solveContinuous(network, evidence, do, continuous, result)
 	if err != nil {
 		return nil, err
 	}
 
-	err = solveUtility(network, nodes, evidence, totalProb, ignorePolicies, result)
+	err = solveUtility(network, nodes, evidence, do, totalProb, ignorePolicies, result)
 	if err != nil {
 		return nil, err
 	}
@@ -52,8 +72,8 @@ func solveEvidence(network *bbn.Network, evidence map[string]string, result map[
 	return nil
 }
 
-func solveQueries(network *bbn.Network, evidence map[string]string, queries []string, ignorePolicies bool, result map[string][]float64) (float64, error) {
-	_, f, err := network.SolveQuery(evidence, []string{}, ignorePolicies)
+func solveQueries(network *bbn.Network, evidence map[string]string, do map[string]string, queries []string, ignorePolicies bool, result map[string][]float64) (float64, error) {
+	_, f, err := network.SolveIntervention(do, evidence, []string{}, ignorePolicies)
 	if err != nil {
 		return 0, err
 	}
@@ -63,20 +83,34 @@ func solveQueries(network *bbn.Network, evidence map[string]string, queries []st
 	}
 
 	for _, q := range queries {
-		r, _, err := network.SolveQuery(evidence, []string{q}, ignorePolicies)
+		r, _, err := network.SolveIntervention(do, evidence, []string{q}, ignorePolicies)
 		if err != nil {
 			return 0, err
 		}
 		var ok bool
 		result[q], ok = r[q]
 		if !ok {
-			panic(fmt.Sprintf("query variable %s not in result", q))
+			return 0, fmt.Errorf("query variable %s not in result", q)
 		}
 	}
 	return totalProb, nil
 }
 
-func solveUtility(network *bbn.Network, nodes []Node, evidence map[string]string, totalProb float64, ignorePolicies bool, result map[string][]float64) error {
+func solveContinuous(network *bbn.Network, evidence map[string]string, do map[string]string, queries []string, result map[string][]float64) error {
+	if len(queries) == 0 {
+		return nil
+	}
+	r, err := network.SolveContinuous(evidence, do, queries)
+	if err != nil {
+		return err
+	}
+	for _, q := range queries {
+		result[q] = []float64{r[q].Mean, r[q].Variance}
+	}
+	return nil
+}
+
+func solveUtility(network *bbn.Network, nodes []Node, evidence map[string]string, do map[string]string, totalProb float64, ignorePolicies bool, result map[string][]float64) error {
 	utilities := []string{}
 	var totalUtilityNode *bbn.Variable
 
@@ -90,7 +124,7 @@ func solveUtility(network *bbn.Network, nodes []Node, evidence map[string]string
 		}
 	}
 
-	f, err := network.SolveUtility(evidence, []string{}, "", ignorePolicies)
+	f, err := network.SolveInterventionUtility(do, evidence, []string{}, "", ignorePolicies)
 	if err != nil {
 		return err
 	}
@@ -103,7 +137,7 @@ func solveUtility(network *bbn.Network, nodes []Node, evidence map[string]string
 	totalUtility /= totalProb
 
 	for _, n := range utilities {
-		f, err = network.SolveUtility(evidence, []string{}, n, ignorePolicies)
+		f, err = network.SolveInterventionUtility(do, evidence, []string{}, n, ignorePolicies)
 		if err != nil {
 			return err
 		}
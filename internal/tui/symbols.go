@@ -10,17 +10,19 @@ var (
 )
 
 const (
-	Empty          = ' '
-	Shade          = '░'
-	Full           = '█'
-	SelectionStart = '['
-	SelectionEnd   = ']'
-	EvidenceStart  = '+'
-	EvidenceEnd    = '+'
-	ArrowUp        = '^'
-	ArrowDown      = 'v'
-	ArrowLeft      = '<'
-	ArrowRight     = '>'
+	Empty             = ' '
+	Shade             = '░'
+	Full              = '█'
+	SelectionStart    = '['
+	SelectionEnd      = ']'
+	EvidenceStart     = '+'
+	EvidenceEnd       = '+'
+	InterventionStart = '>'
+	InterventionEnd   = '<'
+	ArrowUp           = '^'
+	ArrowDown         = 'v'
+	ArrowLeft         = '<'
+	ArrowRight        = '>'
 )
 
 var Partial = []rune{
@@ -13,14 +13,19 @@ type Table struct {
 	index       int
 	nodesByName map[string]int
 	header      []string
+	parents     []string
 	columns     int
 }
 
 func NewTable(nodes []Node, index int, nodesByName map[string]int) Table {
-	header := append([]string{}, nodes[index].Node().Factor.Given...)
-	header = append(header, nodes[index].Node().Outcomes...)
+	node := nodes[index].Node()
+	if node.NodeType == ve.ContinuousNode {
+		return newContinuousTable(nodes, index, nodesByName)
+	}
+	header := append([]string{}, node.Factor.Given...)
+	header = append(header, node.Outcomes...)
 
-	forIdx := nodesByName[nodes[index].Node().Factor.For]
+	forIdx := nodesByName[node.Factor.For]
 	forNode := nodes[forIdx]
 	columns := len(forNode.Node().Outcomes)
 
@@ -29,10 +34,39 @@ func NewTable(nodes []Node, index int, nodesByName map[string]int) Table {
 		index:       index,
 		nodesByName: nodesByName,
 		header:      header,
+		parents:     node.Factor.Given,
 		columns:     columns,
 	}
 }
 
+// newContinuousTable creates a table for a continuous node.
+// Rows are given by discrete parents, columns are intercept, coefficients of continuous parents, and variance.
+func newContinuousTable(nodes []Node, index int, nodesByName map[string]int) Table {
+	node := nodes[index].Node()
+	parents := []string{}
+	coefficients := []string{}
+	for _, p := range node.Factor.Given {
+		if nodes[nodesByName[p]].Node().NodeType == ve.ContinuousNode {
+			coefficients = append(coefficients, p)
+		} else {
+			parents = append(parents, p)
+		}
+	}
+	header := append([]string{}, parents...)
+	header = append(header, "intercept")
+	header = append(header, coefficients...)
+	header = append(header, "variance")
+
+	return Table{
+		nodes:       nodes,
+		index:       index,
+		nodesByName: nodesByName,
+		header:      header,
+		parents:     parents,
+		columns:     len(coefficients) + 2,
+	}
+}
+
 func (t *Table) GetCell(row, column int) *tview.TableCell {
 	if row == 0 {
 		cell := tview.NewTableCell(t.header[column])
@@ -43,16 +77,16 @@ func (t *Table) GetCell(row, column int) *tview.TableCell {
 	row -= 1
 
 	node := t.nodes[t.index]
-	numParents := len(node.Node().Factor.Given)
+	numParents := len(t.parents)
 
-	if column < len(node.Node().Factor.Given) {
+	if column < numParents {
 		stride := 1
-		for i := len(node.Node().Factor.Given) - 1; i > column; i-- {
-			parIdx := t.nodesByName[node.Node().Factor.Given[i]]
+		for i := numParents - 1; i > column; i-- {
+			parIdx := t.nodesByName[t.parents[i]]
 			par := t.nodes[parIdx]
 			stride *= len(par.Node().Outcomes)
 		}
-		parent := t.nodes[t.nodesByName[node.Node().Factor.Given[column]]].Node()
+		parent := t.nodes[t.nodesByName[t.parents[column]]].Node()
 		text := parent.Outcomes[(row/stride)%len(parent.Outcomes)]
 		cell := tview.NewTableCell(text)
 		cell.SetAlign(tview.AlignRight)
@@ -63,7 +97,9 @@ func (t *Table) GetCell(row, column int) *tview.TableCell {
 
 	values := node.Node().Factor.Table[row*t.columns : (row+1)*t.columns]
 
-	if node.Node().NodeType == ve.UtilityNode {
+	if allowed := node.Node().Factor.Allowed; allowed != nil && !allowed[row*t.columns+column-numParents] {
+		text = "infeasible"
+	} else if node.Node().NodeType == ve.UtilityNode || node.Node().NodeType == ve.ContinuousNode {
 		text = fmt.Sprintf("%9.3f", values[column-numParents])
 	} else {
 		sum := 0.0
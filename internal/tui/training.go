@@ -34,7 +34,10 @@ func TrainNetwork(net *bbn.Network, nodes []bbn.Variable, dataFile, noData strin
 		return nil, err
 	}
 
-	train := bbn.NewTrainer(net)
+	train, err := bbn.TryNewTrainer(net)
+	if err != nil {
+		return nil, err
+	}
 	sample := make([]int, len(nodes))
 	utility := make([]float64, len(nodes))
 
@@ -61,12 +64,17 @@ func TrainNetwork(net *bbn.Network, nodes []bbn.Variable, dataFile, noData strin
 			} else {
 				var ok bool
 				sample[i], ok = outcomes[i][record[idx]]
+				if !ok {
+					sample[i], ok = nodes[i].Outcome(record[idx])
+				}
 				if !ok {
 					return nil, fmt.Errorf("outcome '%s' not available in node '%s'", record[idx], nodes[i].Name)
 				}
 			}
 		}
-		train.AddSample(sample, utility)
+		if err := train.TryAddSample(sample, utility); err != nil {
+			return nil, err
+		}
 	}
 
 	return train.UpdateNetwork()
@@ -0,0 +1,21 @@
+package tui_test
+
+import (
+	"testing"
+
+	"github.com/mlange-42/bbn"
+	"github.com/mlange-42/bbn/internal/tui"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTrainNetworkBins(t *testing.T) {
+	net, err := bbn.FromFile("../../_examples/bbn/weather-untrained.yml")
+	assert.Nil(t, err)
+
+	net, err = tui.TrainNetwork(net, net.Variables(), "../../_examples/bbn/weather.csv", "", ',')
+	assert.Nil(t, err)
+
+	temp := net.Variables()[0].Factor.Table
+	assert.InDelta(t, 1, temp[0]+temp[1]+temp[2], 1e-9)
+	assert.Greater(t, temp[1], temp[2])
+}
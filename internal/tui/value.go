@@ -0,0 +1,40 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mlange-42/bbn"
+)
+
+// valueColumnWidth is the width of columns in value of information reports.
+const valueColumnWidth = 12
+
+// FormatValueReport formats the value of information and of control for a chance variable as text.
+func FormatValueReport(report *bbn.ValueReport) string {
+	b := strings.Builder{}
+	fmt.Fprintf(&b, "Value of %s, observed before decision %s\n\n", report.Variable, report.Decision)
+	fmt.Fprintf(&b, "%-40s %*.3f\n", "Expected utility", valueColumnWidth, report.Utility)
+	fmt.Fprintf(&b, "%-40s %*.3f\n", "Value of perfect information (EVPI)", valueColumnWidth, report.Perfect)
+	fmt.Fprintf(&b, "%-40s %*.3f\n", "Value of control", valueColumnWidth, report.Control)
+	if report.Test == nil {
+		return b.String()
+	}
+	fmt.Fprintf(&b, "%-40s %*.3f\n", "Value of sample information (EVSI)", valueColumnWidth, report.Sample)
+
+	fmt.Fprintf(&b, "\nTest of %s\n", report.Variable)
+	fmt.Fprintf(&b, "%-*s |", valueColumnWidth, truncate(report.Variable, valueColumnWidth))
+	for _, o := range report.Test.Outcomes {
+		fmt.Fprintf(&b, " %*s", valueColumnWidth, truncate(o, valueColumnWidth))
+	}
+	b.WriteString("\n")
+	cols := len(report.Test.Outcomes)
+	for i, o := range report.Outcomes {
+		fmt.Fprintf(&b, "%-*s |", valueColumnWidth, truncate(o, valueColumnWidth))
+		for _, p := range report.Test.Table[i*cols : (i+1)*cols] {
+			fmt.Fprintf(&b, " %*.3f", valueColumnWidth, p)
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
@@ -0,0 +1,33 @@
+package tui_test
+
+import (
+	"testing"
+
+	"github.com/mlange-42/bbn"
+	"github.com/mlange-42/bbn/internal/tui"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatValueReport(t *testing.T) {
+	report := bbn.ValueReport{
+		Variable: "Weather",
+		Outcomes: []string{"Sunny", "Rainy"},
+		Decision: "Umbrella",
+		Utility:  77,
+		Perfect:  14,
+		Control:  23,
+	}
+	text := tui.FormatValueReport(&report)
+	assert.Contains(t, text, "Value of Weather, observed before decision Umbrella")
+	assert.Contains(t, text, "14.000")
+	assert.Contains(t, text, "23.000")
+	assert.NotContains(t, text, "EVSI")
+
+	report.Test = bbn.NewBinaryTest(0.9, 0.8)
+	report.Sample = 5.6
+	text = tui.FormatValueReport(&report)
+	assert.Contains(t, text, "EVSI")
+	assert.Contains(t, text, "5.600")
+	assert.Contains(t, text, "positive")
+	assert.Contains(t, text, "0.900")
+}
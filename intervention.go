@@ -0,0 +1,87 @@
+package bbn
+
+import (
+	"fmt"
+
+	"github.com/mlange-42/bbn/ve"
+)
+
+// SolveIntervention solves a query under interventions, using variable elimination.
+//
+// Interventions in do are applied using Pearl's do-operator, by graph surgery:
+// incoming edges of intervened variables are cut, and their tables are replaced by point masses
+// on the given outcomes. The network itself is not modified.
+// Evidence is applied after the interventions, like in [Network.SolveQuery].
+//
+// Policies from [Network.SolvePolicies] are used for decisions that are not intervened.
+// Utility variables can't be intervened.
+//
+// Returns a map of normalized marginal probabilities for each query variable, by variable name.
+// Further, it returns the resulting factor containing the query variables.
+func (n *Network) SolveIntervention(do map[string]string, evidence map[string]string, query []string, ignorePolicies bool) (map[string][]float64, *ve.Factor, error) {
+	if err := n.checkInterventions(do, evidence); err != nil {
+		return nil, nil, err
+	}
+	f, err := n.solve(evidence, do, query, false, "", ignorePolicies)
+	if err != nil {
+		return nil, nil, err
+	}
+	result, err := n.queryMarginals(f, query)
+	if err != nil {
+		return nil, nil, err
+	}
+	return result, f, nil
+}
+
+// SolveInterventionUtility solves utility under interventions, using variable elimination.
+//
+// See [Network.SolveIntervention] for interventions, and [Network.SolveUtility] for utility.
+func (n *Network) SolveInterventionUtility(do map[string]string, evidence map[string]string, query []string, utilityVar string, ignorePolicies bool) (*ve.Factor, error) {
+	if err := n.checkInterventions(do, evidence); err != nil {
+		return nil, err
+	}
+	return n.solve(evidence, do, query, true, utilityVar, ignorePolicies)
+}
+
+// checkInterventions checks that interventions refer to existing non-utility variables and outcomes,
+// and that they don't contradict evidence.
+func (n *Network) checkInterventions(do map[string]string, evidence map[string]string) error {
+	for name, value := range do {
+		v, ok := n.variable(name)
+		if !ok {
+			return newVariableError(name, ErrUnknownVariable, "intervention variable %s not found", name)
+		}
+		if v.NodeType == ve.UtilityNode || v.NodeType == ve.ContinuousNode {
+			return fmt.Errorf("can't intervene on utility or continuous variable %s", name)
+		}
+		idx, ok := v.Outcome(value)
+		if !ok {
+			return newVariableError(name, ErrUnknownOutcome, "outcome %s for intervention variable %s not found", value, name)
+		}
+		if ev, ok := evidence[name]; ok {
+			if evIdx, ok := v.Outcome(ev); !ok || evIdx != idx {
+				return fmt.Errorf("intervention %s=%s contradicts evidence %s=%s", name, value, name, ev)
+			}
+		}
+	}
+	return nil
+}
+
+// interventionFactors creates point mass factors for intervened variables.
+func interventionFactors(vars *ve.Variables, varNames map[string]*variable, do map[string]string) ([]ve.Factor, error) {
+	factors := make([]ve.Factor, 0, len(do))
+	for name, value := range do {
+		v, ok := varNames[name]
+		if !ok {
+			return nil, newVariableError(name, ErrUnknownVariable, "intervention variable %s not found", name)
+		}
+		idx, ok := v.Variable.Outcome(value)
+		if !ok {
+			return nil, newVariableError(name, ErrUnknownOutcome, "outcome %s for intervention variable %s not found", value, name)
+		}
+		table := make([]float64, len(v.Variable.Outcomes))
+		table[idx] = 1
+		factors = append(factors, vars.CreateFactor([]ve.Variable{v.VeVariable}, table))
+	}
+	return factors, nil
+}
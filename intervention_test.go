@@ -0,0 +1,73 @@
+package bbn_test
+
+import (
+	"testing"
+
+	"github.com/mlange-42/bbn"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNetworkSolveIntervention(t *testing.T) {
+	net, err := bbn.FromFile("_examples/bbn/sprinkler.yml")
+	assert.Nil(t, err)
+
+	observed, _, err := net.SolveQuery(map[string]string{"Sprinkler": "yes"}, []string{"Rain"}, false)
+	assert.Nil(t, err)
+	assert.InDelta(t, 0.002/(0.002+0.16), observed["Rain"][0], 1e-9)
+
+	result, _, err := net.SolveIntervention(map[string]string{"Sprinkler": "yes"}, nil, []string{"Rain", "Sprinkler", "GrassWet"}, false)
+	assert.Nil(t, err)
+	assert.InDelta(t, 0.2, result["Rain"][0], 1e-9)
+	assert.Equal(t, []float64{1, 0}, result["Sprinkler"])
+	assert.InDelta(t, 0.2*0.99+0.8*0.9, result["GrassWet"][0], 1e-9)
+
+	result, _, err = net.SolveIntervention(map[string]string{"Sprinkler": "yes"}, map[string]string{"GrassWet": "yes"}, []string{"Rain"}, false)
+	assert.Nil(t, err)
+	assert.InDelta(t, 0.2*0.99/(0.2*0.99+0.8*0.9), result["Rain"][0], 1e-9)
+
+	// network is unchanged
+	observed2, _, err := net.SolveQuery(map[string]string{"Sprinkler": "yes"}, []string{"Rain"}, false)
+	assert.Nil(t, err)
+	assert.Equal(t, observed, observed2)
+}
+
+func TestNetworkSolveInterventionErrors(t *testing.T) {
+	net, err := bbn.FromFile("_examples/bbn/sprinkler.yml")
+	assert.Nil(t, err)
+
+	_, _, err = net.SolveIntervention(map[string]string{"X": "yes"}, nil, []string{"Rain"}, false)
+	assert.ErrorIs(t, err, bbn.ErrUnknownVariable)
+
+	_, _, err = net.SolveIntervention(map[string]string{"Rain": "maybe"}, nil, []string{"Rain"}, false)
+	assert.ErrorIs(t, err, bbn.ErrUnknownOutcome)
+
+	_, _, err = net.SolveIntervention(map[string]string{"Rain": "yes"}, map[string]string{"Rain": "no"}, []string{"Sprinkler"}, false)
+	assert.NotNil(t, err)
+}
+
+func TestNetworkSolveInterventionUtility(t *testing.T) {
+	net, err := bbn.NewBuilder("Umbrella", "").
+		AddChance("Rain", "yes", "no").
+		AddDecision("Umbrella", "yes", "no").
+		AddUtility("Wet").
+		AddEdge("Rain", "Wet").
+		AddEdge("Umbrella", "Wet").
+		SetTable("Rain", []float64{0.3, 0.7}).
+		SetTable("Wet", []float64{0, -100, -5, 0}).
+		Build()
+	assert.Nil(t, err)
+
+	_, err = net.SolvePolicies(false)
+	assert.Nil(t, err)
+
+	u, err := net.SolveInterventionUtility(map[string]string{"Umbrella": "no"}, nil, nil, "", false)
+	assert.Nil(t, err)
+	assert.InDelta(t, -30, u.Data()[0], 1e-9)
+
+	u, err = net.SolveInterventionUtility(map[string]string{"Umbrella": "yes"}, nil, nil, "", false)
+	assert.Nil(t, err)
+	assert.InDelta(t, -3.5, u.Data()[0], 1e-9)
+
+	_, err = net.SolveInterventionUtility(map[string]string{"Wet": "utility"}, nil, nil, "", false)
+	assert.NotNil(t, err)
+}
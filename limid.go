@@ -0,0 +1,280 @@
+package bbn
+
+import (
+	"fmt"
+	"math"
+	"slices"
+
+	"github.com/mlange-42/bbn/ve"
+)
+
+// limidIterations is the maximum number of single policy update iterations in [Network.SolvePolicies].
+const limidIterations = 100
+
+// LIMIDSolution reports on the solution of a limited-memory influence diagram (LIMID).
+// See [Network.SolveLIMID].
+type LIMIDSolution struct {
+	Policies   map[string]Factor // Policies for each decision variable, by variable name.
+	Order      []string          // Decisions in the order of policy updates.
+	Iterations int               // Number of iterations over all decisions.
+	Converged  bool              // Whether policies converged within the maximum number of iterations.
+	Soluble    bool              // Whether the LIMID is soluble, i.e. the solution is guaranteed to be optimal. Otherwise, it is only locally optimal.
+}
+
+// LIMID returns whether the network is a limited-memory influence diagram (LIMID).
+// See [Network.SetLIMID].
+func (n *Network) LIMID() bool {
+	return n.limid
+}
+
+// SetLIMID sets whether the network is a limited-memory influence diagram (LIMID).
+//
+// In a LIMID, decisions only observe the parents they declare, and don't remember earlier observations and decisions.
+// [Network.SolvePolicies] then uses [Network.SolveLIMID].
+func (n *Network) SetLIMID(limid bool) {
+	n.limid = limid
+	clear(n.policies)
+}
+
+// solveLIMIDPolicies solves policies for [Network.SolvePolicies] in LIMID mode.
+func (n *Network) solveLIMIDPolicies() (map[string]Factor, error) {
+	solution, err := n.SolveLIMID(limidIterations)
+	if err != nil {
+		return nil, err
+	}
+	if !solution.Converged {
+		return nil, fmt.Errorf("%w: policies did not converge within %d iterations", ErrNoPolicy, limidIterations)
+	}
+	return solution.Policies, nil
+}
+
+// SolveLIMID solves and inserts policies for decisions by single policy update (SPU),
+// treating the network as a limited-memory influence diagram (LIMID).
+//
+// Starting from uniform policies, the policy of each decision is replaced by the optimal one,
+// given the policies of all other decisions, until no policy changes, or for at most maxIterations iterations.
+// Policies only depend on the declared parents of decisions.
+//
+// If the LIMID is soluble (Lauritzen & Nilsson, 2001), decisions are updated in an exact solution order,
+// and the solution is optimal. Otherwise, it is only locally optimal.
+func (n *Network) SolveLIMID(maxIterations int) (*LIMIDSolution, error) {
+	clear(n.policies)
+
+	topological, err := n.TopologicalOrder()
+	if err != nil {
+		return nil, err
+	}
+	decisions := []string{}
+	for _, name := range topological {
+		if v, _ := n.variable(name); v.NodeType == ve.DecisionNode {
+			decisions = append(decisions, name)
+		}
+	}
+	if err := n.uniformPolicies(decisions); err != nil {
+		return nil, err
+	}
+
+	order, soluble := n.limidOrder(decisions)
+	solution := LIMIDSolution{Order: order, Soluble: soluble}
+	for solution.Iterations < maxIterations && !solution.Converged {
+		solution.Iterations++
+		solution.Converged = true
+		for _, name := range order {
+			changed, err := n.updatePolicy(name)
+			if err != nil {
+				return nil, err
+			}
+			solution.Converged = solution.Converged && !changed
+		}
+	}
+
+	solution.Policies = map[string]Factor{}
+	for _, name := range decisions {
+		v, _ := n.variable(name)
+		policy := n.policies[name]
+		solution.Policies[name] = Factor{
+			For:     name,
+			Given:   slices.Clone(declaredParents(v)),
+			Table:   slices.Clone(policy.Data()),
+			Allowed: slices.Clone(declaredAllowed(v)),
+		}
+	}
+	return &solution, nil
+}
+
+// uniformPolicies inserts uniform policies over their declared parents for the given decisions.
+// For constrained decisions, policies are uniform over the allowed outcomes.
+func (n *Network) uniformPolicies(decisions []string) error {
+	var err error
+	n.ve, n.variableNames, err = n.toVE(nil, nil, nil)
+	if err != nil {
+		return err
+	}
+	for _, name := range decisions {
+		v, _ := n.variable(name)
+		parents := declaredParents(v)
+		variables := make([]ve.Variable, 0, len(parents)+1)
+		size := len(v.Outcomes)
+		for _, p := range parents {
+			variables = append(variables, n.variableNames[p].VeVariable)
+			pv, _ := n.variable(p)
+			size *= len(pv.Outcomes)
+		}
+		variables = append(variables, n.variableNames[name].VeVariable)
+
+		data := make([]float64, size)
+		columns := len(v.Outcomes)
+		allowed := declaredAllowed(v)
+		for row := 0; row < size/columns; row++ {
+			count := columns
+			if allowed != nil {
+				count = 0
+				for _, a := range allowed[row*columns : (row+1)*columns] {
+					if a {
+						count++
+					}
+				}
+			}
+			for i := 0; i < columns; i++ {
+				if allowed == nil || allowed[row*columns+i] {
+					data[row*columns+i] = 1 / float64(count)
+				}
+			}
+		}
+		n.policies[name] = n.ve.Variables().CreateFactor(variables, data)
+	}
+	return nil
+}
+
+// updatePolicy replaces the policy of a decision by the optimal one, given the policies of all other decisions.
+// Only allowed outcomes are considered for constrained decisions.
+// Returns whether the policy changed.
+func (n *Network) updatePolicy(decision string) (bool, error) {
+	v, _ := n.variable(decision)
+	parents := declaredParents(v)
+	alternatives := len(v.Outcomes)
+
+	utilities := make([][]float64, alternatives)
+	for i, alt := range v.Outcomes {
+		u, err := n.SolveUtility(map[string]string{decision: alt}, parents, "", true)
+		if err != nil {
+			return false, err
+		}
+		if len(parents) == 0 {
+			utilities[i] = u.Data()
+			continue
+		}
+		rearranged, err := n.TryRearrange(u, parents)
+		if err != nil {
+			return false, err
+		}
+		utilities[i] = rearranged.Data()
+	}
+
+	policy := n.policies[decision]
+	data := policy.Data()
+	allowed := declaredAllowed(v)
+	changed := false
+	for row := range utilities[0] {
+		current := slices.Index(data[row*alternatives:(row+1)*alternatives], 1)
+		best := -1
+		for i := range utilities {
+			if allowed != nil && !allowed[row*alternatives+i] {
+				continue
+			}
+			if best < 0 || utilities[i][row] > utilities[best][row] {
+				best = i
+			}
+		}
+		// keep the current action on ties, to guarantee convergence
+		tolerance := optimalTolerance * math.Max(1, math.Abs(utilities[best][row]))
+		if current >= 0 && utilities[current][row] >= utilities[best][row]-tolerance {
+			continue
+		}
+		changed = true
+		for i := 0; i < alternatives; i++ {
+			data[row*alternatives+i] = 0
+		}
+		data[row*alternatives+best] = 1
+	}
+	return changed, nil
+}
+
+// limidOrder returns the order of decisions for single policy update, and whether the LIMID is soluble.
+//
+// A LIMID is soluble if its relevance graph is acyclic.
+// Then, each decision is updated after all decisions it requires, which is an exact solution order.
+// Otherwise, decisions are updated in reverse topological order.
+func (n *Network) limidOrder(decisions []string) ([]string, bool) {
+	requires := map[string][]string{}
+	for _, d := range decisions {
+		for _, other := range decisions {
+			if other != d && n.isRelevant(other, d) {
+				requires[d] = append(requires[d], other)
+			}
+		}
+	}
+
+	order := []string{}
+	done := map[string]bool{}
+	for len(order) < len(decisions) {
+		found := false
+		for i := len(decisions) - 1; i >= 0; i-- {
+			d := decisions[i]
+			if done[d] || slices.ContainsFunc(requires[d], func(r string) bool { return !done[r] }) {
+				continue
+			}
+			order = append(order, d)
+			done[d] = true
+			found = true
+			break
+		}
+		if !found {
+			reversed := slices.Clone(decisions)
+			slices.Reverse(reversed)
+			return reversed, false
+		}
+	}
+	return order, true
+}
+
+// isRelevant checks whether the policy of decision other is relevant for decision d.
+// That is, whether the utility descendants of d are not d-separated from a policy node of other,
+// given d and its declared parents.
+func (n *Network) isRelevant(other string, d string) bool {
+	dIdx, _ := n.variableIdx(d)
+	otherIdx, _ := n.variableIdx(other)
+
+	observed := make([]bool, len(n.variables))
+	observed[dIdx] = true
+	for _, p := range declaredParents(&n.variables[dIdx]) {
+		idx, _ := n.variableIdx(p)
+		observed[idx] = true
+	}
+
+	// a parentless policy node enters the other decision from above
+	reachable := n.reachableFrom(trailStep{otherIdx, false}, observed)
+	for i, desc := range n.descendantIndices(dIdx) {
+		if desc && reachable[i] && n.variables[i].NodeType == ve.UtilityNode {
+			return true
+		}
+	}
+	return false
+}
+
+// declaredParents returns the declared parents of a variable.
+func declaredParents(v *Variable) []string {
+	if v.Factor == nil {
+		return nil
+	}
+	return v.Factor.Given
+}
+
+// declaredAllowed returns the allowed outcomes of a decision, in the layout of its declared parents.
+// Returns nil if the decision is not constrained.
+func declaredAllowed(v *Variable) []bool {
+	if v.Factor == nil {
+		return nil
+	}
+	return v.Factor.Allowed
+}
@@ -0,0 +1,108 @@
+package bbn_test
+
+import (
+	"testing"
+
+	"github.com/mlange-42/bbn"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNetworkSolveLIMID(t *testing.T) {
+	net, err := bbn.FromFile("_examples/decision/maintenance.yml")
+	assert.Nil(t, err)
+	assert.True(t, net.LIMID())
+
+	solution, err := net.SolveLIMID(100)
+	assert.Nil(t, err)
+	assert.True(t, solution.Converged)
+	assert.False(t, solution.Soluble)
+	assert.Equal(t, []string{"Replace", "Inspect"}, solution.Order)
+
+	assert.Equal(t, []string{"Vibration"}, solution.Policies["Inspect"].Given)
+	assert.Equal(t, []float64{1, 0, 0, 1}, solution.Policies["Inspect"].Table)
+	assert.Equal(t, []string{"Report"}, solution.Policies["Replace"].Given)
+	assert.Equal(t, []float64{0, 1, 1, 0, 1, 0}, solution.Policies["Replace"].Table)
+
+	utility, err := net.SolveUtility(nil, nil, "", false)
+	assert.Nil(t, err)
+	assert.InDelta(t, -24.25, utility.Data()[0], 1e-9)
+
+	policies, err := net.SolvePolicies(true)
+	assert.Nil(t, err)
+	assert.Equal(t, solution.Policies, policies)
+
+	solution, err = net.SolveLIMID(1)
+	assert.Nil(t, err)
+	assert.False(t, solution.Converged)
+	assert.Equal(t, 1, solution.Iterations)
+}
+
+func TestNetworkSolveLIMIDSoluble(t *testing.T) {
+	net, err := bbn.FromFile("_examples/decision/oil.yml")
+	assert.Nil(t, err)
+	assert.False(t, net.LIMID())
+
+	policies, err := net.SolvePolicies(true)
+	assert.Nil(t, err)
+
+	net.SetLIMID(true)
+	solution, err := net.SolveLIMID(100)
+	assert.Nil(t, err)
+	assert.True(t, solution.Soluble)
+	assert.True(t, solution.Converged)
+	assert.Equal(t, []string{"Do drill", "Do test drill"}, solution.Order)
+	for name, p := range policies {
+		assert.Equal(t, p.Table, solution.Policies[name].Table)
+	}
+
+	utility, err := net.SolveUtility(nil, nil, "", false)
+	assert.Nil(t, err)
+	assert.InDelta(t, 22.5, utility.Data()[0], 1e-9)
+}
+
+func TestNetworkSolveLIMIDForgetting(t *testing.T) {
+	net, err := bbn.FromFile("_examples/decision/robot.yml")
+	assert.Nil(t, err)
+
+	policies, err := net.SolvePolicies(true)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"Short path"}, policies["Wear pads"].Given)
+
+	net.SetLIMID(true)
+	policies, err = net.SolvePolicies(true)
+	assert.Nil(t, err)
+	assert.Empty(t, policies["Wear pads"].Given)
+	assert.Equal(t, 2, len(policies["Wear pads"].Table))
+}
+
+func TestBuilderLIMID(t *testing.T) {
+	net, err := bbn.NewBuilder("Umbrella", "").
+		AddChance("Rain", "yes", "no").
+		AddChance("Forecast", "rain", "sun").
+		AddDecision("Umbrella", "yes", "no").
+		AddUtility("Wet").
+		AddEdge("Rain", "Forecast").
+		AddEdge("Forecast", "Umbrella").
+		AddEdge("Rain", "Wet").
+		AddEdge("Umbrella", "Wet").
+		SetTable("Rain", []float64{0.3, 0.7}).
+		SetTable("Forecast", []float64{0.8, 0.2, 0.1, 0.9}).
+		SetTable("Wet", []float64{-5, -100, -20, 0}).
+		SetLIMID(true).
+		Build()
+	assert.Nil(t, err)
+	assert.True(t, net.LIMID())
+
+	solution, err := net.SolveLIMID(100)
+	assert.Nil(t, err)
+	assert.True(t, solution.Soluble)
+	assert.Equal(t, []float64{1, 0, 0, 1}, solution.Policies["Umbrella"].Table)
+
+	yml, err := bbn.ToYAML(net)
+	assert.Nil(t, err)
+	assert.Contains(t, string(yml), "limid: true")
+
+	net2, err := bbn.FromYAML(yml)
+	assert.Nil(t, err)
+	assert.True(t, net2.LIMID())
+}
@@ -9,6 +9,9 @@
 //   - "And" becomes "and"
 //   - "IfThen" becomes "if-then"
 //
+// Custom logic factors can be added using [Register].
+// They are then also available in YAML network files.
+//
 // Most logic factors work with binary True/False outcomes.
 // The first possible outcome (index 0) is considered True,
 // while the second outcome (index 1) is considered False.
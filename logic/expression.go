@@ -0,0 +1,426 @@
+package logic
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// Expression is a boolean expression over parent variables, compiled by [ParseExpression].
+//
+// Expressions refer to parents by name, and support the following, in order of increasing precedence:
+//   - iff: biconditional
+//   - implies: material implication, right-associative
+//   - or: disjunction
+//   - xor: exclusive disjunction
+//   - and: conjunction
+//   - not: negation
+//
+// Further, parentheses, the constants true and false, and comparisons of parents with outcomes
+// using == and != are supported, like "Weather == sunny".
+// A parent name without comparison is true if the parent has its first outcome,
+// and is only allowed for parents with two outcomes.
+//
+// Names and outcomes that contain spaces or other special characters can be quoted with single quotes,
+// like 'Court Order' == yes.
+type Expression struct {
+	source string
+	root   exprNode
+}
+
+// SyntaxError is returned by [ParseExpression] and [Expression.Table] for invalid expressions.
+type SyntaxError struct {
+	Expression string // The erroneous expression.
+	Position   int    // Position of the offending token in the expression, in characters starting at 0.
+	Token      string // The offending token. Empty at the end of the expression.
+	Message    string // Description of the error.
+}
+
+// Error implements the error interface.
+func (e *SyntaxError) Error() string {
+	if e.Token == "" {
+		return fmt.Sprintf("%s at end of expression \"%s\"", e.Message, e.Expression)
+	}
+	return fmt.Sprintf("%s at position %d ('%s') in expression \"%s\"", e.Message, e.Position+1, e.Token, e.Expression)
+}
+
+// ParseExpression parses a boolean expression. See [Expression] for the syntax.
+//
+// Returns a [*SyntaxError] for invalid expressions.
+func ParseExpression(expr string) (*Expression, error) {
+	tokens, err := tokenize(expr)
+	if err != nil {
+		return nil, err
+	}
+	p := parser{source: expr, tokens: tokens}
+	root, err := p.parseIff()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != tokenEnd {
+		return nil, p.error(tok, "unexpected token")
+	}
+	return &Expression{source: expr, root: root}, nil
+}
+
+// Variables returns the names of all parents referenced by the expression, in order of first occurrence.
+func (e *Expression) Variables() []string {
+	names := []string{}
+	e.root.variables(&names)
+	return names
+}
+
+// Table returns the CPT for a binary variable with the given parents and their outcomes.
+// The first outcome (index 0) is True, the second one (index 1) is False.
+//
+// Returns a [*SyntaxError] for unknown parents or outcomes.
+func (e *Expression) Table(parents []string, outcomes [][]string) ([]float64, error) {
+	if len(parents) != len(outcomes) {
+		return nil, fmt.Errorf("got %d parents, but outcomes for %d", len(parents), len(outcomes))
+	}
+	ctx := exprContext{expr: e, parents: parents, outcomes: outcomes}
+	if err := e.root.check(&ctx); err != nil {
+		return nil, err
+	}
+
+	rows := 1
+	for _, o := range outcomes {
+		rows *= len(o)
+	}
+	table := make([]float64, rows*2)
+	ctx.values = make([]int, len(parents))
+	for row := 0; row < rows; row++ {
+		if e.root.eval(&ctx) {
+			table[row*2] = 1
+		} else {
+			table[row*2+1] = 1
+		}
+		for i := len(ctx.values) - 1; i >= 0; i-- {
+			ctx.values[i]++
+			if ctx.values[i] < len(outcomes[i]) {
+				break
+			}
+			ctx.values[i] = 0
+		}
+	}
+	return table, nil
+}
+
+// String returns the source of the expression.
+func (e *Expression) String() string {
+	return e.source
+}
+
+// exprContext holds parent information for checking and evaluating expressions.
+type exprContext struct {
+	expr     *Expression
+	parents  []string
+	outcomes [][]string
+	values   []int
+}
+
+// error creates a [*SyntaxError] for the given token.
+func (c *exprContext) error(tok token, format string, args ...any) error {
+	return &SyntaxError{Expression: c.expr.source, Position: tok.pos, Token: tok.text, Message: fmt.Sprintf(format, args...)}
+}
+
+// exprNode is a node in the syntax tree of an expression.
+type exprNode interface {
+	check(ctx *exprContext) error
+	eval(ctx *exprContext) bool
+	variables(names *[]string)
+}
+
+// constNode is a constant true or false.
+type constNode struct {
+	value bool
+}
+
+func (n *constNode) check(ctx *exprContext) error { return nil }
+func (n *constNode) eval(ctx *exprContext) bool   { return n.value }
+func (n *constNode) variables(names *[]string)    {}
+
+// notNode is a negation.
+type notNode struct {
+	operand exprNode
+}
+
+func (n *notNode) check(ctx *exprContext) error { return n.operand.check(ctx) }
+func (n *notNode) eval(ctx *exprContext) bool   { return !n.operand.eval(ctx) }
+func (n *notNode) variables(names *[]string)    { n.operand.variables(names) }
+
+// binaryNode is a binary logic operation.
+type binaryNode struct {
+	op    string
+	left  exprNode
+	right exprNode
+}
+
+func (n *binaryNode) check(ctx *exprContext) error {
+	if err := n.left.check(ctx); err != nil {
+		return err
+	}
+	return n.right.check(ctx)
+}
+
+func (n *binaryNode) eval(ctx *exprContext) bool {
+	a, b := n.left.eval(ctx), n.right.eval(ctx)
+	switch n.op {
+	case "and":
+		return a && b
+	case "or":
+		return a || b
+	case "xor":
+		return a != b
+	case "implies":
+		return !a || b
+	default: // iff
+		return a == b
+	}
+}
+
+func (n *binaryNode) variables(names *[]string) {
+	n.left.variables(names)
+	n.right.variables(names)
+}
+
+// compareNode compares a parent with an outcome.
+// Without an outcome, it checks whether a binary parent has its first outcome.
+type compareNode struct {
+	name    token
+	outcome *token
+	negate  bool
+	parent  int
+	value   int
+}
+
+func (n *compareNode) check(ctx *exprContext) error {
+	n.parent = slices.Index(ctx.parents, n.name.text)
+	if n.parent < 0 {
+		return ctx.error(n.name, "unknown parent %s; parents are %v", n.name.text, ctx.parents)
+	}
+	outcomes := ctx.outcomes[n.parent]
+	if n.outcome == nil {
+		if len(outcomes) != 2 {
+			return ctx.error(n.name, "parent %s has %d outcomes; compare it with an outcome using == or !=", n.name.text, len(outcomes))
+		}
+		n.value = 0
+		return nil
+	}
+	n.value = slices.Index(outcomes, n.outcome.text)
+	if n.value < 0 {
+		return ctx.error(*n.outcome, "unknown outcome %s of parent %s; outcomes are %v", n.outcome.text, n.name.text, outcomes)
+	}
+	return nil
+}
+
+func (n *compareNode) eval(ctx *exprContext) bool {
+	return (ctx.values[n.parent] == n.value) != n.negate
+}
+
+func (n *compareNode) variables(names *[]string) {
+	if !slices.Contains(*names, n.name.text) {
+		*names = append(*names, n.name.text)
+	}
+}
+
+type tokenKind uint8
+
+const (
+	tokenEnd tokenKind = iota
+	tokenName
+	tokenKeyword
+	tokenOperator
+	tokenOpen
+	tokenClose
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+var keywords = []string{"and", "or", "xor", "not", "implies", "iff", "true", "false"}
+
+// tokenize splits an expression into tokens.
+func tokenize(expr string) ([]token, error) {
+	runes := []rune(expr)
+	tokens := []token{}
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(' || r == ')':
+			kind := tokenOpen
+			if r == ')' {
+				kind = tokenClose
+			}
+			tokens = append(tokens, token{kind: kind, text: string(r), pos: i})
+			i++
+		case (r == '=' || r == '!') && i+1 < len(runes) && runes[i+1] == '=':
+			tokens = append(tokens, token{kind: tokenOperator, text: string(runes[i : i+2]), pos: i})
+			i += 2
+		case r == '\'':
+			end := slices.Index(runes[i+1:], '\'')
+			if end < 0 {
+				return nil, &SyntaxError{Expression: expr, Position: i, Token: string(runes[i:]), Message: "unterminated quote"}
+			}
+			tokens = append(tokens, token{kind: tokenName, text: string(runes[i+1 : i+1+end]), pos: i})
+			i += end + 2
+		case isNameRune(r):
+			var tok token
+			tok, i = nameToken(runes, i)
+			tokens = append(tokens, tok)
+		default:
+			return nil, &SyntaxError{Expression: expr, Position: i, Token: string(r), Message: "invalid character"}
+		}
+	}
+	return append(tokens, token{kind: tokenEnd, pos: len(runes)}), nil
+}
+
+// nameToken reads a name or keyword starting at position start.
+// Returns the token and the position after it.
+func nameToken(runes []rune, start int) (token, int) {
+	i := start
+	for i < len(runes) && isNameRune(runes[i]) {
+		i++
+	}
+	text := string(runes[start:i])
+	if slices.Contains(keywords, strings.ToLower(text)) {
+		return token{kind: tokenKeyword, text: text, pos: start}, i
+	}
+	return token{kind: tokenName, text: text, pos: start}, i
+}
+
+func isNameRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '.' || r == '-'
+}
+
+// parser is a recursive descent parser for expressions.
+type parser struct {
+	source string
+	tokens []token
+	pos    int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokenEnd {
+		p.pos++
+	}
+	return tok
+}
+
+// accept consumes the next token if it is the given keyword.
+func (p *parser) accept(keyword string) bool {
+	if tok := p.peek(); tok.kind == tokenKeyword && strings.EqualFold(tok.text, keyword) {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *parser) error(tok token, message string) error {
+	return &SyntaxError{Expression: p.source, Position: tok.pos, Token: tok.text, Message: message}
+}
+
+func (p *parser) parseIff() (exprNode, error) {
+	return p.parseBinary("iff", p.parseImplies)
+}
+
+func (p *parser) parseImplies() (exprNode, error) {
+	left, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if !p.accept("implies") {
+		return left, nil
+	}
+	right, err := p.parseImplies()
+	if err != nil {
+		return nil, err
+	}
+	return &binaryNode{op: "implies", left: left, right: right}, nil
+}
+
+func (p *parser) parseOr() (exprNode, error) {
+	return p.parseBinary("or", p.parseXor)
+}
+
+func (p *parser) parseXor() (exprNode, error) {
+	return p.parseBinary("xor", p.parseAnd)
+}
+
+func (p *parser) parseAnd() (exprNode, error) {
+	return p.parseBinary("and", p.parseUnary)
+}
+
+// parseBinary parses a left-associative chain of a binary operator.
+func (p *parser) parseBinary(op string, operand func() (exprNode, error)) (exprNode, error) {
+	left, err := operand()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept(op) {
+		right, err := operand()
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryNode{op: op, left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseUnary() (exprNode, error) {
+	if p.accept("not") {
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &notNode{operand: operand}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *parser) parsePrimary() (exprNode, error) {
+	tok := p.next()
+	switch {
+	case tok.kind == tokenOpen:
+		node, err := p.parseIff()
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.next(); closing.kind != tokenClose {
+			return nil, p.error(closing, "expected ')'")
+		}
+		return node, nil
+	case tok.kind == tokenKeyword && (strings.EqualFold(tok.text, "true") || strings.EqualFold(tok.text, "false")):
+		return &constNode{value: strings.EqualFold(tok.text, "true")}, nil
+	case tok.kind == tokenName:
+		return p.parseComparison(tok)
+	case tok.kind == tokenEnd:
+		return nil, p.error(tok, "unexpected end of expression")
+	default:
+		return nil, p.error(tok, "expected parent name, constant or '('")
+	}
+}
+
+func (p *parser) parseComparison(name token) (exprNode, error) {
+	op := p.peek()
+	if op.kind != tokenOperator {
+		return &compareNode{name: name}, nil
+	}
+	p.pos++
+	outcome := p.next()
+	if outcome.kind != tokenName && outcome.kind != tokenKeyword {
+		return nil, p.error(outcome, "expected outcome after "+op.text)
+	}
+	return &compareNode{name: name, outcome: &outcome, negate: op.text == "!="}, nil
+}
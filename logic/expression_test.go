@@ -0,0 +1,131 @@
+package logic_test
+
+import (
+	"testing"
+
+	"github.com/mlange-42/bbn/logic"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExpressionTable(t *testing.T) {
+	binary := []string{"yes", "no"}
+
+	tests := []struct {
+		expr     string
+		expected []float64
+	}{
+		{"A and B", []float64{1, 0, 0, 1, 0, 1, 0, 1}},
+		{"A or B", []float64{1, 0, 1, 0, 1, 0, 0, 1}},
+		{"A xor B", []float64{0, 1, 1, 0, 1, 0, 0, 1}},
+		{"A implies B", []float64{1, 0, 0, 1, 1, 0, 1, 0}},
+		{"A iff B", []float64{1, 0, 0, 1, 0, 1, 1, 0}},
+		{"not A", []float64{0, 1, 0, 1, 1, 0, 1, 0}},
+		{"A AND NOT B", []float64{0, 1, 1, 0, 0, 1, 0, 1}},
+		{"A == no or B != yes", []float64{0, 1, 1, 0, 1, 0, 1, 0}},
+		{"true", []float64{1, 0, 1, 0, 1, 0, 1, 0}},
+		{"(A or false) and B", []float64{1, 0, 0, 1, 0, 1, 0, 1}},
+	}
+	for _, tt := range tests {
+		e, err := logic.ParseExpression(tt.expr)
+		assert.Nil(t, err, tt.expr)
+		table, err := e.Table([]string{"A", "B"}, [][]string{binary, binary})
+		assert.Nil(t, err, tt.expr)
+		assert.Equal(t, tt.expected, table, tt.expr)
+	}
+}
+
+func TestExpressionPrecedence(t *testing.T) {
+	binary := []string{"yes", "no"}
+	parents := []string{"A", "B", "C"}
+	outcomes := [][]string{binary, binary, binary}
+
+	e1, err := logic.ParseExpression("A and not B or C")
+	assert.Nil(t, err)
+	e2, err := logic.ParseExpression("(A and (not B)) or C")
+	assert.Nil(t, err)
+	t1, err := e1.Table(parents, outcomes)
+	assert.Nil(t, err)
+	t2, err := e2.Table(parents, outcomes)
+	assert.Nil(t, err)
+	assert.Equal(t, t2, t1)
+
+	e1, err = logic.ParseExpression("A implies B implies C")
+	assert.Nil(t, err)
+	e2, err = logic.ParseExpression("A implies (B implies C)")
+	assert.Nil(t, err)
+	t1, err = e1.Table(parents, outcomes)
+	assert.Nil(t, err)
+	t2, err = e2.Table(parents, outcomes)
+	assert.Nil(t, err)
+	assert.Equal(t, t2, t1)
+}
+
+func TestExpressionMultiOutcome(t *testing.T) {
+	e, err := logic.ParseExpression("Weather == sunny and not 'Day Off'")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"Weather", "Day Off"}, e.Variables())
+
+	table, err := e.Table(
+		[]string{"Weather", "Day Off"},
+		[][]string{{"sunny", "cloudy", "rainy"}, {"yes", "no"}},
+	)
+	assert.Nil(t, err)
+	assert.Equal(t, []float64{
+		0, 1, // sunny, yes
+		1, 0, // sunny, no
+		0, 1, // cloudy, yes
+		0, 1, // cloudy, no
+		0, 1, // rainy, yes
+		0, 1, // rainy, no
+	}, table)
+
+	_, err = e.Table([]string{"Weather"}, [][]string{{"sunny", "cloudy"}})
+	assert.NotNil(t, err)
+}
+
+func TestExpressionErrors(t *testing.T) {
+	tests := []struct {
+		expr     string
+		position int
+		token    string
+	}{
+		{"A and", 5, ""},
+		{"A and and B", 6, "and"},
+		{"(A or B", 7, ""},
+		{"A or B)", 6, ")"},
+		{"A # B", 2, "#"},
+		{"A == ", 5, ""},
+		{"'A or B", 0, "'A or B"},
+		{"A B", 2, "B"},
+	}
+	for _, tt := range tests {
+		_, err := logic.ParseExpression(tt.expr)
+		assert.NotNil(t, err, tt.expr)
+		var syntaxErr *logic.SyntaxError
+		if assert.ErrorAs(t, err, &syntaxErr, tt.expr) {
+			assert.Equal(t, tt.position, syntaxErr.Position, tt.expr)
+			assert.Equal(t, tt.token, syntaxErr.Token, tt.expr)
+		}
+	}
+
+	binary := []string{"yes", "no"}
+	tableTests := []struct {
+		expr     string
+		position int
+		token    string
+	}{
+		{"A and C", 6, "C"},
+		{"A == maybe", 5, "maybe"},
+		{"W", 0, "W"},
+	}
+	for _, tt := range tableTests {
+		e, err := logic.ParseExpression(tt.expr)
+		assert.Nil(t, err, tt.expr)
+		_, err = e.Table([]string{"A", "W"}, [][]string{binary, {"a", "b", "c"}})
+		var syntaxErr *logic.SyntaxError
+		if assert.ErrorAs(t, err, &syntaxErr, tt.expr) {
+			assert.Equal(t, tt.position, syntaxErr.Position, tt.expr)
+			assert.Equal(t, tt.token, syntaxErr.Token, tt.expr)
+		}
+	}
+}
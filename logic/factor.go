@@ -1,5 +1,13 @@
 package logic
 
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"unicode"
+)
+
 // Factor is an interface for logic factors.
 type Factor interface {
 	// Table returns the CPT for the given number of parent variables.
@@ -10,58 +18,103 @@ type Factor interface {
 
 // Get logic factors by their name.
 //
-// Primarily used for deserialization.
+// Returns a new instance on each call, so that arguments set via [Factor.SetArgs]
+// are not shared between callers. Primarily used for deserialization.
 func Get(name string) (Factor, bool) {
-	f, ok := factors[name]
-	return f, ok
+	registryMutex.RLock()
+	defer registryMutex.RUnlock()
+
+	constructor, ok := registry[name]
+	if !ok {
+		return nil, false
+	}
+	return constructor(), true
 }
 
-var factors = map[string]Factor{
-	"not": Not(),
+// Register a logic factor under the given name, e.g. to make it available in YAML files.
+//
+// The constructor is called on each call to [Get], and should return a new instance.
+// Names must be lower case, without whitespace.
+// Returns an error if the name is invalid or already registered.
+func Register(name string, constructor func() Factor) error {
+	if name == "" || name != strings.ToLower(name) || strings.ContainsFunc(name, unicode.IsSpace) {
+		return fmt.Errorf("invalid logic operator name '%s'; names must be lower case, without whitespace", name)
+	}
+	if constructor == nil {
+		return fmt.Errorf("no constructor given for logic operator '%s'", name)
+	}
+
+	registryMutex.Lock()
+	defer registryMutex.Unlock()
+
+	if _, ok := registry[name]; ok {
+		return fmt.Errorf("logic operator '%s' is already registered", name)
+	}
+	registry[name] = constructor
+	return nil
+}
+
+// Names returns the names of all registered logic factors, in alphabetical order.
+func Names() []string {
+	registryMutex.RLock()
+	defer registryMutex.RUnlock()
+
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+var registryMutex sync.RWMutex
+
+var registry = map[string]func() Factor{
+	"not": Not,
 
-	"and":         And(),
-	"not-and":     NotAnd(),
-	"and-not":     AndNot(),
-	"not-and-not": NotAndNot(),
+	"and":         And,
+	"not-and":     NotAnd,
+	"and-not":     AndNot,
+	"not-and-not": NotAndNot,
 
-	"or":         Or(),
-	"not-or":     NotOr(),
-	"or-not":     OrNot(),
-	"not-or-not": NotOrNot(),
+	"or":         Or,
+	"not-or":     NotOr,
+	"or-not":     OrNot,
+	"not-or-not": NotOrNot,
 
-	"xor": XOr(),
+	"xor": XOr,
 
-	"cond":         Cond(),
-	"not-cond":     NotCond(),
-	"cond-not":     CondNot(),
-	"not-cond-not": NotCondNot(),
+	"cond":         Cond,
+	"not-cond":     NotCond,
+	"cond-not":     CondNot,
+	"not-cond-not": NotCondNot,
 
-	"bicond": BiCond(),
+	"bicond": BiCond,
 
-	"if-then":         IfThen(),
-	"if-not-then":     IfNotThen(),
-	"if-then-not":     IfThenNot(),
-	"if-not-then-not": IfNotThenNot(),
+	"if-then":         IfThen,
+	"if-not-then":     IfNotThen,
+	"if-then-not":     IfThenNot,
+	"if-not-then-not": IfNotThenNot,
 
-	"equals":     Equals(),
-	"equals-not": EqualsNot(),
+	"equals":     Equals,
+	"equals-not": EqualsNot,
 
-	"count-true":  CountTrue(),
-	"count-false": CountFalse(),
+	"count-true":  CountTrue,
+	"count-false": CountFalse,
 
-	"count-is":      CountIs(0),
-	"count-less":    CountLess(0),
-	"count-greater": CountGreater(0),
+	"count-is":      func() Factor { return CountIs(0) },
+	"count-less":    func() Factor { return CountLess(0) },
+	"count-greater": func() Factor { return CountGreater(0) },
 
-	"given":      Given(0),
-	"given-not":  GivenNot(0),
-	"given-excl": GivenExcl(0),
+	"given":      func() Factor { return Given(0) },
+	"given-not":  func() Factor { return GivenNot(0) },
+	"given-excl": func() Factor { return GivenExcl(0) },
 
-	"outcome-is":      OutcomeIs(0, 0),
-	"outcome-is-not":  OutcomeIsNot(0, 0),
-	"outcome-either":  OutcomeEither(nil, 0),
-	"outcome-less":    OutcomeLess(0, 0),
-	"outcome-greater": OutcomeGreater(0, 0),
+	"outcome-is":      func() Factor { return OutcomeIs(0, 0) },
+	"outcome-is-not":  func() Factor { return OutcomeIsNot(0, 0) },
+	"outcome-either":  func() Factor { return OutcomeEither(nil, 0) },
+	"outcome-less":    func() Factor { return OutcomeLess(0, 0) },
+	"outcome-greater": func() Factor { return OutcomeGreater(0, 0) },
 
-	"bits": Bits(),
+	"bits": Bits,
 }
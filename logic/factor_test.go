@@ -20,6 +20,60 @@ func TestFactorGet(t *testing.T) {
 	assert.False(t, ok)
 }
 
+func TestFactorGetFreshInstances(t *testing.T) {
+	f1, ok := logic.Get("count-is")
+	assert.True(t, ok)
+	f2, ok := logic.Get("count-is")
+	assert.True(t, ok)
+
+	assert.Nil(t, f1.SetArgs(0))
+	assert.Nil(t, f2.SetArgs(2))
+
+	t1, err := f1.Table(2)
+	assert.Nil(t, err)
+	t2, err := f2.Table(2)
+	assert.Nil(t, err)
+	assert.Equal(t, []float64{0, 1, 0, 1, 0, 1, 1, 0}, t1)
+	assert.Equal(t, []float64{1, 0, 0, 1, 0, 1, 0, 1}, t2)
+}
+
+type majorityFactor struct{}
+
+func (f *majorityFactor) SetArgs(args ...int) error {
+	if len(args) > 0 {
+		return fmt.Errorf("logic operator expects zero arguments, got %d", len(args))
+	}
+	return nil
+}
+
+func (f *majorityFactor) Table(given int) ([]float64, error) {
+	count := logic.CountGreater(given / 2)
+	return count.Table(given)
+}
+
+func TestFactorRegister(t *testing.T) {
+	err := logic.Register("test-majority", func() logic.Factor { return &majorityFactor{} })
+	assert.Nil(t, err)
+
+	f, ok := logic.Get("test-majority")
+	assert.True(t, ok)
+	table, err := f.Table(3)
+	assert.Nil(t, err)
+	assert.Equal(t, []float64{1, 0, 1, 0, 1, 0, 0, 1, 1, 0, 0, 1, 0, 1, 0, 1}, table)
+	assert.Contains(t, logic.Names(), "test-majority")
+
+	err = logic.Register("test-majority", func() logic.Factor { return &majorityFactor{} })
+	assert.NotNil(t, err)
+	err = logic.Register("and", logic.And)
+	assert.NotNil(t, err)
+	err = logic.Register("Majority", func() logic.Factor { return &majorityFactor{} })
+	assert.NotNil(t, err)
+	err = logic.Register("k of n", func() logic.Factor { return &majorityFactor{} })
+	assert.NotNil(t, err)
+	err = logic.Register("test-nil", nil)
+	assert.NotNil(t, err)
+}
+
 func Example() {
 	and := logic.And()
 
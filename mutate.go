@@ -0,0 +1,595 @@
+package bbn
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/mlange-42/bbn/ve"
+)
+
+// ErrCycle is returned when a modification of a network would result in a cycle.
+var ErrCycle = errors.New("network contains a cycle")
+
+// AddVariable adds a variable to the network, together with its factor.
+//
+// The factor's table may be nil. In this case, a uniform table is created for chance nodes,
+// and a table of zeros for utility nodes.
+// Policies from previous calls to [Network.SolvePolicies] are reset.
+func (n *Network) AddVariable(variable Variable, factor Factor) error {
+	return n.mutate(func(m *mutation) error {
+		if m.index(variable.Name) >= 0 {
+			return newVariableError(variable.Name, ve.ErrDuplicateVariable, "duplicate variable name %s", variable.Name)
+		}
+		factor.For = variable.Name
+		factor.Given = slices.Clone(factor.Given)
+		factor.Table = slices.Clone(factor.Table)
+		factor.Allowed = slices.Clone(factor.Allowed)
+		variable.Outcomes = slices.Clone(variable.Outcomes)
+		variable.Factor = nil
+		m.variables = append(m.variables, variable)
+		m.factors = append(m.factors, factor)
+
+		f := &m.factors[len(m.factors)-1]
+		outcomes, err := m.outcomeCounts(f.Given)
+		if err != nil {
+			return err
+		}
+		rows := product(outcomes)
+		if variable.NodeType == ve.DecisionNode {
+			f.Table = nil
+			return nil
+		}
+		cols := m.columns(&variable, f.Given)
+		if f.Table == nil {
+			f.Table = make([]float64, 0, rows*cols)
+			for i := 0; i < rows; i++ {
+				f.Table = append(f.Table, m.defaultRow(&variable, f.Given)...)
+			}
+			return nil
+		}
+		if len(f.Table) != rows*cols {
+			return newVariableError(variable.Name, ErrTableShape, "wrong table size for %s; expected %d values, got %d",
+				variable.Name, rows*cols, len(f.Table))
+		}
+		return nil
+	})
+}
+
+// RemoveVariable removes a variable from the network.
+//
+// Tables of child variables are reduced by averaging over the outcomes of the removed variable.
+// Canonical models (see [Factor.Noisy]) of child variables are replaced by their generated tables.
+// Policies from previous calls to [Network.SolvePolicies] are reset.
+func (n *Network) RemoveVariable(name string) error {
+	return n.mutate(func(m *mutation) error {
+		idx := m.index(name)
+		if idx < 0 {
+			return newVariableError(name, ErrUnknownVariable, "variable %s not found", name)
+		}
+		for i := range m.factors {
+			if slices.Contains(m.factors[i].Given, name) {
+				if err := m.removeEdge(name, m.factors[i].For); err != nil {
+					return err
+				}
+			}
+		}
+		m.variables = slices.Delete(m.variables, idx, idx+1)
+		m.factors = slices.DeleteFunc(m.factors, func(f Factor) bool { return f.For == name })
+		return nil
+	})
+}
+
+// AddEdge adds an edge from a parent to a child variable.
+//
+// The child's table is extended by repeating each row for all outcomes of the new parent.
+// A canonical model (see [Factor.Noisy]) of the child is replaced by its generated table.
+// Returns an error wrapping [ErrCycle] if the edge would result in a cycle.
+// Policies from previous calls to [Network.SolvePolicies] are reset.
+func (n *Network) AddEdge(from, to string) error {
+	return n.mutate(func(m *mutation) error {
+		return m.addEdge(from, to)
+	})
+}
+
+// RemoveEdge removes an edge between a parent and a child variable.
+//
+// The child's table is reduced by averaging over the outcomes of the removed parent.
+// A canonical model (see [Factor.Noisy]) of the child is replaced by its generated table.
+// Policies from previous calls to [Network.SolvePolicies] are reset.
+func (n *Network) RemoveEdge(from, to string) error {
+	return n.mutate(func(m *mutation) error {
+		return m.removeEdge(from, to)
+	})
+}
+
+// AddOutcome adds an outcome to a chance or decision variable.
+//
+// The variable's own table gets a column of zeros.
+// Tables of child variables get uniform rows (chance nodes) or zero rows (utility nodes) for the new outcome.
+// Canonical models (see [Factor.Noisy]) of the variable and its children are replaced by their generated tables.
+// Policies from previous calls to [Network.SolvePolicies] are reset.
+func (n *Network) AddOutcome(variable, outcome string) error {
+	return n.mutate(func(m *mutation) error {
+		v, err := m.outcomeVariable(variable)
+		if err != nil {
+			return err
+		}
+		if slices.Contains(v.Outcomes, outcome) {
+			return newVariableError(variable, ErrUnknownOutcome, "duplicate outcome %s for variable %s", outcome, variable)
+		}
+		oldCount := len(v.Outcomes)
+		v.Outcomes = append(v.Outcomes, outcome)
+
+		if f := m.factor(variable); f != nil && len(f.Table) > 0 {
+			f.Table = insertColumn(f.Table, oldCount, oldCount, 0)
+			f.Noisy = nil
+		}
+		if f := m.factor(variable); f != nil {
+			f.Allowed = updateAllowed(f.Allowed, func(t []float64) []float64 { return insertColumn(t, oldCount, oldCount, 1) })
+		}
+		return m.updateChildren(variable, oldCount, func(f *Factor, child *Variable, pos int, outcomes []int) {
+			f.Table = addParentOutcome(f.Table, m.columns(child, f.Given), outcomes, pos, m.defaultRow(child, f.Given))
+		})
+	})
+}
+
+// RemoveOutcome removes an outcome from a chance or decision variable.
+//
+// The outcome's column is removed from the variable's own table,
+// and the respective rows are removed from the tables of child variables.
+// Canonical models (see [Factor.Noisy]) of the variable and its children are replaced by their generated tables.
+// Policies from previous calls to [Network.SolvePolicies] are reset.
+func (n *Network) RemoveOutcome(variable, outcome string) error {
+	return n.mutate(func(m *mutation) error {
+		v, err := m.outcomeVariable(variable)
+		if err != nil {
+			return err
+		}
+		idx := slices.Index(v.Outcomes, outcome)
+		if idx < 0 {
+			return newVariableError(variable, ErrUnknownOutcome, "outcome %s for variable %s not found", outcome, variable)
+		}
+		if len(v.Outcomes) == 1 {
+			return newVariableError(variable, ErrUnknownOutcome, "can't remove the last outcome of variable %s", variable)
+		}
+		oldCount := len(v.Outcomes)
+		v.Outcomes = slices.Delete(v.Outcomes, idx, idx+1)
+
+		if f := m.factor(variable); f != nil && len(f.Table) > 0 {
+			f.Table = deleteColumn(f.Table, oldCount, idx)
+			f.Noisy = nil
+		}
+		if f := m.factor(variable); f != nil {
+			f.Allowed = updateAllowed(f.Allowed, func(t []float64) []float64 { return deleteColumn(t, oldCount, idx) })
+		}
+		return m.updateChildren(variable, oldCount, func(f *Factor, child *Variable, pos int, outcomes []int) {
+			f.Table = deleteParentOutcome(f.Table, m.columns(child, f.Given), outcomes, pos, idx)
+		})
+	})
+}
+
+// RenameVariable renames a variable.
+//
+// Policies from previous calls to [Network.SolvePolicies] are reset.
+func (n *Network) RenameVariable(oldName, newName string) error {
+	return n.mutate(func(m *mutation) error {
+		idx := m.index(oldName)
+		if idx < 0 {
+			return newVariableError(oldName, ErrUnknownVariable, "variable %s not found", oldName)
+		}
+		if oldName != newName && m.index(newName) >= 0 {
+			return newVariableError(newName, ve.ErrDuplicateVariable, "duplicate variable name %s", newName)
+		}
+		m.variables[idx].Name = newName
+		for i := range m.factors {
+			f := &m.factors[i]
+			if f.For == oldName {
+				f.For = newName
+			}
+			for j, g := range f.Given {
+				if g == oldName {
+					f.Given[j] = newName
+				}
+			}
+		}
+		return nil
+	})
+}
+
+// checkStructure prepares the network's variables, and checks for cycles.
+func (n *Network) checkStructure() error {
+	if err := n.prepareVariables(); err != nil {
+		return err
+	}
+	if found, problems := n.validateCycles(nil); found {
+		return newVariableError(problems[0].Variable, ErrCycle, "%s", problems[0].Message)
+	}
+	return nil
+}
+
+// mutate applies a modification to copies of the network's variables and factors.
+// The copies replace the originals if the modification succeeds and the resulting structure is valid.
+func (n *Network) mutate(fn func(m *mutation) error) error {
+	m := mutation{
+		variables: make([]Variable, len(n.variables)),
+		factors:   make([]Factor, len(n.factors)),
+	}
+	for i, v := range n.variables {
+		v.Outcomes = slices.Clone(v.Outcomes)
+		v.Factor = nil
+		m.variables[i] = v
+	}
+	for i, f := range n.factors {
+		m.factors[i] = Factor{
+			For:     f.For,
+			Given:   slices.Clone(f.Given),
+			Table:   slices.Clone(f.Table),
+			Noisy:   f.Noisy,
+			Utility: f.Utility,
+			Allowed: slices.Clone(f.Allowed),
+		}
+	}
+
+	if err := fn(&m); err != nil {
+		return err
+	}
+
+	net := Network{
+		name:      n.name,
+		info:      n.info,
+		variables: m.variables,
+		factors:   m.factors,
+		policies:  map[string]ve.Factor{},
+		limid:     n.limid,
+	}
+	if err := net.checkStructure(); err != nil {
+		return err
+	}
+	*n = net
+	return nil
+}
+
+// mutation holds copies of a network's variables and factors during a modification.
+type mutation struct {
+	variables []Variable
+	factors   []Factor
+}
+
+// index returns the index of the variable with the given name, or -1 if not found.
+func (m *mutation) index(name string) int {
+	return slices.IndexFunc(m.variables, func(v Variable) bool { return v.Name == name })
+}
+
+// factor returns the factor for the given variable, or nil if not found.
+func (m *mutation) factor(name string) *Factor {
+	idx := slices.IndexFunc(m.factors, func(f Factor) bool { return f.For == name })
+	if idx < 0 {
+		return nil
+	}
+	return &m.factors[idx]
+}
+
+// factorOrNew returns the factor for the given variable, and creates it if not present.
+func (m *mutation) factorOrNew(v *Variable) *Factor {
+	if f := m.factor(v.Name); f != nil {
+		return f
+	}
+	f := Factor{For: v.Name}
+	if v.NodeType != ve.DecisionNode {
+		f.Table = m.defaultRow(v, nil)
+	}
+	m.factors = append(m.factors, f)
+	return &m.factors[len(m.factors)-1]
+}
+
+// outcomeCounts returns the number of outcomes for each of the given variables.
+func (m *mutation) outcomeCounts(names []string) ([]int, error) {
+	counts := make([]int, len(names))
+	for i, name := range names {
+		idx := m.index(name)
+		if idx < 0 {
+			return nil, newVariableError(name, ErrUnknownVariable, "variable %s not found", name)
+		}
+		counts[i] = m.rows(&m.variables[idx])
+	}
+	return counts, nil
+}
+
+// rows returns the number of table rows a variable contributes to its children's tables.
+// 1 for continuous variables, the number of outcomes otherwise.
+func (m *mutation) rows(v *Variable) int {
+	if v.NodeType == ve.ContinuousNode {
+		return 1
+	}
+	return len(v.Outcomes)
+}
+
+// columns returns the number of table columns of a variable with the given parents.
+func (m *mutation) columns(v *Variable, given []string) int {
+	if v.NodeType != ve.ContinuousNode {
+		return len(v.Outcomes)
+	}
+	return 2 + m.continuousCount(given)
+}
+
+// continuousCount returns the number of continuous variables among the given variables.
+func (m *mutation) continuousCount(names []string) int {
+	cnt := 0
+	for _, name := range names {
+		if idx := m.index(name); idx >= 0 && m.variables[idx].NodeType == ve.ContinuousNode {
+			cnt++
+		}
+	}
+	return cnt
+}
+
+// outcomeVariable returns the variable with the given name,
+// if it is a chance or decision variable.
+func (m *mutation) outcomeVariable(name string) (*Variable, error) {
+	idx := m.index(name)
+	if idx < 0 {
+		return nil, newVariableError(name, ErrUnknownVariable, "variable %s not found", name)
+	}
+	v := &m.variables[idx]
+	if v.NodeType == ve.UtilityNode || v.NodeType == ve.ContinuousNode {
+		return nil, fmt.Errorf("can't change outcomes of utility or continuous variable %s", name)
+	}
+	return v, nil
+}
+
+// defaultRow creates a table row for the given variable with the given parents.
+// Uniform for chance nodes, zero coefficients and unit variance for continuous nodes,
+// ones for decision nodes (i.e. all outcomes allowed), zeros otherwise.
+func (m *mutation) defaultRow(v *Variable, given []string) []float64 {
+	row := make([]float64, m.columns(v, given))
+	if v.NodeType == ve.ContinuousNode {
+		row[len(row)-1] = 1
+	}
+	if v.NodeType == ve.ChanceNode {
+		for i := range row {
+			row[i] = 1.0 / float64(len(row))
+		}
+	}
+	if v.NodeType == ve.DecisionNode {
+		for i := range row {
+			row[i] = 1
+		}
+	}
+	return row
+}
+
+// isTotalUtility checks whether the given variable is a total utility node,
+// or would become one by adding a utility parent.
+func (m *mutation) isTotalUtility(v *Variable, parent *Variable) bool {
+	return v.NodeType == ve.UtilityNode && parent.NodeType == ve.UtilityNode
+}
+
+// addEdge adds an edge, extending the child's table.
+func (m *mutation) addEdge(from, to string) error {
+	fromIdx, toIdx := m.index(from), m.index(to)
+	if fromIdx < 0 {
+		return newVariableError(from, ErrUnknownVariable, "variable %s not found", from)
+	}
+	if toIdx < 0 {
+		return newVariableError(to, ErrUnknownVariable, "variable %s not found", to)
+	}
+	parent, child := &m.variables[fromIdx], &m.variables[toIdx]
+
+	f := m.factorOrNew(child)
+	if slices.Contains(f.Given, from) {
+		return fmt.Errorf("edge from %s to %s already exists", from, to)
+	}
+	outcomes, err := m.outcomeCounts(f.Given)
+	if err != nil {
+		return err
+	}
+	cols := m.columns(child, f.Given)
+	f.Given = append(f.Given, from)
+	f.Noisy = nil
+
+	if m.isTotalUtility(child, parent) {
+		// total utility: one weight per utility parent
+		if len(outcomes) == 0 {
+			child.Outcomes = nil
+			f.Table = nil
+		}
+		child.Outcomes = append(child.Outcomes, from)
+		f.Table = append(f.Table, 1)
+		return nil
+	}
+	if child.NodeType == ve.DecisionNode {
+		f.Allowed = updateAllowed(f.Allowed, func(t []float64) []float64 { return addParent(t, cols, m.rows(parent)) })
+		return nil
+	}
+	if child.NodeType == ve.ContinuousNode && parent.NodeType == ve.ContinuousNode {
+		// continuous parent: coefficient column before variance
+		f.Table = insertColumn(f.Table, cols, cols-1, 0)
+		return nil
+	}
+	f.Table = addParent(f.Table, cols, m.rows(parent))
+	return nil
+}
+
+// removeEdge removes an edge, reducing the child's table.
+func (m *mutation) removeEdge(from, to string) error {
+	toIdx := m.index(to)
+	if toIdx < 0 {
+		return newVariableError(to, ErrUnknownVariable, "variable %s not found", to)
+	}
+	child := &m.variables[toIdx]
+	f := m.factor(to)
+	pos := -1
+	if f != nil {
+		pos = slices.Index(f.Given, from)
+	}
+	if pos < 0 {
+		return fmt.Errorf("edge from %s to %s not found", from, to)
+	}
+	outcomes, err := m.outcomeCounts(f.Given)
+	if err != nil {
+		return err
+	}
+	cols := m.columns(child, f.Given)
+	continuousBefore := m.continuousCount(f.Given[:pos])
+	f.Given = slices.Delete(f.Given, pos, pos+1)
+	f.Noisy = nil
+
+	fromIdx := m.index(from)
+	if fromIdx >= 0 && m.isTotalUtility(child, &m.variables[fromIdx]) {
+		child.Outcomes = slices.Delete(child.Outcomes, pos, pos+1)
+		f.Table = slices.Delete(f.Table, pos, pos+1)
+		return nil
+	}
+	if child.NodeType == ve.DecisionNode {
+		// allowed if allowed for any outcome of the removed parent
+		f.Allowed = updateAllowed(f.Allowed, func(t []float64) []float64 { return removeParent(t, cols, outcomes, pos) })
+		return nil
+	}
+	if len(f.Table) == 0 {
+		return nil
+	}
+	if child.NodeType == ve.ContinuousNode && fromIdx >= 0 && m.variables[fromIdx].NodeType == ve.ContinuousNode {
+		// continuous parent: remove coefficient column
+		f.Table = deleteColumn(f.Table, cols, 1+continuousBefore)
+		return nil
+	}
+	f.Table = removeParent(f.Table, cols, outcomes, pos)
+	return nil
+}
+
+// updateChildren calls fn for each factor that has the given variable as parent.
+// Argument pos is the position of the variable in the factor's parents,
+// and outcomes are the outcome counts of the parents before the modification.
+func (m *mutation) updateChildren(name string, oldCount int, fn func(f *Factor, child *Variable, pos int, outcomes []int)) error {
+	for i := range m.factors {
+		f := &m.factors[i]
+		pos := slices.Index(f.Given, name)
+		if pos < 0 {
+			continue
+		}
+		childIdx := m.index(f.For)
+		if childIdx < 0 {
+			return newVariableError(f.For, ErrUnknownVariable, "variable %s not found", f.For)
+		}
+		child := &m.variables[childIdx]
+		if (child.NodeType == ve.DecisionNode && f.Allowed == nil) || (child.NodeType != ve.DecisionNode && len(f.Table) == 0) {
+			continue
+		}
+		outcomes, err := m.outcomeCounts(f.Given)
+		if err != nil {
+			return err
+		}
+		// restore the parent's old outcome count, as it was already modified
+		outcomes[pos] = oldCount
+		if child.NodeType == ve.DecisionNode {
+			f.Allowed = updateAllowed(f.Allowed, func(t []float64) []float64 {
+				allowed := Factor{Given: f.Given, Table: t}
+				fn(&allowed, child, pos, outcomes)
+				return allowed.Table
+			})
+			continue
+		}
+		fn(f, child, pos, outcomes)
+		f.Noisy = nil
+	}
+	return nil
+}
+
+// product of all values. 1 for an empty slice.
+func product(values []int) int {
+	p := 1
+	for _, v := range values {
+		p *= v
+	}
+	return p
+}
+
+// addParent extends a table by a new last parent, repeating each row for all its outcomes.
+func addParent(table []float64, cols int, parentOutcomes int) []float64 {
+	rows := len(table) / cols
+	result := make([]float64, 0, len(table)*parentOutcomes)
+	for r := 0; r < rows; r++ {
+		for k := 0; k < parentOutcomes; k++ {
+			result = append(result, table[r*cols:(r+1)*cols]...)
+		}
+	}
+	return result
+}
+
+// removeParent reduces a table by averaging over the outcomes of the parent at the given position.
+func removeParent(table []float64, cols int, outcomes []int, pos int) []float64 {
+	outer, inner := product(outcomes[:pos]), product(outcomes[pos+1:])
+	count := outcomes[pos]
+	result := make([]float64, outer*inner*cols)
+	for a := 0; a < outer; a++ {
+		for b := 0; b < inner; b++ {
+			newRow := a*inner + b
+			for k := 0; k < count; k++ {
+				oldRow := (a*count+k)*inner + b
+				for c := 0; c < cols; c++ {
+					result[newRow*cols+c] += table[oldRow*cols+c] / float64(count)
+				}
+			}
+		}
+	}
+	return result
+}
+
+// addParentOutcome extends a table for an additional outcome of the parent at the given position,
+// using the given row for the new outcome.
+// Argument outcomes contains the outcome counts of the parents before the modification.
+func addParentOutcome(table []float64, cols int, outcomes []int, pos int, row []float64) []float64 {
+	outer, inner := product(outcomes[:pos]), product(outcomes[pos+1:])
+	count := outcomes[pos]
+	result := make([]float64, 0, outer*(count+1)*inner*cols)
+	for a := 0; a < outer; a++ {
+		start := a * count * inner * cols
+		result = append(result, table[start:start+count*inner*cols]...)
+		for b := 0; b < inner; b++ {
+			result = append(result, row...)
+		}
+	}
+	return result
+}
+
+// deleteParentOutcome reduces a table by removing the rows for an outcome of the parent at the given position.
+// Argument outcomes contains the outcome counts of the parents before the modification.
+func deleteParentOutcome(table []float64, cols int, outcomes []int, pos int, outcome int) []float64 {
+	outer, inner := product(outcomes[:pos]), product(outcomes[pos+1:])
+	count := outcomes[pos]
+	result := make([]float64, 0, outer*(count-1)*inner*cols)
+	for a := 0; a < outer; a++ {
+		for k := 0; k < count; k++ {
+			if k == outcome {
+				continue
+			}
+			start := (a*count + k) * inner * cols
+			result = append(result, table[start:start+inner*cols]...)
+		}
+	}
+	return result
+}
+
+// insertColumn inserts a column with the given value into a table, at the given column index.
+func insertColumn(table []float64, cols int, col int, value float64) []float64 {
+	rows := len(table) / cols
+	result := make([]float64, 0, rows*(cols+1))
+	for r := 0; r < rows; r++ {
+		result = append(result, table[r*cols:r*cols+col]...)
+		result = append(result, value)
+		result = append(result, table[r*cols+col:(r+1)*cols]...)
+	}
+	return result
+}
+
+// deleteColumn removes a column from a table.
+func deleteColumn(table []float64, cols int, col int) []float64 {
+	rows := len(table) / cols
+	result := make([]float64, 0, rows*(cols-1))
+	for r := 0; r < rows; r++ {
+		result = append(result, table[r*cols:r*cols+col]...)
+		result = append(result, table[r*cols+col+1:(r+1)*cols]...)
+	}
+	return result
+}
@@ -0,0 +1,156 @@
+package bbn
+
+import (
+	"testing"
+
+	"github.com/mlange-42/bbn/ve"
+	"github.com/stretchr/testify/assert"
+)
+
+func newMutateTestNetwork(t *testing.T) *Network {
+	net, err := NewBuilder("Test", "").
+		AddChance("A", "a1", "a2").
+		AddChance("B", "b1", "b2", "b3").
+		AddChance("C", "c1", "c2").
+		AddEdge("A", "C").
+		SetTable("A", []float64{0.2, 0.8}).
+		SetTable("B", []float64{0.2, 0.3, 0.5}).
+		SetTable("C", []float64{0.1, 0.9, 0.6, 0.4}).
+		Build()
+	assert.Nil(t, err)
+	return net
+}
+
+func TestNetworkAddRemoveEdge(t *testing.T) {
+	net := newMutateTestNetwork(t)
+
+	assert.Nil(t, net.AddEdge("B", "C"))
+	c, _ := net.variable("C")
+	assert.Equal(t, []string{"A", "B"}, c.Factor.Given)
+	assert.Equal(t, []float64{
+		0.1, 0.9, 0.1, 0.9, 0.1, 0.9,
+		0.6, 0.4, 0.6, 0.4, 0.6, 0.4,
+	}, c.Factor.Table)
+	assert.Equal(t, []int{2, 3}, c.Factor.outcomes)
+
+	assert.NotNil(t, net.AddEdge("B", "C"))
+	assert.ErrorIs(t, net.AddEdge("C", "A"), ErrCycle)
+	assert.ErrorIs(t, net.AddEdge("X", "A"), ErrUnknownVariable)
+
+	assert.Nil(t, net.RemoveEdge("A", "C"))
+	c, _ = net.variable("C")
+	assert.Equal(t, []string{"B"}, c.Factor.Given)
+	assert.InDeltaSlice(t, []float64{0.35, 0.65, 0.35, 0.65, 0.35, 0.65}, c.Factor.Table, 1e-9)
+
+	assert.NotNil(t, net.RemoveEdge("A", "C"))
+	assert.Empty(t, net.Validate())
+}
+
+func TestNetworkAddRemoveOutcome(t *testing.T) {
+	net := newMutateTestNetwork(t)
+
+	assert.Nil(t, net.AddOutcome("A", "a3"))
+	a, _ := net.variable("A")
+	c, _ := net.variable("C")
+	assert.Equal(t, []string{"a1", "a2", "a3"}, a.Outcomes)
+	assert.Equal(t, []float64{0.2, 0.8, 0}, a.Factor.Table)
+	assert.Equal(t, []float64{0.1, 0.9, 0.6, 0.4, 0.5, 0.5}, c.Factor.Table)
+
+	assert.Nil(t, net.RemoveOutcome("A", "a1"))
+	a, _ = net.variable("A")
+	c, _ = net.variable("C")
+	assert.Equal(t, []string{"a2", "a3"}, a.Outcomes)
+	assert.Equal(t, []float64{0.8, 0}, a.Factor.Table)
+	assert.Equal(t, []float64{0.6, 0.4, 0.5, 0.5}, c.Factor.Table)
+
+	assert.ErrorIs(t, net.AddOutcome("A", "a2"), ErrUnknownOutcome)
+	assert.ErrorIs(t, net.RemoveOutcome("A", "a1"), ErrUnknownOutcome)
+	assert.ErrorIs(t, net.AddOutcome("X", "x"), ErrUnknownVariable)
+}
+
+func TestNetworkAddRemoveVariable(t *testing.T) {
+	net := newMutateTestNetwork(t)
+
+	err := net.AddVariable(
+		Variable{Name: "D", NodeType: ve.ChanceNode, Outcomes: []string{"d1", "d2"}},
+		Factor{Given: []string{"A", "C"}},
+	)
+	assert.Nil(t, err)
+	d, _ := net.variable("D")
+	assert.Equal(t, []float64{0.5, 0.5, 0.5, 0.5, 0.5, 0.5, 0.5, 0.5}, d.Factor.Table)
+
+	err = net.AddVariable(Variable{Name: "D", NodeType: ve.ChanceNode, Outcomes: []string{"d1"}}, Factor{})
+	assert.ErrorIs(t, err, ve.ErrDuplicateVariable)
+
+	err = net.AddVariable(Variable{Name: "E", NodeType: ve.ChanceNode, Outcomes: []string{"e1"}}, Factor{Table: []float64{1, 2}})
+	assert.ErrorIs(t, err, ErrTableShape)
+	_, ok := net.variable("E")
+	assert.False(t, ok)
+
+	assert.Nil(t, net.RemoveVariable("C"))
+	d, _ = net.variable("D")
+	assert.Equal(t, []string{"A"}, d.Factor.Given)
+	assert.Equal(t, []float64{0.5, 0.5, 0.5, 0.5}, d.Factor.Table)
+	assert.Equal(t, 3, len(net.Variables()))
+
+	assert.ErrorIs(t, net.RemoveVariable("C"), ErrUnknownVariable)
+}
+
+func TestNetworkRenameVariable(t *testing.T) {
+	net := newMutateTestNetwork(t)
+
+	assert.Nil(t, net.RenameVariable("A", "X"))
+	c, _ := net.variable("C")
+	assert.Equal(t, []string{"X"}, c.Factor.Given)
+	x, ok := net.variable("X")
+	assert.True(t, ok)
+	assert.Equal(t, "X", x.Factor.For)
+
+	assert.ErrorIs(t, net.RenameVariable("X", "B"), ve.ErrDuplicateVariable)
+	assert.ErrorIs(t, net.RenameVariable("A", "Y"), ErrUnknownVariable)
+
+	result, _, err := net.SolveQuery(map[string]string{"X": "a1"}, []string{"C"}, false)
+	assert.Nil(t, err)
+	assert.InDeltaSlice(t, []float64{0.1, 0.9}, result["C"], 1e-9)
+}
+
+func TestNetworkMutateUtility(t *testing.T) {
+	net, err := NewBuilder("Test", "").
+		AddChance("A", "a1", "a2").
+		AddDecision("D", "d1", "d2").
+		AddUtility("U1").
+		AddUtility("U2").
+		AddEdge("A", "U1").
+		AddEdge("D", "U2").
+		SetTable("A", []float64{0.5, 0.5}).
+		SetTable("U1", []float64{1, 2}).
+		SetTable("U2", []float64{3, 4}).
+		Build()
+	assert.Nil(t, err)
+	assert.Equal(t, -1, net.TotalUtilityIndex())
+
+	err = net.AddVariable(Variable{Name: "Total", NodeType: ve.UtilityNode}, Factor{Given: []string{"U1"}, Table: []float64{1}})
+	assert.NotNil(t, err)
+
+	err = net.AddVariable(
+		Variable{Name: "Total", NodeType: ve.UtilityNode, Outcomes: []string{"U1"}},
+		Factor{Given: []string{"U1"}, Table: []float64{1}},
+	)
+	assert.Nil(t, err)
+	assert.Equal(t, 4, net.TotalUtilityIndex())
+
+	assert.Nil(t, net.AddEdge("U2", "Total"))
+	total, _ := net.variable("Total")
+	assert.Equal(t, []string{"U1", "U2"}, total.Outcomes)
+	assert.Equal(t, []float64{1, 1}, total.Factor.Table)
+
+	assert.Nil(t, net.AddOutcome("D", "d3"))
+	u2, _ := net.variable("U2")
+	assert.Equal(t, []float64{3, 4, 0}, u2.Factor.Table)
+	assert.NotNil(t, net.AddOutcome("U2", "x"))
+
+	assert.Nil(t, net.RemoveVariable("U1"))
+	total, _ = net.variable("Total")
+	assert.Equal(t, []string{"U2"}, total.Outcomes)
+	assert.Equal(t, 3, net.TotalUtilityIndex())
+}
@@ -3,10 +3,11 @@ package bbn
 import (
 	"fmt"
 	"os"
-	"path/filepath"
 	"slices"
-	"strings"
+	"strconv"
 
+	"github.com/mlange-42/bbn/noisy"
+	"github.com/mlange-42/bbn/utility"
 	"github.com/mlange-42/bbn/ve"
 )
 
@@ -14,19 +15,27 @@ import (
 type Variable struct {
 	Name     string      // Name of the variable.
 	NodeType ve.NodeType // Node type of the variable.
-	Outcomes []string    // Possible outcomes.
+	Outcomes []string    // Possible outcomes. Empty for continuous variables.
+	Bins     []float64   // Edges of interval outcomes for numeric values, optional. See [Variable.Bin].
 	Position [2]int      // Position in bbni visualization, in terminal cells.
 	Color    string      // Name of the node color in bbni visualization.
 	Factor   *Factor     // Don't set this, it is initialized when constructing the network.
 }
 
 // Factor definition, encoding a conditional probability or utility table.
+//
+// For continuous variables, the table encodes a linear Gaussian distribution.
+// It has one row per configuration of discrete parents, with columns intercept,
+// one coefficient per continuous parent (in the order of Given), and variance.
 type Factor struct {
-	For      string    // Primary variable of the factor.
-	Given    []string  `yaml:",omitempty"` // Names of dependency variables, i.e. parents.
-	Table    []float64 `yaml:",omitempty"` // Flat representation of the factor's table.
-	outcomes []int     // Number of outcomes of parent/given variables.
-	columns  int       // Number of table columns, i.e. of outcomes of the primary variable.
+	For      string           // Primary variable of the factor.
+	Given    []string         `yaml:",omitempty"` // Names of dependency variables, i.e. parents.
+	Table    []float64        `yaml:",omitempty"` // Flat representation of the factor's table.
+	Noisy    noisy.Model      `yaml:"-"`          // Canonical model to generate the table from, optional. Chance variables only.
+	Utility  utility.Function `yaml:"-"`          // Function to combine utilities, optional. Total utility variable only, with the table as weights.
+	Allowed  []bool           `yaml:"-"`          // Allowed outcomes, in the layout of the table, optional. Decision variables only.
+	outcomes []int            // Number of outcomes of parent/given variables.
+	columns  int              // Number of table columns, i.e. of outcomes of the primary variable.
 }
 
 // Row returns a table row of the factor for the
@@ -34,12 +43,33 @@ type Factor struct {
 //
 // The returned slice is referencing a range in the original table,
 // so modifications affect the owning factor.
+//
+// Returns false as second argument in case of missing data in the argument (i.e. -1).
+//
+// Panics if the number of indices does not match the number of parents.
+// See [Factor.TryRow] for an error-returning variant.
 func (f *Factor) Row(indices []int) ([]float64, bool) {
-	idx, ok := f.rowIndex(indices)
-	if !ok {
-		return nil, false
+	row, ok, err := f.TryRow(indices)
+	if err != nil {
+		panic(err)
 	}
-	return f.Table[idx : idx+f.columns], true
+	return row, ok
+}
+
+// TryRow returns a table row of the factor for the
+// given outcome indices of given/parent variables.
+//
+// The returned slice is referencing a range in the original table,
+// so modifications affect the owning factor.
+//
+// Returns false as second argument in case of missing data in the argument (i.e. -1).
+// Returns an error wrapping [ErrTableShape] if the number of indices does not match the number of parents.
+func (f *Factor) TryRow(indices []int) ([]float64, bool, error) {
+	idx, ok, err := f.rowIndex(indices)
+	if !ok || err != nil {
+		return nil, false, err
+	}
+	return f.Table[idx : idx+f.columns], true, nil
 }
 
 // rowIndex returns a row starting index for the
@@ -49,26 +79,27 @@ func (f *Factor) Row(indices []int) ([]float64, bool) {
 //
 // The last variable in the factor (i.e. f.For) is not considered,
 // and the returned index refers to the two-dimensional representation of the table.
-func (f *Factor) rowIndex(indices []int) (int, bool) {
+func (f *Factor) rowIndex(indices []int) (int, bool, error) {
 	if len(indices) != len(f.outcomes) {
-		panic(fmt.Sprintf("factor with %d given variables can't use %d indices", len(f.outcomes), len(indices)))
+		return 0, false, newVariableError(f.For, ErrTableShape,
+			"factor with %d given variables can't use %d indices", len(f.outcomes), len(indices))
 	}
 
 	if len(indices) == 0 {
-		return 0, true
+		return 0, true, nil
 	}
 
 	idx := 0
 	stride := 1
 	for i := len(indices) - 1; i >= 0; i-- {
 		if indices[i] < 0 {
-			return 0, false
+			return 0, false, nil
 		}
 		idx += indices[i] * stride
 		stride *= int(f.outcomes[i])
 	}
 
-	return idx, true
+	return idx, true, nil
 }
 
 type variable struct {
@@ -86,6 +117,7 @@ type Network struct {
 	ve                *ve.VE               // Current VE instance.
 	variableNames     map[string]*variable // Mapping from names to variables.
 	totalUtilityIndex int                  // Index of the total utility node. -1 if none.
+	limid             bool                 // Whether the network is a limited-memory influence diagram.
 }
 
 // New creates a new bbn network from the given variables and factors.
@@ -105,29 +137,15 @@ func New(name string, info string, variables []Variable, factors []Factor) (*Net
 }
 
 // FromFile reads a [Network] from an YAML or XML file.
+//
+// The format is detected from the content, with the file extension as fallback.
+// See [DetectFormat].
 func FromFile(path string) (*Network, error) {
 	data, err := os.ReadFile(path)
 	if err != nil {
 		return nil, err
 	}
-	ext := filepath.Ext(path)
-
-	switch strings.ToLower(ext) {
-	case ".yml":
-		n, err := FromYAML(data)
-		if err != nil {
-			return nil, err
-		}
-		return n, nil
-	case ".xml", ".bifxml":
-		n, err := FromBIFXML(data)
-		if err != nil {
-			return nil, err
-		}
-		return n, nil
-	default:
-		return nil, fmt.Errorf("unsupported file format '%s'", ext)
-	}
+	return parse(path, data, FormatAuto)
 }
 
 // prepareVariables, called from the constructor.
@@ -137,10 +155,17 @@ func (n *Network) prepareVariables() error {
 	for i := range n.variables {
 		v := &n.variables[i]
 		if _, ok := varNames[v.Name]; ok {
-			return fmt.Errorf("duplicate variable name %s", v.Name)
+			return newVariableError(v.Name, ve.ErrDuplicateVariable, "duplicate variable name %s", v.Name)
+		}
+		if err := checkBins(v); err != nil {
+			return err
 		}
 		varNames[v.Name] = v
 		outcomes[v.Name] = len(v.Outcomes)
+		if v.NodeType == ve.ContinuousNode {
+			// continuous parents don't contribute table rows
+			outcomes[v.Name] = 1
+		}
 	}
 
 	for i := range n.variables {
@@ -156,12 +181,21 @@ func (n *Network) prepareVariables() error {
 		for i, g := range v.Factor.Given {
 			n, ok := outcomes[g]
 			if !ok {
-				return fmt.Errorf("parent variable %s of %s not found", g, v.Name)
+				return newVariableError(g, ErrUnknownVariable, "parent variable %s of %s not found", g, v.Name)
 			}
 			v.Factor.outcomes[i] = n
 		}
+		if err := prepareNoisyNode(v); err != nil {
+			return err
+		}
+		if err := checkAllowed(v); err != nil {
+			return err
+		}
 	}
 
+	if err := n.prepareContinuousNodes(varNames); err != nil {
+		return err
+	}
 	return n.prepareUtilityNodes(varNames)
 }
 
@@ -173,12 +207,15 @@ func (n *Network) prepareUtilityNodes(varNames map[string]*Variable) error {
 		if v.NodeType != ve.UtilityNode {
 			continue
 		}
+		if v.Factor == nil {
+			return fmt.Errorf("utility node %s has no parents", v.Name)
+		}
 		hasUtilParents := false
 		hasOtherParents := false
 		for _, parent := range v.Factor.Given {
 			p, ok := varNames[parent]
 			if !ok {
-				return fmt.Errorf("parent node %s for %s not found", parent, v.Name)
+				return newVariableError(parent, ErrUnknownVariable, "parent node %s for %s not found", parent, v.Name)
 			}
 			if p.NodeType == ve.UtilityNode {
 				hasUtilParents = true
@@ -199,11 +236,14 @@ func (n *Network) prepareUtilityNodes(varNames map[string]*Variable) error {
 			return fmt.Errorf("found multiple nodes for total utility")
 		}
 		if len(v.Outcomes) != len(v.Factor.Given) {
-			return fmt.Errorf("invalid total utility node; number of parents and number of outcomes must be the same")
+			return newVariableError(v.Name, ErrTableShape, "invalid total utility node; number of parents and number of outcomes must be the same")
+		}
+		if len(v.Factor.Table) != len(v.Outcomes) {
+			return newVariableError(v.Name, ErrTableShape, "invalid total utility node; expected %d weights, got %d", len(v.Outcomes), len(v.Factor.Table))
 		}
 		n.totalUtilityIndex = i
 	}
-	return nil
+	return n.checkUtilityFunction()
 }
 
 // Name of the network.
@@ -228,18 +268,29 @@ func (n *Network) TotalUtilityIndex() int {
 
 // SolvePolicies solves and inserts policies for decisions, using variable elimination.
 //
+// For a limited-memory influence diagram (see [Network.SetLIMID]), policies are solved by [Network.SolveLIMID],
+// and argument stepwise is ignored.
+// Returns an error wrapping [ErrNoPolicy] if policies did not converge.
+// Use [Network.SolveLIMID] directly to check whether the solution is optimal.
+//
 // Returns a map of policies for each decision variable, by variable name.
 func (n *Network) SolvePolicies(stepwise bool) (map[string]Factor, error) {
+	if n.limid {
+		return n.solveLIMIDPolicies()
+	}
 	clear(n.policies)
 
 	decisions := n.countDecisionSteps(stepwise)
 	for i := 0; i < decisions; i++ {
 		var err error
-		n.ve, n.variableNames, err = n.toVE(nil)
+		n.ve, n.variableNames, err = n.toVE(nil, nil, nil)
+		if err != nil {
+			return nil, err
+		}
+		policies, err := n.ve.TrySolvePolicies(stepwise)
 		if err != nil {
 			return nil, err
 		}
-		policies := n.ve.SolvePolicies(stepwise)
 		if policies == nil {
 			break
 		}
@@ -271,7 +322,10 @@ func (n *Network) SolvePolicies(stepwise bool) (map[string]Factor, error) {
 		}
 		newVars[len(newVars)-1] = variables[idx]
 
-		f := n.ve.Variables().Rearrange(&f, newVars)
+		f, err := n.ve.Variables().TryRearrange(&f, newVars)
+		if err != nil {
+			return nil, err
+		}
 
 		given := make([]string, len(newVars)-1)
 		for i := 0; i < len(newVars)-1; i++ {
@@ -279,9 +333,10 @@ func (n *Network) SolvePolicies(stepwise bool) (map[string]Factor, error) {
 		}
 
 		ff := Factor{
-			For:   name,
-			Given: given,
-			Table: f.Data(),
+			For:     name,
+			Given:   given,
+			Table:   f.Data(),
+			Allowed: n.policyAllowed(name, given),
 		}
 		result[name] = ff
 	}
@@ -310,19 +365,29 @@ func (n *Network) countDecisionSteps(stepwise bool) int {
 // Returns a map of normalized marginal probabilities for each query variable, by variable name.
 // Further, it returns the resulting factor containing the query variables.
 func (n *Network) SolveQuery(evidence map[string]string, query []string, ignorePolicies bool) (map[string][]float64, *ve.Factor, error) {
-	f, err := n.solve(evidence, query, false, "", ignorePolicies)
+	f, err := n.solve(evidence, nil, query, false, "", ignorePolicies)
+	if err != nil {
+		return nil, nil, err
+	}
+	result, err := n.queryMarginals(f, query)
 	if err != nil {
 		return nil, nil, err
 	}
+	return result, f, nil
+}
 
+// queryMarginals calculates normalized marginal probabilities for each query variable.
+func (n *Network) queryMarginals(f *ve.Factor, query []string) (map[string][]float64, error) {
 	result := map[string][]float64{}
 	for _, q := range query {
-		m := n.Marginal(f, q)
+		m, err := n.TryMarginal(f, q)
+		if err != nil {
+			return nil, err
+		}
 		n := n.Normalize(&m)
 		result[q] = n.Data()
 	}
-
-	return result, f, nil
+	return result, nil
 }
 
 // SolveUtility solves utility, using variable elimination.
@@ -335,18 +400,22 @@ func (n *Network) SolveQuery(evidence map[string]string, query []string, ignoreP
 //
 // Returns a factor for utility, containing the query variables.
 func (n *Network) SolveUtility(evidence map[string]string, query []string, utilityVar string, ignorePolicies bool) (*ve.Factor, error) {
-	return n.solve(evidence, query, true, utilityVar, ignorePolicies)
+	return n.solve(evidence, nil, query, true, utilityVar, ignorePolicies)
 }
 
 // solve solves a query or utility, using variable elimination.
-func (n *Network) solve(evidence map[string]string, query []string, utility bool, utilityVar string, ignorePolicies bool) (*ve.Factor, error) {
+// Argument do contains interventions, which may be nil.
+func (n *Network) solve(evidence map[string]string, do map[string]string, query []string, utility bool, utilityVar string, ignorePolicies bool) (*ve.Factor, error) {
+	evidence, continuous, err := n.splitEvidence(evidence)
+	if err != nil {
+		return nil, err
+	}
 	var decisionEvidence map[string]string
 	if ignorePolicies {
 		decisionEvidence = evidence
 	}
 
-	var err error
-	n.ve, n.variableNames, err = n.toVE(decisionEvidence)
+	n.ve, n.variableNames, err = n.toVE(decisionEvidence, do, continuous)
 	if err != nil {
 		return nil, err
 	}
@@ -355,11 +424,11 @@ func (n *Network) solve(evidence map[string]string, query []string, utility bool
 	for name, value := range evidence {
 		vv, ok := n.variableNames[name]
 		if !ok {
-			return nil, fmt.Errorf("evidence variable %s not found", name)
+			return nil, newVariableError(name, ErrUnknownVariable, "evidence variable %s not found", name)
 		}
 		idx := slices.Index(vv.Variable.Outcomes, value)
 		if idx < 0 {
-			return nil, fmt.Errorf("outcome %s for evidence variable %s not found", value, name)
+			return nil, newVariableError(name, ErrUnknownOutcome, "outcome %s for evidence variable %s not found", value, name)
 		}
 		ev = append(ev, ve.Evidence{Variable: vv.VeVariable, Value: idx})
 	}
@@ -368,7 +437,10 @@ func (n *Network) solve(evidence map[string]string, query []string, utility bool
 	for i, name := range query {
 		vv, ok := n.variableNames[name]
 		if !ok {
-			return nil, fmt.Errorf("query variable %s not found", name)
+			if v, ok := n.variable(name); ok && v.NodeType == ve.ContinuousNode {
+				return nil, newVariableError(name, ErrUnsupported, "query variable %s is continuous; use SolveContinuous", name)
+			}
+			return nil, newVariableError(name, ErrUnknownVariable, "query variable %s not found", name)
 		}
 		q[i] = vv.VeVariable
 	}
@@ -378,28 +450,36 @@ func (n *Network) solve(evidence map[string]string, query []string, utility bool
 		if utilityVar != "" {
 			u, ok := n.variableNames[utilityVar]
 			if !ok {
-				return nil, fmt.Errorf("utility query variable %s not found", utilityVar)
+				return nil, newVariableError(utilityVar, ErrUnknownVariable, "utility query variable %s not found", utilityVar)
 			}
 			util = &u.VeVariable
 		}
-		return n.ve.SolveUtility(ev, q, util), nil
-	} else {
-		return n.ve.SolveQuery(ev, q), nil
+		return n.ve.TrySolveUtility(ev, q, util)
 	}
+	return n.ve.TrySolveQuery(ev, q)
 }
 
 // ToEvidence converts a string variable/value pair to marginal probabilities for the evidence variable.
 //
 // As an example, say we have a variable with outcomes [yes, no]. Given evidence "yes" (index 0):
 // we get the following probabilities: [1, 0].
+//
+// For continuous variables, it returns the parsed value and a variance of zero.
 func (n *Network) ToEvidence(variable string, value string) ([]float64, error) {
+	if n.isContinuous(variable) {
+		x, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil, newVariableError(variable, ErrUnknownOutcome, "invalid value %s for continuous evidence variable %s", value, variable)
+		}
+		return []float64{x, 0}, nil
+	}
 	vv, ok := n.variableNames[variable]
 	if !ok {
-		return nil, fmt.Errorf("evidence variable %s not found", variable)
+		return nil, newVariableError(variable, ErrUnknownVariable, "evidence variable %s not found", variable)
 	}
-	idx := slices.Index(vv.Variable.Outcomes, value)
-	if idx < 0 {
-		return nil, fmt.Errorf("outcome %s for evidence variable %s not found", value, variable)
+	idx, ok := vv.Variable.Outcome(value)
+	if !ok {
+		return nil, newVariableError(variable, ErrUnknownOutcome, "outcome %s for evidence variable %s not found", value, variable)
 	}
 	probs := make([]float64, len(vv.Variable.Outcomes))
 	probs[idx] = 1.0
@@ -407,61 +487,86 @@ func (n *Network) ToEvidence(variable string, value string) ([]float64, error) {
 }
 
 // toVE creates a Variable Elimination solver from the network.
-func (n *Network) toVE(evidence map[string]string) (*ve.VE, map[string]*variable, error) {
+//
+// Argument do contains interventions, which may be nil.
+// Factors of intervened variables are replaced by point masses, thus cutting their incoming edges.
+// Argument continuous contains evidence for continuous variables, which may be nil.
+// Continuous variables are not part of the solver, but continuous evidence is represented by a likelihood factor.
+func (n *Network) toVE(evidence map[string]string, do map[string]string, continuous map[string]float64) (*ve.VE, map[string]*variable, error) {
 	vars := ve.NewVariables()
-	varNames := map[string]*variable{}
-	varIDs := make([]variable, len(n.variables))
 	dependencies := map[ve.Variable][]ve.Variable{}
-	totalUtilityName := ""
+	varIDs, varNames := n.collectVariables(vars, do)
 
-	// collect variables for lookup
-	for i, v := range n.variables {
-		// skip total utility node
-		if i == n.totalUtilityIndex {
-			totalUtilityName = v.Name
-			continue
-		}
-		// treat decision variables with policy as normal change variables
-		if v.NodeType == ve.DecisionNode {
-			if _, ok := n.policies[v.Name]; ok {
-				varIDs[i] = variable{
-					Variable:   v,
-					VeVariable: vars.AddVariable(i, ve.ChanceNode, uint16(len(v.Outcomes))),
-				}
-				varNames[v.Name] = &varIDs[i]
-				continue
-			}
-		}
+	// create factors from tables
+	factors, err := n.tableFactors(vars, varNames, do, dependencies)
+	if err != nil {
+		return nil, nil, err
+	}
 
-		// for all other variables
-		varIDs[i] = variable{
-			Variable:   v,
-			VeVariable: vars.AddVariable(i, v.NodeType, uint16(len(v.Outcomes))),
-		}
-		varNames[v.Name] = &varIDs[i]
+	// add policies as factors
+	factors = append(factors, n.policyFactors(vars, varIDs, evidence, do)...)
+
+	// add interventions as factors
+	doFactors, err := interventionFactors(vars, varNames, do)
+	if err != nil {
+		return nil, nil, err
+	}
+	factors = append(factors, doFactors...)
+
+	// add continuous evidence as factor
+	likelihood, err := n.likelihoodFactor(vars, varNames, continuous)
+	if err != nil {
+		return nil, nil, err
+	}
+	if likelihood != nil {
+		factors = append(factors, *likelihood)
+	}
+
+	// add combined utility for a utility function
+	utilityFactor, err := n.totalUtilityFactor(vars, varNames)
+	if err != nil {
+		return nil, nil, err
+	}
+	if utilityFactor != nil {
+		factors = append(factors, *utilityFactor)
+	}
+
+	weights, err := n.prepareUtilityWeights()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	solver := ve.New(vars, factors, dependencies, weights)
+	if err := n.constrainDecisions(solver, varNames); err != nil {
+		return nil, nil, err
+	}
+	return solver, varNames, nil
+}
+
+// tableFactors creates factors from the tables of the network's variables.
+// Dependencies of unsolved decision variables are added to dependencies.
+func (n *Network) tableFactors(vars *ve.Variables, varNames map[string]*variable, do map[string]string, dependencies map[ve.Variable][]ve.Variable) ([]ve.Factor, error) {
+	totalUtilityName := ""
+	if n.totalUtilityIndex >= 0 {
+		totalUtilityName = n.variables[n.totalUtilityIndex].Name
 	}
 
-	// create factors from tables
 	factors := make([]ve.Factor, 0, len(n.factors))
+	nextID := len(n.variables)
 	for _, f := range n.factors {
-		// skip factor for total utility
-		if f.For == totalUtilityName {
+		if n.skipFactor(&f, varNames, do, totalUtilityName) {
 			continue
 		}
 		// get primary variable
 		forVar, ok := varNames[f.For]
 		if !ok {
-			return nil, nil, fmt.Errorf("variable %s for factor not found", f.For)
+			return nil, newVariableError(f.For, ErrUnknownVariable, "variable %s for factor not found", f.For)
 		}
 
 		// collect conditional variables
-		variables := make([]ve.Variable, len(f.Given))
-		for j, v := range f.Given {
-			vv, ok := varNames[v]
-			if !ok {
-				return nil, nil, fmt.Errorf("variable %s in factor for %s not found", v, f.For)
-			}
-			variables[j] = vv.VeVariable
+		variables, err := givenVariables(&f, varNames)
+		if err != nil {
+			return nil, err
 		}
 
 		// don't add factors for unsolved decision nodes, but add dependencies
@@ -474,10 +579,23 @@ func (n *Network) toVE(evidence map[string]string) (*ve.VE, map[string]*variable
 			continue
 		}
 
+		// use a factorized representation for canonical models with many parents
+		if f.Noisy != nil && len(variables) > 2 {
+			noisyFactors, err := n.noisyFactors(vars, &f, forVar.VeVariable, variables, &nextID)
+			if err != nil {
+				return nil, err
+			}
+			factors = append(factors, noisyFactors...)
+			continue
+		}
+
 		// append primary variable as last variable of the factor
 		variables = append(variables, forVar.VeVariable)
 
-		factor := vars.CreateFactor(variables, f.Table)
+		factor, err := vars.TryCreateFactor(variables, f.Table)
+		if err != nil {
+			return nil, newVariableError(f.For, ErrTableShape, "invalid table for variable %s: %s", f.For, err.Error())
+		}
 
 		// normalize for primary chance variable
 		if forVar.Variable.NodeType == ve.ChanceNode {
@@ -487,20 +605,91 @@ func (n *Network) toVE(evidence map[string]string) (*ve.VE, map[string]*variable
 		// add to list of factors
 		factors = append(factors, factor)
 	}
+	return factors, nil
+}
 
-	// add policies as factors
-	factors = append(factors, n.policyFactors(vars, varIDs, evidence)...)
+// skipFactor checks whether a factor should be skipped when creating solver factors.
+func (n *Network) skipFactor(f *Factor, varNames map[string]*variable, do map[string]string, totalUtilityName string) bool {
+	// skip factor for total utility, and factors replaced by interventions
+	if _, isDo := do[f.For]; isDo || f.For == totalUtilityName {
+		return true
+	}
+	// skip factors of continuous variables
+	_, ok := varNames[f.For]
+	return !ok && n.isContinuous(f.For)
+}
 
-	weights, err := n.prepareUtilityWeights()
-	if err != nil {
-		return nil, nil, err
+// givenVariables returns the solver variables for the conditional variables of a factor.
+func givenVariables(f *Factor, varNames map[string]*variable) ([]ve.Variable, error) {
+	variables := make([]ve.Variable, len(f.Given))
+	for j, v := range f.Given {
+		vv, ok := varNames[v]
+		if !ok {
+			return nil, newVariableError(v, ErrUnknownVariable, "variable %s in factor for %s not found", v, f.For)
+		}
+		variables[j] = vv.VeVariable
 	}
+	return variables, nil
+}
 
-	return ve.New(vars, factors, dependencies, weights), varNames, nil
+// collectVariables creates VE variables for all variables except the total utility node.
+//
+// Decision variables with a policy or an intervention are treated as chance variables.
+func (n *Network) collectVariables(vars *ve.Variables, do map[string]string) ([]variable, map[string]*variable) {
+	varNames := map[string]*variable{}
+	varIDs := make([]variable, len(n.variables))
+
+	for i, v := range n.variables {
+		// skip total utility node without utility function, and continuous variables
+		if (i == n.totalUtilityIndex && !n.hasUtilityFunction()) || v.NodeType == ve.ContinuousNode {
+			continue
+		}
+		nodeType := v.NodeType
+		// treat decision variables with policy or intervention as normal chance variables
+		if nodeType == ve.DecisionNode {
+			_, hasPolicy := n.policies[v.Name]
+			_, isDo := do[v.Name]
+			if hasPolicy || isDo {
+				nodeType = ve.ChanceNode
+			}
+		}
+		outcomes := len(v.Outcomes)
+		if i == n.totalUtilityIndex {
+			outcomes = 1
+		}
+		varIDs[i] = variable{
+			Variable:   v,
+			VeVariable: vars.AddVariable(i, nodeType, uint16(outcomes)),
+		}
+		varNames[v.Name] = &varIDs[i]
+	}
+	return varIDs, varNames
 }
 
-// prepareUtilityWeights derives utility weights from a potential total utility node.
+// prepareUtilityWeights derives utility weights for the solver from a potential total utility node.
+//
+// With a utility function, only the combined utility of the total utility node is used.
 func (n *Network) prepareUtilityWeights() ([]float64, error) {
+	if !n.hasUtilityFunction() {
+		return n.attributeWeights()
+	}
+	weights := []float64{}
+	for i, v := range n.variables {
+		if v.NodeType != ve.UtilityNode {
+			continue
+		}
+		if i == n.totalUtilityIndex {
+			weights = append(weights, 1)
+		} else {
+			weights = append(weights, 0)
+		}
+	}
+	return weights, nil
+}
+
+// attributeWeights derives the weights of utility nodes from a potential total utility node.
+// Returns nil if there is no total utility node.
+func (n *Network) attributeWeights() ([]float64, error) {
 	utilityNodes := []*Variable{}
 
 	// collect variables for lookup
@@ -526,7 +715,7 @@ func (n *Network) prepareUtilityWeights() ([]float64, error) {
 	for i := range utilityNodes {
 		idx := slices.Index(parents, utilityNodes[i].Name)
 		if idx < 0 {
-			return nil, fmt.Errorf("utility node %s not included in total utility", utilityNodes[i].Name)
+			return nil, newVariableError(utilityNodes[i].Name, ErrDefinition, "utility node %s not included in total utility", utilityNodes[i].Name)
 		}
 		weights[i] = table[idx]
 	}
@@ -535,13 +724,17 @@ func (n *Network) prepareUtilityWeights() ([]float64, error) {
 }
 
 // policyFactors collects policies as factors.
-func (n *Network) policyFactors(vars *ve.Variables, varIDs []variable, evidence map[string]string) []ve.Factor {
+func (n *Network) policyFactors(vars *ve.Variables, varIDs []variable, evidence map[string]string, do map[string]string) []ve.Factor {
 	factors := []ve.Factor{}
 	for name, f := range n.policies {
 		// if decision variable has evidence (and policies are ignored), don't add a factor
 		if _, isEvidence := evidence[name]; isEvidence {
 			continue
 		}
+		// if decision variable is intervened, don't add a factor
+		if _, isDo := do[name]; isDo {
+			continue
+		}
 		// collect variables
 		variables := make([]ve.Variable, len(f.Variables()))
 		for i, v := range f.Variables() {
@@ -574,36 +767,74 @@ func (n *Network) NormalizeUtility(utility *ve.Factor, probs *ve.Factor) ve.Fact
 }
 
 // Marginal calculates marginal probabilities from a factor for a variable.
+//
+// Panics if the variable is not in the network or not in the factor.
+// See [Network.TryMarginal] for an error-returning variant.
 func (n *Network) Marginal(f *ve.Factor, variable string) ve.Factor {
+	m, err := n.TryMarginal(f, variable)
+	if err != nil {
+		panic(err)
+	}
+	return m
+}
+
+// TryMarginal calculates marginal probabilities from a factor for a variable.
+//
+// Returns an error wrapping [ErrUnknownVariable] if the variable is not in the network or not in the factor.
+func (n *Network) TryMarginal(f *ve.Factor, variable string) (ve.Factor, error) {
 	vv, ok := n.variableNames[variable]
 	if !ok {
-		panic(fmt.Sprintf("marginal: variable %s not found", variable))
+		return ve.Factor{}, newVariableError(variable, ErrUnknownVariable, "marginal: variable %s not found", variable)
 	}
-	return n.ve.Variables().Marginal(f, vv.VeVariable)
+	m, err := n.ve.Variables().TryMarginal(f, vv.VeVariable)
+	if err != nil {
+		return ve.Factor{}, newVariableError(variable, ErrUnknownVariable, "marginal: variable %s not in factor", variable)
+	}
+	return m, nil
 }
 
 // Rearrange a factor for the given variable order.
+//
+// Panics if a variable is not in the network or not in the factor.
+// See [Network.TryRearrange] for an error-returning variant.
 func (n *Network) Rearrange(f *ve.Factor, variables []string) ve.Factor {
-	vars := n.rearrangeVariables(f, variables)
-	return n.ve.Variables().Rearrange(f, vars)
+	r, err := n.TryRearrange(f, variables)
+	if err != nil {
+		panic(err)
+	}
+	return r
+}
+
+// TryRearrange rearranges a factor for the given variable order.
+//
+// Returns an error wrapping [ErrUnknownVariable] if a variable is not in the network or not in the factor.
+func (n *Network) TryRearrange(f *ve.Factor, variables []string) (ve.Factor, error) {
+	vars, err := n.rearrangeVariables(f, variables)
+	if err != nil {
+		return ve.Factor{}, err
+	}
+	return n.ve.Variables().TryRearrange(f, vars)
 }
 
-func (n *Network) rearrangeVariables(f *ve.Factor, variables []string) []ve.Variable {
+func (n *Network) rearrangeVariables(f *ve.Factor, variables []string) ([]ve.Variable, error) {
+	if len(variables) == 0 {
+		return nil, fmt.Errorf("%w: no variables to rearrange", ErrTableShape)
+	}
 	fVariables := f.Variables()
 	vars := make([]ve.Variable, 0, len(fVariables))
 	done := make([]bool, len(fVariables))
 	for i := 0; i < len(variables)-1; i++ {
-		idx, ok := n.variableIndex(f, variables[i])
-		if !ok {
-			panic(fmt.Sprintf("variable %s to rearrange not in factor", variables[i]))
+		idx, err := n.variableIndex(f, variables[i])
+		if err != nil {
+			return nil, err
 		}
 		vars = append(vars, fVariables[idx])
 		done[idx] = true
 	}
 
-	idx, ok := n.variableIndex(f, variables[len(variables)-1])
-	if !ok {
-		panic(fmt.Sprintf("variable %s to rearrange not in factor", variables[len(variables)-1]))
+	idx, err := n.variableIndex(f, variables[len(variables)-1])
+	if err != nil {
+		return nil, err
 	}
 	last := fVariables[idx]
 
@@ -616,17 +847,17 @@ func (n *Network) rearrangeVariables(f *ve.Factor, variables []string) []ve.Vari
 
 	vars = append(vars, last)
 
-	return vars
+	return vars, nil
 }
 
-func (n *Network) variableIndex(f *ve.Factor, v string) (int, bool) {
+func (n *Network) variableIndex(f *ve.Factor, v string) (int, error) {
 	variable, ok := n.variableNames[v]
 	if !ok {
-		panic(fmt.Sprintf("variable %s not found in network", v))
+		return -1, newVariableError(v, ErrUnknownVariable, "variable %s not found in network", v)
 	}
 	idx := slices.IndexFunc(f.Variables(), variable.VeVariable.Is)
 	if idx < 0 {
-		return -1, false
+		return -1, newVariableError(v, ErrUnknownVariable, "variable %s to rearrange not in factor", v)
 	}
-	return idx, true
+	return idx, nil
 }
@@ -32,21 +32,28 @@ func TestFactorRow(t *testing.T) {
 
 	variable := net.Variables()[2]
 
-	s, ok := variable.Factor.rowIndex([]int{0, 0})
+	s, ok, err := variable.Factor.rowIndex([]int{0, 0})
+	assert.Nil(t, err)
 	assert.True(t, ok)
 	assert.Equal(t, 0, s)
 
-	s, ok = variable.Factor.rowIndex([]int{1, 1})
+	s, ok, err = variable.Factor.rowIndex([]int{1, 1})
+	assert.Nil(t, err)
 	assert.True(t, ok)
 	assert.Equal(t, 3, s)
 
-	s, ok = variable.Factor.rowIndex([]int{2, 0})
+	s, ok, err = variable.Factor.rowIndex([]int{2, 0})
+	assert.Nil(t, err)
 	assert.True(t, ok)
 	assert.Equal(t, 4, s)
 
-	s, ok = variable.Factor.rowIndex([]int{-1, 0})
+	s, ok, err = variable.Factor.rowIndex([]int{-1, 0})
+	assert.Nil(t, err)
 	assert.False(t, ok)
 	_ = s
+
+	_, _, err = variable.Factor.rowIndex([]int{0})
+	assert.ErrorIs(t, err, ErrTableShape)
 }
 
 func TestNetworkToVE(t *testing.T) {
@@ -79,7 +86,7 @@ func TestNetworkToVE(t *testing.T) {
 	n, err := New("umbrella", "", vars, factors)
 	assert.Nil(t, err)
 
-	v, variables, err := n.toVE(nil)
+	v, variables, err := n.toVE(nil, nil, nil)
 	assert.Nil(t, err)
 
 	result1 := v.SolveUtility(nil, nil, nil)
@@ -289,12 +296,18 @@ func TestNetworkRearrange(t *testing.T) {
 	vars := []ve.Variable(f.Variables())
 	a, b, c, d := vars[0], vars[1], vars[2], vars[3]
 
-	result := net.rearrangeVariables(f, []string{"a", "b", "c", "d"})
+	result, err := net.rearrangeVariables(f, []string{"a", "b", "c", "d"})
+	assert.Nil(t, err)
 	assert.Equal(t, vars, result)
 
-	result = net.rearrangeVariables(f, []string{"d", "c", "b", "a"})
+	result, err = net.rearrangeVariables(f, []string{"d", "c", "b", "a"})
+	assert.Nil(t, err)
 	assert.Equal(t, []ve.Variable{d, c, b, a}, result)
 
-	result = net.rearrangeVariables(f, []string{"d", "a"})
+	result, err = net.rearrangeVariables(f, []string{"d", "a"})
+	assert.Nil(t, err)
 	assert.Equal(t, []ve.Variable{d, b, c, a}, result)
+
+	_, err = net.rearrangeVariables(f, []string{"d", "x"})
+	assert.ErrorIs(t, err, ErrUnknownVariable)
 }
@@ -0,0 +1,54 @@
+package bbn
+
+import (
+	"github.com/mlange-42/bbn/noisy"
+	"github.com/mlange-42/bbn/ve"
+)
+
+// prepareNoisyNode generates the table of a variable with a canonical model, called from prepareVariables.
+func prepareNoisyNode(v *Variable) error {
+	if v.Factor.Noisy == nil {
+		return nil
+	}
+	if v.NodeType != ve.ChanceNode {
+		return newVariableError(v.Name, ErrTableShape, "canonical models are only supported for chance variables; got %s", v.Name)
+	}
+	table, err := noisy.Table(v.Factor.Noisy, len(v.Outcomes), v.Factor.outcomes)
+	if err != nil {
+		return newVariableError(v.Name, ErrTableShape, "canonical model for %s: %s", v.Name, err.Error())
+	}
+	v.Factor.Table = table
+	return nil
+}
+
+// noisyFactors creates a factorized representation of a canonical model, instead of a single factor for the full table.
+//
+// Uses a sequential decomposition with len(parents) auxiliary variables: one leak variable,
+// distributed according to the leak, plus len(parents)-1 chain variables.
+// Each chain variable combines the previous auxiliary variable with one parent.
+// The last parent is combined into the primary variable.
+// Thus, factor sizes grow linearly with the number of parents, rather than exponentially.
+// Auxiliary variables get IDs starting at nextID, which is updated accordingly.
+func (n *Network) noisyFactors(vars *ve.Variables, f *Factor, primary ve.Variable, parents []ve.Variable, nextID *int) ([]ve.Factor, error) {
+	outcomes := f.columns
+	d, err := f.Noisy.Decompose(outcomes, f.outcomes)
+	if err != nil {
+		return nil, newVariableError(f.For, ErrTableShape, "canonical model for %s: %s", f.For, err.Error())
+	}
+
+	factors := make([]ve.Factor, 0, len(parents)+1)
+	acc := vars.AddVariable(*nextID, ve.ChanceNode, uint16(outcomes))
+	*nextID++
+	factors = append(factors, vars.CreateFactor([]ve.Variable{acc}, d.Leak))
+
+	for i, parent := range parents {
+		next := primary
+		if i < len(parents)-1 {
+			next = vars.AddVariable(*nextID, ve.ChanceNode, uint16(outcomes))
+			*nextID++
+		}
+		factors = append(factors, vars.CreateFactor([]ve.Variable{acc, parent, next}, d.Step(i)))
+		acc = next
+	}
+	return factors, nil
+}
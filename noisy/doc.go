@@ -0,0 +1,13 @@
+// Package noisy provides canonical models for conditional probability tables,
+// like noisy-OR, noisy-AND and noisy-MAX.
+//
+// Canonical models assume independence of causal influence:
+// each parent influences the child independently of the other parents,
+// parameterized by one link probability (or distribution) per parent.
+// Further, an optional leak accounts for causes that are not modelled explicitly.
+// Thus, the number of parameters grows linearly with the number of parents,
+// rather than exponentially like for a full table.
+//
+// As in package [github.com/mlange-42/bbn/logic], the first outcome (index 0) of binary variables
+// is considered True, while the second outcome (index 1) is considered False.
+package noisy
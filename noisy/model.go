@@ -0,0 +1,142 @@
+package noisy
+
+import (
+	"fmt"
+	"math"
+)
+
+// Model is an interface for canonical models.
+type Model interface {
+	// Decompose returns the decomposition of the model for a child variable with the given number of outcomes,
+	// and parents with the given numbers of outcomes.
+	Decompose(child int, parents []int) (*Decomposition, error)
+}
+
+// Table returns the full CPT of a model, for a child variable with the given number of outcomes,
+// and parents with the given numbers of outcomes.
+func Table(m Model, child int, parents []int) ([]float64, error) {
+	d, err := m.Decompose(child, parents)
+	if err != nil {
+		return nil, err
+	}
+	return d.Table(), nil
+}
+
+// Decomposition of a canonical model into independent contributions of parents.
+//
+// Each parent i contributes a child outcome, drawn from Links[i] given the parent's outcome.
+// The leak contributes an outcome drawn from Leak. The child's outcome is the minimum
+// of all contributed outcome indices, or the maximum if Max is true.
+type Decomposition struct {
+	Child   int         // Number of outcomes of the child.
+	Parents []int       // Number of outcomes of the parents.
+	Links   [][]float64 // Per parent, a table of the contributed child outcome given the parent outcome.
+	Leak    []float64   // Distribution of the outcome contributed by the leak.
+	Max     bool        // Whether contributions are combined by maximum instead of minimum outcome index.
+}
+
+// Table returns the full CPT.
+func (d *Decomposition) Table() []float64 {
+	rows := 1
+	for _, p := range d.Parents {
+		rows *= p
+	}
+	table := make([]float64, rows*d.Child)
+	indices := make([]int, len(d.Parents))
+	cumulative := make([]float64, d.Child)
+	for row := 0; row < rows; row++ {
+		for y := range cumulative {
+			cumulative[y] = d.cumulative(d.Leak, y)
+			for i, x := range indices {
+				cumulative[y] *= d.cumulative(d.Links[i][x*d.Child:(x+1)*d.Child], y)
+			}
+		}
+		d.fromCumulative(cumulative, table[row*d.Child:(row+1)*d.Child])
+		increment(indices, d.Parents)
+	}
+	return table
+}
+
+// Step returns the table for step i of the sequential decomposition,
+// with the accumulated outcome of steps before i and parent i as parents, in that order.
+//
+// The accumulated outcome before the first step is distributed by Leak.
+func (d *Decomposition) Step(i int) []float64 {
+	parent := d.Parents[i]
+	table := make([]float64, d.Child*parent*d.Child)
+	for acc := 0; acc < d.Child; acc++ {
+		for x := 0; x < parent; x++ {
+			row := (acc*parent + x) * d.Child
+			for z, p := range d.Links[i][x*d.Child : (x+1)*d.Child] {
+				table[row+d.combine(acc, z)] += p
+			}
+		}
+	}
+	return table
+}
+
+// cumulative returns the probability that a contribution with the given distribution
+// does not exceed outcome y in the combination order.
+func (d *Decomposition) cumulative(dist []float64, y int) float64 {
+	sum := 0.0
+	if d.Max {
+		for z := 0; z <= y; z++ {
+			sum += dist[z]
+		}
+	} else {
+		for z := y; z < len(dist); z++ {
+			sum += dist[z]
+		}
+	}
+	return sum
+}
+
+// fromCumulative converts cumulative probabilities to a distribution.
+func (d *Decomposition) fromCumulative(cumulative []float64, dist []float64) {
+	for y := range dist {
+		var next float64
+		if d.Max && y > 0 {
+			next = cumulative[y-1]
+		} else if !d.Max && y < len(dist)-1 {
+			next = cumulative[y+1]
+		}
+		dist[y] = math.Max(cumulative[y]-next, 0)
+	}
+}
+
+func (d *Decomposition) combine(a, b int) int {
+	if d.Max == (a > b) {
+		return a
+	}
+	return b
+}
+
+// increment parent indices, with the last parent changing fastest.
+func increment(indices []int, outcomes []int) {
+	for i := len(indices) - 1; i >= 0; i-- {
+		indices[i]++
+		if indices[i] < outcomes[i] {
+			return
+		}
+		indices[i] = 0
+	}
+}
+
+func checkProbability(p float64, what string) error {
+	if math.IsNaN(p) || p < 0 || p > 1 {
+		return fmt.Errorf("%s must be in [0, 1]; got %f", what, p)
+	}
+	return nil
+}
+
+func checkBinary(child int, parents []int) error {
+	if child != 2 {
+		return fmt.Errorf("model requires a binary child variable; got %d outcomes", child)
+	}
+	for i, p := range parents {
+		if p != 2 {
+			return fmt.Errorf("model requires binary parent variables; got %d outcomes for parent %d", p, i)
+		}
+	}
+	return nil
+}
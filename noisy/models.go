@@ -0,0 +1,139 @@
+package noisy
+
+import (
+	"fmt"
+	"math"
+)
+
+// Or is a noisy-OR model for binary variables.
+//
+// The child is True if any True parent causes it, which happens independently with the parent's link probability.
+// A leak probability greater than zero makes it a leaky noisy-OR,
+// where the child can also be True without any True parent.
+type Or struct {
+	Links []float64 // Probability that the child is True, if only the respective parent is True.
+	Leak  float64   // Probability that the child is True if all parents are False.
+}
+
+// Decompose implements [Model].
+func (m *Or) Decompose(child int, parents []int) (*Decomposition, error) {
+	d, err := binary(m.Links, m.Leak, child, parents)
+	if err != nil {
+		return nil, err
+	}
+	for i, p := range m.Links {
+		d.Links[i] = []float64{p, 1 - p, 0, 1}
+	}
+	d.Leak = []float64{m.Leak, 1 - m.Leak}
+	return d, nil
+}
+
+// And is a noisy-AND model for binary variables.
+//
+// The child is False if any False parent inhibits it, which happens independently with the parent's link probability.
+// A leak probability greater than zero allows the child to be False even if all parents are True.
+type And struct {
+	Links []float64 // Probability that the child is False, if only the respective parent is False.
+	Leak  float64   // Probability that the child is False if all parents are True.
+}
+
+// Decompose implements [Model].
+func (m *And) Decompose(child int, parents []int) (*Decomposition, error) {
+	d, err := binary(m.Links, m.Leak, child, parents)
+	if err != nil {
+		return nil, err
+	}
+	for i, p := range m.Links {
+		d.Links[i] = []float64{1, 0, 1 - p, p}
+	}
+	d.Leak = []float64{1 - m.Leak, m.Leak}
+	d.Max = true
+	return d, nil
+}
+
+// Max is a noisy-MAX model for graded variables.
+//
+// Outcomes of the child and the parents are ordered by severity, with the first outcome being the most severe,
+// and the last outcome being the normal state (i.e. absence). For binary variables, this equals noisy-OR.
+// The child's outcome is the most severe outcome caused by any parent or by the leak.
+type Max struct {
+	// Distributions of the child's outcome caused by a single parent, one for each non-normal parent outcome.
+	// Parents and their outcomes are in row-major order,
+	// i.e. the first rows are for the first parent, one row per outcome except the last (normal) one.
+	Links [][]float64
+	// Distribution of the child's outcome if all parents are in their normal state.
+	// Optional, defaults to the normal state.
+	Leak []float64
+}
+
+// Decompose implements [Model].
+func (m *Max) Decompose(child int, parents []int) (*Decomposition, error) {
+	rows := 0
+	for _, p := range parents {
+		rows += p - 1
+	}
+	if len(m.Links) != rows {
+		return nil, fmt.Errorf("noisy-MAX model requires %d link rows for parents with %v outcomes; got %d", rows, parents, len(m.Links))
+	}
+	leak := m.Leak
+	if leak == nil {
+		leak = make([]float64, child)
+		leak[child-1] = 1
+	}
+	if err := checkDistribution(leak, child, "leak"); err != nil {
+		return nil, err
+	}
+
+	d := Decomposition{Child: child, Parents: parents, Links: make([][]float64, len(parents)), Leak: leak}
+	row := 0
+	for i, p := range parents {
+		table := make([]float64, 0, p*child)
+		for x := 0; x < p-1; x++ {
+			if err := checkDistribution(m.Links[row], child, fmt.Sprintf("link row %d", row)); err != nil {
+				return nil, err
+			}
+			table = append(table, m.Links[row]...)
+			row++
+		}
+		normal := make([]float64, child)
+		normal[child-1] = 1
+		d.Links[i] = append(table, normal...)
+	}
+	return &d, nil
+}
+
+// binary creates a decomposition for binary models, with unset links and leak.
+func binary(links []float64, leak float64, child int, parents []int) (*Decomposition, error) {
+	if err := checkBinary(child, parents); err != nil {
+		return nil, err
+	}
+	if len(links) != len(parents) {
+		return nil, fmt.Errorf("model requires one link probability per parent; got %d for %d parents", len(links), len(parents))
+	}
+	for i, p := range links {
+		if err := checkProbability(p, fmt.Sprintf("link probability %d", i)); err != nil {
+			return nil, err
+		}
+	}
+	if err := checkProbability(leak, "leak probability"); err != nil {
+		return nil, err
+	}
+	return &Decomposition{Child: child, Parents: parents, Links: make([][]float64, len(parents))}, nil
+}
+
+func checkDistribution(dist []float64, length int, what string) error {
+	if len(dist) != length {
+		return fmt.Errorf("%s requires %d probabilities; got %d", what, length, len(dist))
+	}
+	sum := 0.0
+	for _, p := range dist {
+		if err := checkProbability(p, what); err != nil {
+			return err
+		}
+		sum += p
+	}
+	if math.Abs(sum-1) > 1e-6 {
+		return fmt.Errorf("%s must sum to 1; got %f", what, sum)
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+package noisy_test
+
+import (
+	"testing"
+
+	"github.com/mlange-42/bbn/noisy"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOr(t *testing.T) {
+	m := noisy.Or{Links: []float64{0.8, 0.5}, Leak: 0.1}
+	table, err := noisy.Table(&m, 2, []int{2, 2})
+	assert.Nil(t, err)
+
+	expected := []float64{
+		1 - 0.9*0.2*0.5, 0.9 * 0.2 * 0.5, // T T
+		1 - 0.9*0.2, 0.9 * 0.2, // T F
+		1 - 0.9*0.5, 0.9 * 0.5, // F T
+		0.1, 0.9, // F F
+	}
+	assert.InDeltaSlice(t, expected, table, 1e-12)
+
+	_, err = noisy.Table(&m, 2, []int{2})
+	assert.NotNil(t, err)
+	_, err = noisy.Table(&m, 3, []int{2, 2})
+	assert.NotNil(t, err)
+	_, err = noisy.Table(&noisy.Or{Links: []float64{1.5}}, 2, []int{2})
+	assert.NotNil(t, err)
+}
+
+func TestAnd(t *testing.T) {
+	m := noisy.And{Links: []float64{0.8, 0.5}, Leak: 0.1}
+	table, err := noisy.Table(&m, 2, []int{2, 2})
+	assert.Nil(t, err)
+
+	expected := []float64{
+		0.9, 0.1, // T T
+		0.9 * 0.5, 1 - 0.9*0.5, // T F
+		0.9 * 0.2, 1 - 0.9*0.2, // F T
+		0.9 * 0.2 * 0.5, 1 - 0.9*0.2*0.5, // F F
+	}
+	assert.InDeltaSlice(t, expected, table, 1e-12)
+
+	// deterministic AND
+	table, err = noisy.Table(&noisy.And{Links: []float64{1, 1}}, 2, []int{2, 2})
+	assert.Nil(t, err)
+	assert.InDeltaSlice(t, []float64{1, 0, 0, 1, 0, 1, 0, 1}, table, 1e-12)
+}
+
+func TestMax(t *testing.T) {
+	// equals noisy-OR for binary variables
+	m := noisy.Max{Links: [][]float64{{0.8, 0.2}, {0.5, 0.5}}, Leak: []float64{0.1, 0.9}}
+	table, err := noisy.Table(&m, 2, []int{2, 2})
+	assert.Nil(t, err)
+	or, err := noisy.Table(&noisy.Or{Links: []float64{0.8, 0.5}, Leak: 0.1}, 2, []int{2, 2})
+	assert.Nil(t, err)
+	assert.InDeltaSlice(t, or, table, 1e-12)
+
+	// child [severe, mild, none], parents A [high, low, none], B [yes, no]
+	m = noisy.Max{Links: [][]float64{
+		{0.5, 0.3, 0.2}, // A high
+		{0.1, 0.4, 0.5}, // A low
+		{0.2, 0.6, 0.2}, // B yes
+	}}
+	table, err = noisy.Table(&m, 3, []int{3, 2})
+	assert.Nil(t, err)
+	assert.Len(t, table, 18)
+
+	// A high, B yes: P(none) = 0.2 * 0.2, P(severe) = 1 - 0.5 * 0.8
+	assert.InDelta(t, 1-0.5*0.8, table[0], 1e-12)
+	assert.InDelta(t, 0.2*0.2, table[2], 1e-12)
+	// A low, B no: only A contributes
+	assert.InDeltaSlice(t, []float64{0.1, 0.4, 0.5}, table[9:12], 1e-12)
+	// A none, B no: leak defaults to normal state
+	assert.InDeltaSlice(t, []float64{0, 0, 1}, table[15:18], 1e-12)
+
+	_, err = noisy.Table(&m, 3, []int{2, 2})
+	assert.NotNil(t, err)
+	_, err = noisy.Table(&noisy.Max{Links: [][]float64{{0.5, 0.6}}}, 2, []int{2})
+	assert.NotNil(t, err)
+}
+
+func TestDecompositionStep(t *testing.T) {
+	m := noisy.Or{Links: []float64{0.8, 0.5, 0.3}, Leak: 0.1}
+	d, err := m.Decompose(2, []int{2, 2, 2})
+	assert.Nil(t, err)
+
+	table := d.Table()
+	for x := 0; x < 8; x++ {
+		// sequential decomposition: leak, then one step per parent
+		acc := d.Leak
+		for i := 0; i < 3; i++ {
+			xi := (x >> (2 - i)) & 1
+			step := d.Step(i)
+			next := make([]float64, 2)
+			for a := 0; a < 2; a++ {
+				for y := 0; y < 2; y++ {
+					next[y] += acc[a] * step[(a*2+xi)*2+y]
+				}
+			}
+			acc = next
+		}
+		assert.InDeltaSlice(t, table[x*2:x*2+2], acc, 1e-12)
+	}
+}
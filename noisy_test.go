@@ -0,0 +1,160 @@
+package bbn_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/mlange-42/bbn"
+	"github.com/mlange-42/bbn/noisy"
+	"github.com/stretchr/testify/assert"
+)
+
+// withoutModels creates a copy of a network, with full tables instead of canonical models.
+func withoutModels(t *testing.T, net *bbn.Network) *bbn.Network {
+	variables := slices.Clone(net.Variables())
+	factors := make([]bbn.Factor, len(variables))
+	for i := range variables {
+		f := variables[i].Factor
+		factors[i] = bbn.Factor{For: f.For, Given: f.Given, Table: f.Table}
+		variables[i].Factor = nil
+	}
+	full, err := bbn.New(net.Name(), net.Info(), variables, factors)
+	assert.Nil(t, err)
+	return full
+}
+
+func TestNetworkNoisy(t *testing.T) {
+	net, err := bbn.FromFile("_examples/bbn/car-start.yml")
+	assert.Nil(t, err)
+	full := withoutModels(t, net)
+
+	noStart := net.Variables()[6]
+	assert.Len(t, noStart.Factor.Table, 128)
+	assert.IsType(t, &noisy.Or{}, noStart.Factor.Noisy)
+
+	query := []string{"Battery", "Starter", "Fuel", "Spark Plugs", "Fuel Pump", "Ignition", "No Start", "Engine Noise"}
+	evidences := []map[string]string{
+		{},
+		{"No Start": "yes"},
+		{"No Start": "yes", "Battery": "ok"},
+		{"No Start": "no", "Engine Noise": "loud"},
+	}
+	for _, evidence := range evidences {
+		for _, q := range query {
+			if _, ok := evidence[q]; ok {
+				continue
+			}
+			expected, _, err := full.SolveQuery(evidence, []string{q}, false)
+			assert.Nil(t, err)
+			result, _, err := net.SolveQuery(evidence, []string{q}, false)
+			assert.Nil(t, err)
+			assert.InDeltaSlice(t, expected[q], result[q], 1e-9, "%s given %v", q, evidence)
+		}
+	}
+
+	result, _, err := net.SolveQuery(map[string]string{"Battery": "ok", "Starter": "ok", "Fuel": "ok", "Spark Plugs": "ok", "Fuel Pump": "ok", "Ignition": "ok"}, []string{"No Start"}, false)
+	assert.Nil(t, err)
+	assert.InDeltaSlice(t, []float64{0.01, 0.99}, result["No Start"], 1e-9)
+
+	yml, err := bbn.ToYAML(net)
+	assert.Nil(t, err)
+	net2, err := bbn.FromYAML(yml)
+	assert.Nil(t, err)
+	assert.Equal(t, net.Variables()[6].Factor.Noisy, net2.Variables()[6].Factor.Noisy)
+	assert.Equal(t, net.Variables()[7].Factor.Noisy, net2.Variables()[7].Factor.Noisy)
+	assert.InDeltaSlice(t, net.Variables()[7].Factor.Table, net2.Variables()[7].Factor.Table, 1e-12)
+}
+
+func TestNetworkNoisyMutation(t *testing.T) {
+	net, err := bbn.FromFile("_examples/bbn/car-start.yml")
+	assert.Nil(t, err)
+
+	assert.Nil(t, net.RenameVariable("Battery", "Accumulator"))
+	assert.IsType(t, &noisy.Or{}, net.Variables()[6].Factor.Noisy)
+
+	assert.Nil(t, net.RemoveEdge("Accumulator", "No Start"))
+	noStart := net.Variables()[6]
+	assert.Nil(t, noStart.Factor.Noisy)
+	assert.Len(t, noStart.Factor.Table, 64)
+}
+
+func TestNetworkNoisyBuilder(t *testing.T) {
+	b := bbn.NewBuilder("Test", "")
+	causes := []string{"A", "B", "C", "D"}
+	for _, c := range causes {
+		b.AddChance(c, "yes", "no").SetTable(c, []float64{0.2, 0.8})
+	}
+	b.AddChance("E", "yes", "no")
+	for _, c := range causes {
+		b.AddEdge(c, "E")
+	}
+	net, err := b.SetNoisy("E", &noisy.And{Links: []float64{0.9, 0.8, 0.7, 0.6}, Leak: 0.05}).Build()
+	assert.Nil(t, err)
+
+	full := withoutModels(t, net)
+	expected, _, err := full.SolveQuery(map[string]string{"E": "no"}, []string{"A"}, false)
+	assert.Nil(t, err)
+	result, _, err := net.SolveQuery(map[string]string{"E": "no"}, []string{"A"}, false)
+	assert.Nil(t, err)
+	assert.InDeltaSlice(t, expected["A"], result["A"], 1e-9)
+
+	// mutation keeps the expanded table, but drops the model
+	assert.Nil(t, net.RemoveEdge("D", "E"))
+	assert.Nil(t, net.Variables()[4].Factor.Noisy)
+	assert.Len(t, net.Variables()[4].Factor.Table, 16)
+
+	_, err = bbn.NewBuilder("Test", "").
+		AddChance("A", "yes", "no").
+		AddChance("B", "yes", "no").
+		AddEdge("A", "B").
+		SetTable("A", []float64{0.5, 0.5}).
+		SetNoisy("B", &noisy.Or{Links: []float64{0.5, 0.5}}).
+		Build()
+	assert.ErrorIs(t, err, bbn.ErrTableShape)
+
+	_, err = bbn.NewBuilder("Test", "").
+		AddDecision("A", "yes", "no").
+		SetNoisy("A", &noisy.Or{}).
+		Build()
+	assert.ErrorIs(t, err, bbn.ErrTableShape)
+}
+
+func TestNetworkNoisyYAMLErrors(t *testing.T) {
+	tests := []string{
+		`
+name: Test
+variables:
+- variable: A
+  outcomes: [yes, no]
+  noisy: foo
+`,
+		`
+name: Test
+variables:
+- variable: A
+  outcomes: [yes, no]
+  noisy: or
+  table: [[0.5, 0.5]]
+`,
+		`
+name: Test
+variables:
+- variable: A
+  outcomes: [yes, no]
+  links: [0.5]
+  table: [[0.5, 0.5]]
+`,
+		`
+name: Test
+variables:
+- variable: A
+  outcomes: [yes, no]
+  noisy: or
+  leak: [0.1, 0.9]
+`,
+	}
+	for _, yml := range tests {
+		_, err := bbn.FromYAML([]byte(yml))
+		assert.NotNil(t, err, yml)
+	}
+}
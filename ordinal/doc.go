@@ -0,0 +1,10 @@
+// Package ordinal provides deterministic functions of ordinal, multi-valued parents,
+// like sum, min, max, mean and weighted threshold.
+//
+// Outcomes are mapped to numeric values. If all outcomes of a variable are numbers, like "0", "1", "2",
+// these numbers are used. Otherwise, outcome indices are used.
+//
+// Results are mapped to the child's outcome with the nearest numeric value.
+// For children with non-numeric outcomes, this is the rounded result, clamped to the range of outcome indices.
+// Ties are rounded up.
+package ordinal
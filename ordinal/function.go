@@ -0,0 +1,199 @@
+package ordinal
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// Function is an interface for deterministic functions of ordinal parents.
+type Function interface {
+	// Table returns the CPT for a child with the given outcomes, and parents with the given outcomes.
+	Table(child []string, parents [][]string) ([]float64, error)
+}
+
+// Sum of parent values, optionally weighted.
+type Sum struct {
+	Weights []float64 // Weights of the parents, optional. Defaults to 1 for all parents.
+}
+
+// Table implements [Function].
+func (f *Sum) Table(child []string, parents [][]string) ([]float64, error) {
+	weights, err := checkWeights(f.Weights, len(parents))
+	if err != nil {
+		return nil, err
+	}
+	return table(child, parents, func(values []float64) float64 {
+		sum := 0.0
+		for i, v := range values {
+			sum += weights[i] * v
+		}
+		return sum
+	})
+}
+
+// Mean of parent values, optionally weighted.
+type Mean struct {
+	Weights []float64 // Weights of the parents, optional. Defaults to 1 for all parents.
+}
+
+// Table implements [Function].
+func (f *Mean) Table(child []string, parents [][]string) ([]float64, error) {
+	weights, err := checkWeights(f.Weights, len(parents))
+	if err != nil {
+		return nil, err
+	}
+	total := 0.0
+	for _, w := range weights {
+		total += w
+	}
+	if total == 0 {
+		return nil, fmt.Errorf("weights of mean must not sum to zero")
+	}
+	return table(child, parents, func(values []float64) float64 {
+		sum := 0.0
+		for i, v := range values {
+			sum += weights[i] * v
+		}
+		return sum / total
+	})
+}
+
+// Min of parent values.
+type Min struct{}
+
+// Table implements [Function].
+func (f *Min) Table(child []string, parents [][]string) ([]float64, error) {
+	if len(parents) == 0 {
+		return nil, fmt.Errorf("min requires at least one parent")
+	}
+	return table(child, parents, func(values []float64) float64 {
+		m := values[0]
+		for _, v := range values[1:] {
+			m = math.Min(m, v)
+		}
+		return m
+	})
+}
+
+// Max of parent values.
+type Max struct{}
+
+// Table implements [Function].
+func (f *Max) Table(child []string, parents [][]string) ([]float64, error) {
+	if len(parents) == 0 {
+		return nil, fmt.Errorf("max requires at least one parent")
+	}
+	return table(child, parents, func(values []float64) float64 {
+		m := values[0]
+		for _, v := range values[1:] {
+			m = math.Max(m, v)
+		}
+		return m
+	})
+}
+
+// Threshold checks whether the weighted sum of parent values reaches a threshold.
+//
+// The child must have two outcomes. The first one (True) applies if the weighted sum
+// is greater than or equal to the threshold, the second one (False) otherwise.
+type Threshold struct {
+	Weights   []float64 // Weights of the parents, optional. Defaults to 1 for all parents.
+	Threshold float64   // The threshold.
+}
+
+// Table implements [Function].
+func (f *Threshold) Table(child []string, parents [][]string) ([]float64, error) {
+	if len(child) != 2 {
+		return nil, fmt.Errorf("threshold requires a child with 2 outcomes; got %d", len(child))
+	}
+	weights, err := checkWeights(f.Weights, len(parents))
+	if err != nil {
+		return nil, err
+	}
+	return table([]string{"1", "0"}, parents, func(values []float64) float64 {
+		sum := 0.0
+		for i, v := range values {
+			sum += weights[i] * v
+		}
+		if sum >= f.Threshold {
+			return 1
+		}
+		return 0
+	})
+}
+
+// table creates a deterministic CPT by applying fn to the values of each combination of parent outcomes.
+func table(child []string, parents [][]string, fn func(values []float64) float64) ([]float64, error) {
+	if len(child) == 0 {
+		return nil, fmt.Errorf("child variable has no outcomes")
+	}
+	childValues := Values(child)
+	parentValues := make([][]float64, len(parents))
+	rows := 1
+	for i, p := range parents {
+		parentValues[i] = Values(p)
+		rows *= len(p)
+	}
+
+	result := make([]float64, rows*len(child))
+	indices := make([]int, len(parents))
+	values := make([]float64, len(parents))
+	for row := 0; row < rows; row++ {
+		for i, idx := range indices {
+			values[i] = parentValues[i][idx]
+		}
+		result[row*len(child)+nearest(childValues, fn(values))] = 1
+
+		for i := len(indices) - 1; i >= 0; i-- {
+			indices[i]++
+			if indices[i] < len(parents[i]) {
+				break
+			}
+			indices[i] = 0
+		}
+	}
+	return result, nil
+}
+
+// Values returns the numeric values of outcomes.
+// These are the parsed outcomes if all of them are numbers, and outcome indices otherwise.
+func Values(outcomes []string) []float64 {
+	values := make([]float64, len(outcomes))
+	for i, o := range outcomes {
+		v, err := strconv.ParseFloat(o, 64)
+		if err != nil || math.IsNaN(v) {
+			for j := range values {
+				values[j] = float64(j)
+			}
+			return values
+		}
+		values[i] = v
+	}
+	return values
+}
+
+// nearest returns the index of the value nearest to x. Ties resolve to the larger value, i.e. round half up.
+func nearest(values []float64, x float64) int {
+	best, bestDist := 0, math.Inf(1)
+	for i, v := range values {
+		if d := math.Abs(v - x); d < bestDist || d == bestDist && v > values[best] {
+			best, bestDist = i, d
+		}
+	}
+	return best
+}
+
+func checkWeights(weights []float64, parents int) ([]float64, error) {
+	if weights == nil {
+		weights = make([]float64, parents)
+		for i := range weights {
+			weights[i] = 1
+		}
+		return weights, nil
+	}
+	if len(weights) != parents {
+		return nil, fmt.Errorf("got %d weights for %d parents", len(weights), parents)
+	}
+	return weights, nil
+}
@@ -0,0 +1,121 @@
+package ordinal_test
+
+import (
+	"testing"
+
+	"github.com/mlange-42/bbn/ordinal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValues(t *testing.T) {
+	assert.Equal(t, []float64{1, 2.5, -3}, ordinal.Values([]string{"1", "2.5", "-3"}))
+	assert.Equal(t, []float64{0, 1, 2}, ordinal.Values([]string{"low", "2", "high"}))
+	assert.Equal(t, []float64{}, ordinal.Values([]string{}))
+}
+
+func TestSum(t *testing.T) {
+	levels := []string{"low", "medium", "high"}
+	table, err := (&ordinal.Sum{}).Table(levels, [][]string{{"no", "yes"}, {"no", "yes"}})
+	assert.Nil(t, err)
+	assert.Equal(t, []float64{
+		1, 0, 0, // 0 + 0
+		0, 1, 0, // 0 + 1
+		0, 1, 0, // 1 + 0
+		0, 0, 1, // 1 + 1
+	}, table)
+
+	// clamped to the child's outcomes
+	table, err = (&ordinal.Sum{}).Table([]string{"0", "1"}, [][]string{{"0", "1"}, {"0", "1"}})
+	assert.Nil(t, err)
+	assert.Equal(t, []float64{1, 0, 0, 1, 0, 1, 0, 1}, table)
+
+	// numeric labels, weighted
+	table, err = (&ordinal.Sum{Weights: []float64{1, 10}}).Table(
+		[]string{"0", "5", "10", "20"}, [][]string{{"0", "2"}, {"0", "1"}},
+	)
+	assert.Nil(t, err)
+	assert.Equal(t, []float64{
+		1, 0, 0, 0, // 0 + 0
+		0, 0, 1, 0, // 0 + 10
+		1, 0, 0, 0, // 2 + 0
+		0, 0, 1, 0, // 2 + 10
+	}, table)
+
+	_, err = (&ordinal.Sum{Weights: []float64{1}}).Table(levels, [][]string{{"a", "b"}, {"a", "b"}})
+	assert.NotNil(t, err)
+	_, err = (&ordinal.Sum{}).Table([]string{}, [][]string{{"a", "b"}})
+	assert.NotNil(t, err)
+}
+
+func TestMinMax(t *testing.T) {
+	levels := []string{"low", "medium", "high"}
+	parents := [][]string{levels, {"low", "high"}}
+
+	table, err := (&ordinal.Min{}).Table(levels, parents)
+	assert.Nil(t, err)
+	assert.Equal(t, []float64{
+		1, 0, 0, // low, low
+		1, 0, 0, // low, high
+		1, 0, 0, // medium, low
+		0, 1, 0, // medium, high
+		1, 0, 0, // high, low
+		0, 1, 0, // high, high
+	}, table)
+
+	table, err = (&ordinal.Max{}).Table(levels, parents)
+	assert.Nil(t, err)
+	assert.Equal(t, []float64{
+		1, 0, 0, // low, low
+		0, 1, 0, // low, high
+		0, 1, 0, // medium, low
+		0, 1, 0, // medium, high
+		0, 0, 1, // high, low
+		0, 0, 1, // high, high
+	}, table)
+
+	_, err = (&ordinal.Min{}).Table(levels, nil)
+	assert.NotNil(t, err)
+	_, err = (&ordinal.Max{}).Table(levels, nil)
+	assert.NotNil(t, err)
+}
+
+func TestMean(t *testing.T) {
+	levels := []string{"1", "2", "3", "4", "5"}
+	table, err := (&ordinal.Mean{}).Table(levels, [][]string{{"1", "5"}, {"1", "4"}})
+	assert.Nil(t, err)
+	assert.Equal(t, []float64{
+		1, 0, 0, 0, 0, // 1
+		0, 0, 1, 0, 0, // 2.5, rounds up on tie
+		0, 0, 1, 0, 0, // 3
+		0, 0, 0, 0, 1, // 4.5, rounds up on tie
+	}, table)
+
+	table, err = (&ordinal.Mean{Weights: []float64{3, 1}}).Table(levels, [][]string{{"1", "5"}, {"1", "5"}})
+	assert.Nil(t, err)
+	assert.Equal(t, []float64{
+		1, 0, 0, 0, 0, // 1
+		0, 1, 0, 0, 0, // 2
+		0, 0, 0, 1, 0, // 4
+		0, 0, 0, 0, 1, // 5
+	}, table)
+
+	_, err = (&ordinal.Mean{Weights: []float64{1, -1}}).Table(levels, [][]string{{"1"}, {"1"}})
+	assert.NotNil(t, err)
+}
+
+func TestThreshold(t *testing.T) {
+	f := ordinal.Threshold{Weights: []float64{2, 1}, Threshold: 3}
+	table, err := f.Table([]string{"yes", "no"}, [][]string{{"a", "b", "c"}, {"0", "1"}})
+	assert.Nil(t, err)
+	assert.Equal(t, []float64{
+		0, 1, // 0 + 0
+		0, 1, // 0 + 1
+		0, 1, // 2 + 0
+		1, 0, // 2 + 1
+		1, 0, // 4 + 0
+		1, 0, // 4 + 1
+	}, table)
+
+	_, err = f.Table([]string{"a", "b", "c"}, [][]string{{"a", "b", "c"}, {"0", "1"}})
+	assert.NotNil(t, err)
+}
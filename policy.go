@@ -0,0 +1,122 @@
+package bbn
+
+import (
+	"math"
+
+	"github.com/mlange-42/bbn/ve"
+)
+
+// PolicyReport describes the solved policy of a decision. See [Network.PolicyReports].
+type PolicyReport struct {
+	Decision     string      `json:"decision"`     // Name of the decision variable.
+	Alternatives []string    `json:"alternatives"` // Outcomes of the decision variable.
+	Parents      []string    `json:"parents"`      // Informational parents, in topological order.
+	Rows         []PolicyRow `json:"rows"`         // One row per combination of parent outcomes, last parent varying fastest.
+}
+
+// PolicyRow is the optimal action of a decision, for a combination of outcomes of its informational parents.
+type PolicyRow struct {
+	Outcomes  []string  `json:"outcomes"`            // Outcomes of the informational parents.
+	Possible  bool      `json:"possible"`            // Whether the combination of parent outcomes can occur.
+	Action    string    `json:"action,omitempty"`    // Optimal action. Empty if the row can't occur.
+	Utilities []float64 `json:"utilities,omitempty"` // Expected utility of each alternative. Nil if the row can't occur.
+	Feasible  []bool    `json:"feasible,omitempty"`  // Whether each alternative is allowed. Nil if the decision is not constrained.
+	Margin    float64   `json:"margin"`              // Expected utility difference between the optimal action and the feasible runner-up.
+	Tie       bool      `json:"tie"`                 // Whether multiple actions are optimal.
+}
+
+// PolicyReports reports the optimal action and the expected utility of each alternative,
+// for every decision and every combination of outcomes of its informational parents.
+//
+// Decisions are in topological order.
+// For constrained decisions, only feasible alternatives are considered for the optimal action.
+// Policies must have been solved by [Network.SolvePolicies] before.
+// Later decisions follow their policies.
+func (n *Network) PolicyReports() ([]PolicyReport, error) {
+	topological, err := n.TopologicalOrder()
+	if err != nil {
+		return nil, err
+	}
+	reports := []PolicyReport{}
+	for _, name := range topological {
+		v, _ := n.variable(name)
+		if v.NodeType != ve.DecisionNode {
+			continue
+		}
+		parents, err := n.policyParents(name)
+		if err != nil {
+			return nil, err
+		}
+		report := PolicyReport{Decision: name, Alternatives: v.Outcomes, Parents: []string{}}
+		for _, p := range topological {
+			if parents[p] {
+				report.Parents = append(report.Parents, p)
+			}
+		}
+		if err := n.policyRows(&report); err != nil {
+			return nil, err
+		}
+		reports = append(reports, report)
+	}
+	return reports, nil
+}
+
+// policyRows fills the rows of a policy report.
+func (n *Network) policyRows(report *PolicyReport) error {
+	outcomes := make([]int, len(report.Parents))
+	rows := 1
+	for i, p := range report.Parents {
+		v, _ := n.variable(p)
+		outcomes[i] = len(v.Outcomes)
+		rows *= outcomes[i]
+	}
+
+	indices := make([]int, len(report.Parents))
+	for r := 0; r < rows; r++ {
+		evidence := make(map[string]string, len(report.Parents)+1)
+		row := PolicyRow{Outcomes: make([]string, len(report.Parents))}
+		for i, p := range report.Parents {
+			v, _ := n.variable(p)
+			row.Outcomes[i] = v.Outcomes[indices[i]]
+			evidence[p] = row.Outcomes[i]
+		}
+		if err := n.policyRow(report, evidence, &row); err != nil {
+			return err
+		}
+		report.Rows = append(report.Rows, row)
+		nextIndices(indices, outcomes)
+	}
+	return nil
+}
+
+// policyRow calculates the expected utility of each alternative for a row of a policy report.
+func (n *Network) policyRow(report *PolicyReport, evidence map[string]string, row *PolicyRow) error {
+	_, probs, err := n.SolveQuery(evidence, []string{}, true)
+	if err != nil {
+		return err
+	}
+	prob := probs.Data()[0]
+	if prob <= 0 {
+		return nil
+	}
+	row.Possible = true
+	row.Utilities = make([]float64, len(report.Alternatives))
+	for i, alt := range report.Alternatives {
+		evidence[report.Decision] = alt
+		utility, err := n.SolveUtility(evidence, []string{}, "", true)
+		if err != nil {
+			return err
+		}
+		row.Utilities[i] = utility.Data()[0] / prob
+	}
+	delete(evidence, report.Decision)
+	row.Feasible = n.allowedOutcomes(report.Decision, evidence)
+
+	best, second := bestAlternatives(row.Utilities, row.Feasible)
+	row.Action = report.Alternatives[best]
+	if second >= 0 {
+		row.Margin = row.Utilities[best] - row.Utilities[second]
+		row.Tie = math.Abs(row.Margin) <= optimalTolerance
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+package bbn_test
+
+import (
+	"testing"
+
+	"github.com/mlange-42/bbn"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNetworkPolicyReports(t *testing.T) {
+	net, err := bbn.FromFile("_examples/decision/oil.yml")
+	assert.Nil(t, err)
+
+	_, err = net.PolicyReports()
+	assert.ErrorIs(t, err, bbn.ErrNoPolicy)
+
+	_, err = net.SolvePolicies(true)
+	assert.Nil(t, err)
+
+	reports, err := net.PolicyReports()
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(reports))
+
+	test := reports[0]
+	assert.Equal(t, "Do test drill", test.Decision)
+	assert.Equal(t, []string{}, test.Parents)
+	assert.Equal(t, 1, len(test.Rows))
+	assert.Equal(t, "yes", test.Rows[0].Action)
+	assert.InDeltaSlice(t, []float64{22.5, 20}, test.Rows[0].Utilities, 1e-9)
+	assert.InDelta(t, 2.5, test.Rows[0].Margin, 1e-9)
+
+	drill := reports[1]
+	assert.Equal(t, "Do drill", drill.Decision)
+	assert.Equal(t, []string{"Do test drill", "Test result"}, drill.Parents)
+	assert.Equal(t, 6, len(drill.Rows))
+	assert.Equal(t, []string{"yes", "diffuse"}, drill.Rows[2].Outcomes)
+	assert.Equal(t, "no", drill.Rows[2].Action)
+	assert.InDelta(t, 77.5, drill.Rows[0].Utilities[0], 1e-9)
+	assert.InDelta(t, 87.5, drill.Rows[0].Margin, 1e-9)
+	for _, row := range drill.Rows {
+		assert.True(t, row.Possible)
+		assert.False(t, row.Tie)
+	}
+}
+
+func TestNetworkPolicyReportsTiesImpossible(t *testing.T) {
+	net, err := bbn.NewBuilder("Umbrella", "").
+		AddChance("Forecast", "sunny", "rainy", "snow").
+		AddDecision("Umbrella", "yes", "no").
+		AddUtility("Wet").
+		AddEdge("Forecast", "Umbrella").
+		AddEdge("Forecast", "Wet").
+		AddEdge("Umbrella", "Wet").
+		SetTable("Forecast", []float64{0.7, 0.3, 0}).
+		SetTable("Wet", []float64{0, 0, -5, -100, -5, -100}).
+		Build()
+	assert.Nil(t, err)
+	_, err = net.SolvePolicies(false)
+	assert.Nil(t, err)
+
+	reports, err := net.PolicyReports()
+	assert.Nil(t, err)
+	rows := reports[0].Rows
+	assert.Equal(t, 3, len(rows))
+
+	assert.True(t, rows[0].Tie)
+	assert.Equal(t, 0.0, rows[0].Margin)
+
+	assert.False(t, rows[1].Tie)
+	assert.Equal(t, "yes", rows[1].Action)
+	assert.InDelta(t, 95, rows[1].Margin, 1e-9)
+
+	assert.False(t, rows[2].Possible)
+	assert.Equal(t, "", rows[2].Action)
+	assert.Nil(t, rows[2].Utilities)
+}
@@ -0,0 +1,304 @@
+package bbn
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/mlange-42/bbn/ve"
+)
+
+// dominanceTolerance is the tolerance for comparing cumulative distributions in dominance checks.
+const dominanceTolerance = 1e-12
+
+// RiskProfile is the probability distribution of the weighted total utility,
+// before applying a utility function. See [Network.RiskProfile].
+type RiskProfile struct {
+	Utilities       []float64 // Distinct utility values, in increasing order.
+	Probabilities   []float64 // Probabilities of the utility values.
+	ExpectedUtility float64   // Expected total utility, with the utility function applied. Equals the mean without a utility function.
+}
+
+// Mean returns the expected weighted total utility.
+func (r *RiskProfile) Mean() float64 {
+	mean := 0.0
+	for i, u := range r.Utilities {
+		mean += r.Probabilities[i] * u
+	}
+	return mean
+}
+
+// Variance returns the variance of the utility.
+func (r *RiskProfile) Variance() float64 {
+	mean := r.Mean()
+	variance := 0.0
+	for i, u := range r.Utilities {
+		variance += r.Probabilities[i] * (u - mean) * (u - mean)
+	}
+	return variance
+}
+
+// Cumulative returns the cumulative risk profile, i.e. the probability P(U <= u) for each utility value.
+func (r *RiskProfile) Cumulative() []float64 {
+	result := make([]float64, len(r.Probabilities))
+	sum := 0.0
+	for i, p := range r.Probabilities {
+		sum += p
+		result[i] = sum
+	}
+	return result
+}
+
+// Quantile returns the smallest utility value u with P(U <= u) >= p.
+// For small p, this is the value at risk (VaR) at level p.
+func (r *RiskProfile) Quantile(p float64) float64 {
+	cumulative := r.Cumulative()
+	for i, c := range cumulative {
+		if c >= p-dominanceTolerance {
+			return r.Utilities[i]
+		}
+	}
+	return r.Utilities[len(r.Utilities)-1]
+}
+
+// FirstOrderDominates checks whether the profile stochastically dominates another profile in first order.
+// That is, its cumulative profile is never above the other's, and below it somewhere.
+func (r *RiskProfile) FirstOrderDominates(other *RiskProfile) bool {
+	points := r.support(other)
+	return dominates(r.cumulativeAt(points), other.cumulativeAt(points))
+}
+
+// SecondOrderDominates checks whether the profile stochastically dominates another profile in second order.
+// That is, the integral of its cumulative profile is never above the other's, and below it somewhere.
+// Any risk-averse decision maker prefers a second order dominant alternative.
+func (r *RiskProfile) SecondOrderDominates(other *RiskProfile) bool {
+	points := r.support(other)
+	return dominates(integrate(r.cumulativeAt(points), points), integrate(other.cumulativeAt(points), points))
+}
+
+// support returns the union of utility values of two profiles, in increasing order.
+func (r *RiskProfile) support(other *RiskProfile) []float64 {
+	points := append(slices.Clone(r.Utilities), other.Utilities...)
+	slices.Sort(points)
+	return slices.Compact(points)
+}
+
+// cumulativeAt evaluates the cumulative profile at the given points, in increasing order.
+func (r *RiskProfile) cumulativeAt(points []float64) []float64 {
+	result := make([]float64, len(points))
+	sum, j := 0.0, 0
+	for i, x := range points {
+		for j < len(r.Utilities) && r.Utilities[j] <= x {
+			sum += r.Probabilities[j]
+			j++
+		}
+		result[i] = sum
+	}
+	return result
+}
+
+// integrate integrates a step function with the given values at the given points.
+func integrate(values []float64, points []float64) []float64 {
+	result := make([]float64, len(values))
+	for i := 1; i < len(values); i++ {
+		result[i] = result[i-1] + values[i-1]*(points[i]-points[i-1])
+	}
+	return result
+}
+
+// dominates checks that a is nowhere above b, and below b somewhere.
+func dominates(a, b []float64) bool {
+	strict := false
+	for i := range a {
+		if a[i] > b[i]+dominanceTolerance {
+			return false
+		}
+		if a[i] < b[i]-dominanceTolerance {
+			strict = true
+		}
+	}
+	return strict
+}
+
+// RiskProfile calculates the probability distribution of the total utility, under evidence and interventions.
+//
+// If there is a total utility node, utility nodes are weighted according to its table.
+// The distribution, its quantiles and dominance checks are in units of the weighted sum.
+// A utility function of the total utility node is only applied for [RiskProfile.ExpectedUtility].
+// Policies from [Network.SolvePolicies] are used for decisions that are not intervened,
+// and all other decisions must have a policy.
+func (n *Network) RiskProfile(evidence map[string]string, do map[string]string) (*RiskProfile, error) {
+	utilities, weights, err := n.utilityVariables()
+	if err != nil {
+		return nil, err
+	}
+	if err := n.checkRiskDecisions(evidence, do); err != nil {
+		return nil, err
+	}
+
+	fixed := map[string]int{}
+	query := []string{}
+	for _, u := range utilities {
+		for _, p := range u.Factor.Given {
+			if err := n.riskParent(p, evidence, do, fixed, &query); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	joint, err := n.jointProbabilities(evidence, do, query)
+	if err != nil {
+		return nil, err
+	}
+
+	// query variables first, followed by fixed variables
+	names := slices.Clone(query)
+	indices := make([]int, len(query), len(query)+len(fixed))
+	outcomes := make([]int, len(query))
+	for i, q := range query {
+		v, _ := n.variable(q)
+		outcomes[i] = len(v.Outcomes)
+	}
+	for name, idx := range fixed {
+		names = append(names, name)
+		indices = append(indices, idx)
+	}
+
+	values := map[float64]float64{}
+	attributes := make([]float64, len(utilities))
+	expected := 0.0
+	for _, p := range joint {
+		if p > 0 {
+			for i, u := range utilities {
+				attributes[i] = attributeUtility(u, names, indices)
+			}
+			values[weightedTotal(attributes, weights)] += p
+			expected += p * n.combineUtilities(attributes, weights)
+		}
+		nextIndices(indices[:len(query)], outcomes)
+	}
+
+	result := RiskProfile{ExpectedUtility: expected}
+	for u := range values {
+		result.Utilities = append(result.Utilities, u)
+	}
+	slices.Sort(result.Utilities)
+	for _, u := range result.Utilities {
+		result.Probabilities = append(result.Probabilities, values[u])
+	}
+	return &result, nil
+}
+
+// DecisionRiskProfiles calculates a risk profile for each alternative of a decision.
+// See [Network.RiskProfile].
+//
+// Returns one risk profile per outcome of the decision variable.
+func (n *Network) DecisionRiskProfiles(evidence map[string]string, decision string) ([]RiskProfile, error) {
+	v, ok := n.variable(decision)
+	if !ok {
+		return nil, newVariableError(decision, ErrUnknownVariable, "decision variable %s not found", decision)
+	}
+	if v.NodeType != ve.DecisionNode {
+		return nil, newVariableError(decision, ErrUnsupported, "variable %s is not a decision variable", decision)
+	}
+	if _, ok := evidence[decision]; ok {
+		return nil, fmt.Errorf("decision variable %s can't be an evidence variable", decision)
+	}
+	result := make([]RiskProfile, len(v.Outcomes))
+	for i, o := range v.Outcomes {
+		profile, err := n.RiskProfile(evidence, map[string]string{decision: o})
+		if err != nil {
+			return nil, err
+		}
+		result[i] = *profile
+	}
+	return result, nil
+}
+
+// utilityVariables returns all utility variables except the total utility node, and their weights.
+func (n *Network) utilityVariables() ([]*Variable, []float64, error) {
+	utilities := []*Variable{}
+	for i := range n.variables {
+		v := &n.variables[i]
+		if v.NodeType == ve.UtilityNode && i != n.totalUtilityIndex {
+			utilities = append(utilities, v)
+		}
+	}
+	if len(utilities) == 0 {
+		return nil, nil, fmt.Errorf("network has no utility variables")
+	}
+	weights, err := n.attributeWeights()
+	if err != nil {
+		return nil, nil, err
+	}
+	return utilities, weights, nil
+}
+
+// checkRiskDecisions checks that all decisions are solved, observed or intervened.
+func (n *Network) checkRiskDecisions(evidence map[string]string, do map[string]string) error {
+	for _, v := range n.variables {
+		if v.NodeType != ve.DecisionNode {
+			continue
+		}
+		_, hasPolicy := n.policies[v.Name]
+		_, isEvidence := evidence[v.Name]
+		_, isDo := do[v.Name]
+		if !hasPolicy && !isEvidence && !isDo {
+			return newVariableError(v.Name, ErrNoPolicy, "decision %s has no policy; solve policies first", v.Name)
+		}
+	}
+	return nil
+}
+
+// riskParent registers a parent of a utility variable, either as fixed by evidence or interventions, or as query variable.
+func (n *Network) riskParent(name string, evidence map[string]string, do map[string]string, fixed map[string]int, query *[]string) error {
+	if _, ok := fixed[name]; ok || slices.Contains(*query, name) {
+		return nil
+	}
+	v, _ := n.variable(name)
+	if v.NodeType == ve.ContinuousNode {
+		return newVariableError(name, ErrUnsupported, "continuous utility parent %s is not supported", name)
+	}
+	value, ok := do[name]
+	if !ok {
+		value, ok = evidence[name]
+	}
+	if !ok {
+		*query = append(*query, name)
+		return nil
+	}
+	idx, ok := v.Outcome(value)
+	if !ok {
+		return newVariableError(name, ErrUnknownOutcome, "outcome %s for variable %s not found", value, name)
+	}
+	fixed[name] = idx
+	return nil
+}
+
+// jointProbabilities calculates the normalized joint distribution of the query variables.
+// The last variable varies fastest.
+func (n *Network) jointProbabilities(evidence map[string]string, do map[string]string, query []string) ([]float64, error) {
+	_, f, err := n.SolveIntervention(do, evidence, query, false)
+	if err != nil {
+		return nil, err
+	}
+	data := f.Data()
+	if len(query) > 0 {
+		rearranged, err := n.TryRearrange(f, query)
+		if err != nil {
+			return nil, err
+		}
+		data = rearranged.Data()
+	}
+	sum := 0.0
+	for _, p := range data {
+		sum += p
+	}
+	if sum == 0 {
+		return nil, fmt.Errorf("evidence has zero probability")
+	}
+	result := make([]float64, len(data))
+	for i, p := range data {
+		result[i] = p / sum
+	}
+	return result, nil
+}
@@ -0,0 +1,119 @@
+package bbn_test
+
+import (
+	"testing"
+
+	"github.com/mlange-42/bbn"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNetworkRiskProfile(t *testing.T) {
+	net, err := bbn.FromFile("_examples/decision/umbrella.yml")
+	assert.Nil(t, err)
+
+	_, err = net.RiskProfile(nil, nil)
+	assert.ErrorIs(t, err, bbn.ErrNoPolicy)
+
+	_, err = net.SolvePolicies(true)
+	assert.Nil(t, err)
+
+	profile, err := net.RiskProfile(nil, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, []float64{0, 20, 70, 100}, profile.Utilities)
+	assert.InDeltaSlice(t, []float64{0.12, 0.07, 0.18, 0.63}, profile.Probabilities, 1e-9)
+	assert.InDeltaSlice(t, []float64{0.12, 0.19, 0.37, 1}, profile.Cumulative(), 1e-9)
+	assert.InDelta(t, 77, profile.Mean(), 1e-9)
+	assert.InDelta(t, 0.07*400+0.18*4900+0.63*10000-77*77, profile.Variance(), 1e-6)
+	assert.Equal(t, 0.0, profile.Quantile(0.1))
+	assert.Equal(t, 20.0, profile.Quantile(0.15))
+	assert.Equal(t, 100.0, profile.Quantile(1))
+
+	utility, err := net.SolveUtility(map[string]string{}, []string{}, "", false)
+	assert.Nil(t, err)
+	assert.InDelta(t, utility.Data()[0], profile.Mean(), 1e-9)
+
+	profile, err = net.RiskProfile(map[string]string{"Forecast": "Sunny"}, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, []float64{0, 100}, profile.Utilities)
+	assert.InDelta(t, 0.045/0.535, profile.Probabilities[0], 1e-9)
+}
+
+func TestNetworkDecisionRiskProfiles(t *testing.T) {
+	net, err := bbn.FromFile("_examples/decision/umbrella.yml")
+	assert.Nil(t, err)
+	_, err = net.SolvePolicies(true)
+	assert.Nil(t, err)
+
+	profiles, err := net.DecisionRiskProfiles(nil, "Umbrella")
+	assert.Nil(t, err)
+	assert.Len(t, profiles, 2)
+
+	take, leave := profiles[0], profiles[1]
+	assert.Equal(t, []float64{20, 70}, take.Utilities)
+	assert.InDeltaSlice(t, []float64{0.7, 0.3}, take.Probabilities, 1e-9)
+	assert.Equal(t, []float64{0, 100}, leave.Utilities)
+	assert.InDeltaSlice(t, []float64{0.3, 0.7}, leave.Probabilities, 1e-9)
+
+	assert.InDelta(t, 35, take.Mean(), 1e-9)
+	assert.InDelta(t, 70, leave.Mean(), 1e-9)
+	assert.Less(t, take.Variance(), leave.Variance())
+
+	assert.False(t, take.FirstOrderDominates(&leave))
+	assert.False(t, leave.FirstOrderDominates(&take))
+	assert.False(t, leave.SecondOrderDominates(&take))
+
+	_, err = net.DecisionRiskProfiles(nil, "Weather")
+	assert.ErrorIs(t, err, bbn.ErrUnsupported)
+	_, err = net.DecisionRiskProfiles(nil, "Foo")
+	assert.ErrorIs(t, err, bbn.ErrUnknownVariable)
+	_, err = net.DecisionRiskProfiles(map[string]string{"Umbrella": "Take"}, "Umbrella")
+	assert.NotNil(t, err)
+
+	sprinkler, err := bbn.FromFile("_examples/bbn/sprinkler.yml")
+	assert.Nil(t, err)
+	_, err = sprinkler.RiskProfile(nil, nil)
+	assert.NotNil(t, err)
+}
+
+func TestNetworkRiskProfileTotalUtility(t *testing.T) {
+	net, err := bbn.NewBuilder("Umbrella", "").
+		AddChance("Rain", "yes", "no").
+		AddDecision("Umbrella", "yes", "no").
+		AddUtility("Wet").
+		AddUtility("Comfort").
+		AddUtility("Total", "Wet", "Comfort").
+		AddEdge("Rain", "Wet").
+		AddEdge("Umbrella", "Wet").
+		AddEdge("Umbrella", "Comfort").
+		AddEdge("Wet", "Total").
+		AddEdge("Comfort", "Total").
+		SetTable("Rain", []float64{0.3, 0.7}).
+		SetTable("Wet", []float64{0, -100, 0, 0}).
+		SetTable("Comfort", []float64{-5, 0}).
+		SetTable("Total", []float64{1, 2}).
+		Build()
+	assert.Nil(t, err)
+
+	profiles, err := net.DecisionRiskProfiles(nil, "Umbrella")
+	assert.Nil(t, err)
+	assert.Equal(t, []float64{-10}, profiles[0].Utilities)
+	assert.Equal(t, []float64{-100, 0}, profiles[1].Utilities)
+	assert.InDeltaSlice(t, []float64{0.3, 0.7}, profiles[1].Probabilities, 1e-9)
+}
+
+func TestRiskProfileDominance(t *testing.T) {
+	a := bbn.RiskProfile{Utilities: []float64{10, 20}, Probabilities: []float64{0.5, 0.5}}
+	b := bbn.RiskProfile{Utilities: []float64{0, 20}, Probabilities: []float64{0.5, 0.5}}
+	assert.True(t, a.FirstOrderDominates(&b))
+	assert.False(t, b.FirstOrderDominates(&a))
+	assert.True(t, a.SecondOrderDominates(&b))
+	assert.False(t, a.FirstOrderDominates(&a))
+
+	// Same mean, less spread.
+	c := bbn.RiskProfile{Utilities: []float64{15}, Probabilities: []float64{1}}
+	d := bbn.RiskProfile{Utilities: []float64{0, 30}, Probabilities: []float64{0.5, 0.5}}
+	assert.False(t, c.FirstOrderDominates(&d))
+	assert.False(t, d.FirstOrderDominates(&c))
+	assert.True(t, c.SecondOrderDominates(&d))
+	assert.False(t, d.SecondOrderDominates(&c))
+}
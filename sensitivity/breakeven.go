@@ -0,0 +1,101 @@
+package sensitivity
+
+import (
+	"slices"
+
+	"github.com/mlange-42/bbn"
+	"github.com/mlange-42/bbn/ve"
+)
+
+const (
+	breakEvenSteps     = 20   // Number of grid intervals to scan for policy changes.
+	breakEvenTolerance = 1e-6 // Tolerance for locating break-even values by bisection.
+)
+
+// BreakEven finds the values of a parameter where the optimal decisions from [bbn.Network.SolvePolicies] change.
+//
+// The parameter range [0, 1] is scanned on a regular grid, and each change is located by bisection.
+// Changes that occur twice within a grid interval may be missed.
+// Returns nil if the network has no decision variables.
+// The network itself is not modified.
+func BreakEven(net *bbn.Network, p Parameter) ([]float64, error) {
+	decisions := []string{}
+	for _, v := range net.Variables() {
+		if v.NodeType == ve.DecisionNode {
+			decisions = append(decisions, v.Name)
+		}
+	}
+	if len(decisions) == 0 {
+		return nil, nil
+	}
+	a, err := newAnalysis(net, Target{})
+	if err != nil {
+		return nil, err
+	}
+
+	result := []float64{}
+	prevX := 0.0
+	prev, err := a.decisions(p, prevX, decisions)
+	if err != nil {
+		return nil, err
+	}
+	for i := 1; i <= breakEvenSteps; i++ {
+		x := float64(i) / breakEvenSteps
+		curr, err := a.decisions(p, x, decisions)
+		if err != nil {
+			return nil, err
+		}
+		if !slices.Equal(prev, curr) {
+			b, err := a.bisect(p, prevX, x, prev, decisions)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, b)
+		}
+		prevX, prev = x, curr
+	}
+	return result, nil
+}
+
+// bisect locates a change of decisions between parameter values low and high.
+func (a *analysis) bisect(p Parameter, low, high float64, lowDecisions []int, decisions []string) (float64, error) {
+	for high-low > breakEvenTolerance {
+		mid := (low + high) / 2
+		curr, err := a.decisions(p, mid, decisions)
+		if err != nil {
+			return 0, err
+		}
+		if slices.Equal(curr, lowDecisions) {
+			low = mid
+		} else {
+			high = mid
+		}
+	}
+	return (low + high) / 2, nil
+}
+
+// decisions solves policies with the parameter set to the given value.
+// Returns the optimal outcome index of each decision, for each row of the policies.
+func (a *analysis) decisions(p Parameter, value float64, decisions []string) ([]int, error) {
+	var policies map[string]bbn.Factor
+	err := a.withParameter(p, value, func() (err error) {
+		policies, err = a.net.SolvePolicies(true)
+		return
+	})
+	if err != nil {
+		return nil, err
+	}
+	result := []int{}
+	for _, name := range decisions {
+		policy, ok := policies[name]
+		if !ok {
+			continue
+		}
+		cols := len(a.variable(name).Outcomes)
+		for row := 0; row < len(policy.Table)/cols; row++ {
+			values := policy.Table[row*cols : (row+1)*cols]
+			result = append(result, slices.Index(values, slices.Max(values)))
+		}
+	}
+	return result, nil
+}
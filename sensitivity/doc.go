@@ -0,0 +1,9 @@
+// Package sensitivity provides one-way sensitivity analysis of networks with respect to their table parameters.
+//
+// For a target, like the posterior probability of an outcome or the expected utility,
+// [Analyze] computes a sensitivity function for each probability parameter of chance variables.
+// When varying a parameter, the other parameters in the same table row are co-varied proportionally.
+// Under this scheme, the target is a ratio of two linear functions of the parameter (see [Function]).
+//
+// [BreakEven] finds the parameter values where the optimal decisions of a decision network change.
+package sensitivity
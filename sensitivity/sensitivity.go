@@ -0,0 +1,361 @@
+package sensitivity
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+
+	"github.com/mlange-42/bbn"
+	"github.com/mlange-42/bbn/ve"
+)
+
+// Target of a sensitivity analysis.
+type Target struct {
+	Variable string            // Query variable. Leave empty to target the total expected utility.
+	Outcome  string            // Outcome of the query variable.
+	Evidence map[string]string // Evidence to condition on, optional.
+}
+
+// String returns a description of the target, like "P(Rain=yes | Wet=yes)" or "EU".
+func (t Target) String() string {
+	evidence := make([]string, 0, len(t.Evidence))
+	for k, v := range t.Evidence {
+		evidence = append(evidence, k+"="+v)
+	}
+	slices.Sort(evidence)
+	cond := ""
+	if len(evidence) > 0 {
+		cond = " | " + strings.Join(evidence, ", ")
+	}
+	if t.Variable == "" {
+		if cond == "" {
+			return "EU"
+		}
+		return "EU(" + strings.TrimPrefix(cond, " | ") + ")"
+	}
+	return fmt.Sprintf("P(%s=%s%s)", t.Variable, t.Outcome, cond)
+}
+
+// Parameter identifies a single probability in the table of a chance variable.
+type Parameter struct {
+	Variable string   // The variable.
+	Row      int      // Row index in the table.
+	Column   int      // Column index in the table, i.e. the outcome index.
+	Outcome  string   // The outcome of the variable.
+	Given    []string // Names of the parent variables.
+	Parents  []string // Outcomes of the parent variables for the row.
+	Value    float64  // Original value of the parameter, in the normalized table.
+}
+
+// String returns a description of the parameter, like "P(Rain=yes | Cloudy=no)".
+func (p Parameter) String() string {
+	if len(p.Given) == 0 {
+		return fmt.Sprintf("P(%s=%s)", p.Variable, p.Outcome)
+	}
+	parents := make([]string, len(p.Given))
+	for i, g := range p.Given {
+		parents[i] = g + "=" + p.Parents[i]
+	}
+	return fmt.Sprintf("P(%s=%s | %s)", p.Variable, p.Outcome, strings.Join(parents, ", "))
+}
+
+// Function is a one-way sensitivity function f(x) = (A + B*x) / (C + D*x) of a parameter x.
+type Function struct {
+	A, B, C, D float64
+}
+
+// Eval evaluates the function for the given parameter value.
+// Returns NaN where the denominator is zero, e.g. if evidence becomes impossible.
+func (f Function) Eval(x float64) float64 {
+	den := f.C + f.D*x
+	if den == 0 {
+		return math.NaN()
+	}
+	return (f.A + f.B*x) / den
+}
+
+// Derivative of the function for the given parameter value.
+func (f Function) Derivative(x float64) float64 {
+	den := f.C + f.D*x
+	if den == 0 {
+		return math.NaN()
+	}
+	return (f.B*f.C - f.A*f.D) / (den * den)
+}
+
+// Result of the sensitivity analysis for a single parameter.
+type Result struct {
+	Parameter  Parameter // The parameter.
+	Function   Function  // Sensitivity function of the target.
+	Low        float64   // Lower bound of the parameter range.
+	High       float64   // Upper bound of the parameter range.
+	TargetLow  float64   // Target value at the lower bound of the parameter range.
+	TargetHigh float64   // Target value at the upper bound of the parameter range.
+	Derivative float64   // Derivative of the target at the original parameter value.
+}
+
+// Impact of the parameter on the target, as the absolute difference of the target between the range bounds.
+// Returns 0 if the target is undefined at a bound.
+func (r *Result) Impact() float64 {
+	impact := math.Abs(r.TargetHigh - r.TargetLow)
+	if math.IsNaN(impact) {
+		return 0
+	}
+	return impact
+}
+
+// Analyze computes sensitivity functions for all probability parameters of chance variables in the network.
+// For variables with two outcomes, only the parameters of the first outcome are analyzed,
+// as the others are their complements.
+//
+// Parameters are varied in the range [value - spread, value + spread], clamped to [0, 1].
+// Use a spread of 1 to use the full range.
+// Results are sorted by decreasing impact on the target.
+//
+// For decision networks, policies are solved once and kept fixed.
+// Use [BreakEven] to find parameter values where the optimal policies change.
+// The network itself is not modified.
+func Analyze(net *bbn.Network, target Target, spread float64) (value float64, results []Result, err error) {
+	a, err := newAnalysis(net, target)
+	if err != nil {
+		return 0, nil, err
+	}
+	if spread <= 0 {
+		return 0, nil, fmt.Errorf("spread must be positive; got %f", spread)
+	}
+	num, den, err := a.evaluate()
+	if err != nil {
+		return 0, nil, err
+	}
+	if den == 0 {
+		return 0, nil, fmt.Errorf("evidence has zero probability")
+	}
+	value = num / den
+
+	for _, p := range parameters(a.net) {
+		f, err := a.function(p)
+		if err != nil {
+			return 0, nil, err
+		}
+		low, high := math.Max(0, p.Value-spread), math.Min(1, p.Value+spread)
+		results = append(results, Result{
+			Parameter:  p,
+			Function:   f,
+			Low:        low,
+			High:       high,
+			TargetLow:  f.Eval(low),
+			TargetHigh: f.Eval(high),
+			Derivative: f.Derivative(p.Value),
+		})
+	}
+	slices.SortStableFunc(results, func(a, b Result) int {
+		return cmpDesc(a.Impact(), b.Impact())
+	})
+	return value, results, nil
+}
+
+func cmpDesc(a, b float64) int {
+	if a > b {
+		return -1
+	}
+	if a < b {
+		return 1
+	}
+	return 0
+}
+
+// analysis holds a private copy of a network, whose tables are modified in place.
+type analysis struct {
+	net    *bbn.Network
+	target Target
+}
+
+// newAnalysis creates a copy of the network, with full tables instead of canonical models,
+// and solves policies if there are decision variables.
+func newAnalysis(net *bbn.Network, target Target) (*analysis, error) {
+	variables := slices.Clone(net.Variables())
+	factors := []bbn.Factor{}
+	hasDecisions, hasUtility := false, false
+	for i := range variables {
+		v := &variables[i]
+		hasDecisions = hasDecisions || v.NodeType == ve.DecisionNode
+		hasUtility = hasUtility || v.NodeType == ve.UtilityNode
+		if v.Factor != nil {
+			f := *v.Factor
+			f.Given, f.Table, f.Allowed = slices.Clone(f.Given), slices.Clone(f.Table), slices.Clone(f.Allowed)
+			f.Noisy = nil
+			factors = append(factors, f)
+		}
+		v.Factor = nil
+	}
+	copied, err := bbn.New(net.Name(), net.Info(), variables, factors)
+	if err != nil {
+		return nil, err
+	}
+	copied.SetLIMID(net.LIMID())
+
+	if target.Variable == "" && !hasUtility {
+		return nil, fmt.Errorf("expected utility target requires utility variables")
+	}
+	if target.Variable != "" {
+		idx := slices.IndexFunc(variables, func(v bbn.Variable) bool { return v.Name == target.Variable })
+		if idx < 0 {
+			return nil, fmt.Errorf("%w: target variable %s not found", bbn.ErrUnknownVariable, target.Variable)
+		}
+		if !slices.Contains(variables[idx].Outcomes, target.Outcome) {
+			return nil, fmt.Errorf("%w: outcome %s of target variable %s not found", bbn.ErrUnknownOutcome, target.Outcome, target.Variable)
+		}
+		if _, ok := target.Evidence[target.Variable]; ok {
+			return nil, fmt.Errorf("target variable %s can't be an evidence variable", target.Variable)
+		}
+	}
+
+	if hasDecisions {
+		if _, err := copied.SolvePolicies(true); err != nil {
+			return nil, err
+		}
+	}
+	return &analysis{net: copied, target: target}, nil
+}
+
+// evaluate returns the unnormalized target value and the probability of the evidence.
+func (a *analysis) evaluate() (float64, float64, error) {
+	if a.target.Variable == "" {
+		_, probs, err := a.net.SolveQuery(a.target.Evidence, []string{}, false)
+		if err != nil {
+			return 0, 0, err
+		}
+		utility, err := a.net.SolveUtility(a.target.Evidence, []string{}, "", false)
+		if err != nil {
+			return 0, 0, err
+		}
+		return utility.Data()[0], probs.Data()[0], nil
+	}
+	_, f, err := a.net.SolveQuery(a.target.Evidence, []string{a.target.Variable}, false)
+	if err != nil {
+		return 0, 0, err
+	}
+	idx := slices.Index(a.variable(a.target.Variable).Outcomes, a.target.Outcome)
+	den := 0.0
+	for _, v := range f.Data() {
+		den += v
+	}
+	return f.Data()[idx], den, nil
+}
+
+// function derives the sensitivity function of the target for a parameter,
+// by evaluating the target at parameter values 0 and 1.
+func (a *analysis) function(p Parameter) (Function, error) {
+	var num0, den0, num1, den1 float64
+	err := a.withParameter(p, 0, func() (err error) {
+		num0, den0, err = a.evaluate()
+		return
+	})
+	if err != nil {
+		return Function{}, err
+	}
+	err = a.withParameter(p, 1, func() (err error) {
+		num1, den1, err = a.evaluate()
+		return
+	})
+	if err != nil {
+		return Function{}, err
+	}
+	return Function{A: num0, B: num1 - num0, C: den0, D: den1 - den0}, nil
+}
+
+// withParameter sets a parameter to the given value, with the remaining row co-varied proportionally,
+// and calls fn. The original row is restored afterwards.
+func (a *analysis) withParameter(p Parameter, value float64, fn func() error) error {
+	v := a.variable(p.Variable)
+	cols := len(v.Outcomes)
+	row := v.Factor.Table[p.Row*cols : (p.Row+1)*cols]
+	original := slices.Clone(row)
+	copy(row, covary(normalized(original), p.Column, value))
+	err := fn()
+	copy(row, original)
+	return err
+}
+
+func (a *analysis) variable(name string) *bbn.Variable {
+	vars := a.net.Variables()
+	return &vars[slices.IndexFunc(vars, func(v bbn.Variable) bool { return v.Name == name })]
+}
+
+// parameters lists all probability parameters of chance variables.
+func parameters(net *bbn.Network) []Parameter {
+	vars := net.Variables()
+	result := []Parameter{}
+	for _, v := range vars {
+		if v.NodeType != ve.ChanceNode || v.Factor == nil || len(v.Outcomes) < 2 {
+			continue
+		}
+		cols := len(v.Outcomes)
+		for row := 0; row < len(v.Factor.Table)/cols; row++ {
+			values := normalized(v.Factor.Table[row*cols : (row+1)*cols])
+			parents := parentOutcomes(vars, v.Factor.Given, row)
+			for col, o := range v.Outcomes {
+				if cols == 2 && col == 1 {
+					// the complement of the first parameter
+					break
+				}
+				result = append(result, Parameter{
+					Variable: v.Name,
+					Row:      row,
+					Column:   col,
+					Outcome:  o,
+					Given:    v.Factor.Given,
+					Parents:  parents,
+					Value:    values[col],
+				})
+			}
+		}
+	}
+	return result
+}
+
+// parentOutcomes returns the outcomes of the parents for a table row. The last parent varies fastest.
+func parentOutcomes(vars []bbn.Variable, given []string, row int) []string {
+	result := make([]string, len(given))
+	for i := len(given) - 1; i >= 0; i-- {
+		outcomes := vars[slices.IndexFunc(vars, func(v bbn.Variable) bool { return v.Name == given[i] })].Outcomes
+		result[i] = outcomes[row%len(outcomes)]
+		row /= len(outcomes)
+	}
+	return result
+}
+
+// covary sets a column of a normalized row to the given value,
+// and scales the other columns proportionally to keep the row normalized.
+// If the original value is 1, the remainder is distributed equally.
+func covary(row []float64, col int, value float64) []float64 {
+	result := make([]float64, len(row))
+	for j := range row {
+		switch {
+		case j == col:
+			result[j] = value
+		case row[col] < 1:
+			result[j] = row[j] * (1 - value) / (1 - row[col])
+		default:
+			result[j] = (1 - value) / float64(len(row)-1)
+		}
+	}
+	return result
+}
+
+func normalized(row []float64) []float64 {
+	sum := 0.0
+	for _, v := range row {
+		sum += v
+	}
+	result := make([]float64, len(row))
+	for i, v := range row {
+		if sum > 0 {
+			result[i] = v / sum
+		} else {
+			result[i] = 1 / float64(len(row))
+		}
+	}
+	return result
+}
@@ -0,0 +1,185 @@
+package sensitivity_test
+
+import (
+	"math"
+	"slices"
+	"testing"
+
+	"github.com/mlange-42/bbn"
+	"github.com/mlange-42/bbn/sensitivity"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFunction(t *testing.T) {
+	f := sensitivity.Function{A: 1, B: 2, C: 2, D: -1}
+
+	assert.InDelta(t, 0.5, f.Eval(0), 1e-12)
+	assert.InDelta(t, 3.0, f.Eval(1), 1e-12)
+	assert.True(t, math.IsNaN(f.Eval(2)))
+
+	// (B*C - A*D) / (C + D*x)^2
+	assert.InDelta(t, 5.0/4, f.Derivative(0), 1e-12)
+	assert.InDelta(t, 5.0, f.Derivative(1), 1e-12)
+}
+
+func TestAnalyze(t *testing.T) {
+	net, err := bbn.FromFile("../_examples/bbn/sprinkler.yml")
+	assert.Nil(t, err)
+
+	target := sensitivity.Target{Variable: "Rain", Outcome: "yes", Evidence: map[string]string{"GrassWet": "yes"}}
+	assert.Equal(t, "P(Rain=yes | GrassWet=yes)", target.String())
+
+	value, results, err := sensitivity.Analyze(net, target, 1)
+	assert.Nil(t, err)
+	assert.InDelta(t, 0.5269, value, 0.0001)
+	assert.Equal(t, 1+2+4, len(results))
+
+	for i := 1; i < len(results); i++ {
+		assert.GreaterOrEqual(t, results[i-1].Impact(), results[i].Impact())
+	}
+	for _, r := range results {
+		assert.InDelta(t, value, r.Function.Eval(r.Parameter.Value), 1e-9)
+		assert.Equal(t, 0.0, r.Low)
+		assert.Equal(t, 1.0, r.High)
+	}
+
+	idx := slices.IndexFunc(results, func(r sensitivity.Result) bool {
+		return r.Parameter.Variable == "Sprinkler" && r.Parameter.Row == 1 && r.Parameter.Column == 0
+	})
+	assert.GreaterOrEqual(t, idx, 0)
+	r := results[idx]
+	assert.Equal(t, "P(Sprinkler=yes | Rain=no)", r.Parameter.String())
+	assert.InDelta(t, 0.2, r.Parameter.Value, 1e-12)
+
+	for _, x := range []float64{0.05, 0.5, 0.9} {
+		expected := solveModified(t, net, "Sprinkler", []float64{0.01, 0.99, x, 1 - x}, target)
+		assert.InDelta(t, expected, r.Function.Eval(x), 1e-9)
+	}
+	assert.Less(t, r.Derivative, 0.0)
+
+	// The network is not modified.
+	_, _, err = net.SolveQuery(target.Evidence, []string{"Rain"}, false)
+	assert.Nil(t, err)
+	assert.Equal(t, []float64{0.2, 0.8}, net.Variables()[1].Factor.Table[2:])
+}
+
+func TestAnalyzeSpread(t *testing.T) {
+	net, err := bbn.FromFile("../_examples/bbn/sprinkler.yml")
+	assert.Nil(t, err)
+
+	_, results, err := sensitivity.Analyze(net, sensitivity.Target{Variable: "GrassWet", Outcome: "yes"}, 0.1)
+	assert.Nil(t, err)
+	for _, r := range results {
+		assert.InDelta(t, math.Max(0, r.Parameter.Value-0.1), r.Low, 1e-12)
+		assert.InDelta(t, math.Min(1, r.Parameter.Value+0.1), r.High, 1e-12)
+		assert.InDelta(t, r.Function.Eval(r.Low), r.TargetLow, 1e-12)
+		assert.InDelta(t, r.Function.Eval(r.High), r.TargetHigh, 1e-12)
+	}
+
+	_, _, err = sensitivity.Analyze(net, sensitivity.Target{Variable: "GrassWet", Outcome: "yes"}, 0)
+	assert.NotNil(t, err)
+}
+
+func TestAnalyzeUtility(t *testing.T) {
+	net, err := bbn.FromFile("../_examples/decision/umbrella.yml")
+	assert.Nil(t, err)
+
+	target := sensitivity.Target{}
+	assert.Equal(t, "EU", target.String())
+
+	value, results, err := sensitivity.Analyze(net, target, 1)
+	assert.Nil(t, err)
+	assert.Greater(t, value, 0.0)
+
+	for _, r := range results {
+		assert.InDelta(t, value, r.Function.Eval(r.Parameter.Value), 1e-9)
+	}
+	assert.Greater(t, results[0].Impact(), 0.0)
+}
+
+func TestAnalyzeUtilityFunction(t *testing.T) {
+	net, err := bbn.FromFile("../_examples/decision/investment.yml")
+	assert.Nil(t, err)
+
+	// risk-averse: bonds with a certain return of 40
+	value, results, err := sensitivity.Analyze(net, sensitivity.Target{}, 1)
+	assert.Nil(t, err)
+	assert.InDelta(t, 100*(1-math.Exp(-0.4)), value, 1e-9)
+
+	values, err := sensitivity.BreakEven(net, results[0].Parameter)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(values))
+	assert.InDelta(t, 0.709, values[0], 1e-3)
+}
+
+func TestAnalyzeErrors(t *testing.T) {
+	net, err := bbn.FromFile("../_examples/bbn/sprinkler.yml")
+	assert.Nil(t, err)
+
+	_, _, err = sensitivity.Analyze(net, sensitivity.Target{Variable: "Foo", Outcome: "yes"}, 1)
+	assert.ErrorIs(t, err, bbn.ErrUnknownVariable)
+
+	_, _, err = sensitivity.Analyze(net, sensitivity.Target{Variable: "Rain", Outcome: "maybe"}, 1)
+	assert.ErrorIs(t, err, bbn.ErrUnknownOutcome)
+
+	_, _, err = sensitivity.Analyze(net, sensitivity.Target{Variable: "Rain", Outcome: "yes", Evidence: map[string]string{"Rain": "no"}}, 1)
+	assert.NotNil(t, err)
+
+	_, _, err = sensitivity.Analyze(net, sensitivity.Target{}, 1)
+	assert.NotNil(t, err)
+}
+
+func TestBreakEven(t *testing.T) {
+	net, err := bbn.FromFile("../_examples/decision/umbrella.yml")
+	assert.Nil(t, err)
+
+	_, results, err := sensitivity.Analyze(net, sensitivity.Target{}, 1)
+	assert.Nil(t, err)
+	idx := slices.IndexFunc(results, func(r sensitivity.Result) bool {
+		return r.Parameter.Variable == "Weather" && r.Parameter.Column == 0
+	})
+	assert.GreaterOrEqual(t, idx, 0)
+
+	values, err := sensitivity.BreakEven(net, results[idx].Parameter)
+	assert.Nil(t, err)
+
+	// Take the umbrella if P(Rainy|forecast) / P(Sunny|forecast) > 80 / 70.
+	ratio := 80.0 / 70.0
+	expected := []float64{}
+	for _, likelihood := range []float64{15.0 / 70, 60.0 / 10, 25.0 / 20} {
+		expected = append(expected, 1/(1+ratio/likelihood))
+	}
+	slices.Sort(expected)
+	assert.Equal(t, len(expected), len(values))
+	for i := range expected {
+		assert.InDelta(t, expected[i], values[i], 1e-5)
+	}
+
+	sprinkler, err := bbn.FromFile("../_examples/bbn/sprinkler.yml")
+	assert.Nil(t, err)
+	values, err = sensitivity.BreakEven(sprinkler, results[idx].Parameter)
+	assert.Nil(t, err)
+	assert.Nil(t, values)
+}
+
+// solveModified solves the target for a copy of the network with a replaced table.
+func solveModified(t *testing.T, net *bbn.Network, name string, table []float64, target sensitivity.Target) float64 {
+	variables := slices.Clone(net.Variables())
+	factors := []bbn.Factor{}
+	for i := range variables {
+		v := &variables[i]
+		f := *v.Factor
+		if v.Name == name {
+			f.Table = table
+		}
+		factors = append(factors, f)
+		v.Factor = nil
+	}
+	modified, err := bbn.New(net.Name(), net.Info(), variables, factors)
+	assert.Nil(t, err)
+
+	result, _, err := modified.SolveQuery(target.Evidence, []string{target.Variable}, false)
+	assert.Nil(t, err)
+	idx := slices.Index(net.Variables()[0].Outcomes, target.Outcome)
+	return result[target.Variable][idx]
+}
@@ -18,7 +18,20 @@ type Trainer struct {
 }
 
 // NewTrainer creates a new [Trainer] for the given [Network].
+//
+// Panics if the network can't be trained. See [TryNewTrainer] for an error-returning variant.
 func NewTrainer(net *Network) Trainer {
+	t, err := TryNewTrainer(net)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+// TryNewTrainer creates a new [Trainer] for the given [Network].
+//
+// Returns an error if a variable has no factor or is continuous, or if a parent variable is not found.
+func TryNewTrainer(net *Network) (Trainer, error) {
 	nodes := net.Variables()
 
 	data := make([][][]float64, len(nodes))
@@ -28,6 +41,12 @@ func NewTrainer(net *Network) Trainer {
 	nodeIndices := make(map[string]int, len(nodes))
 	maxColumns := 0
 	for i, node := range nodes {
+		if node.Factor == nil {
+			return Trainer{}, newVariableError(node.Name, ErrTableShape, "no factor for node %s", node.Name)
+		}
+		if node.NodeType == ve.ContinuousNode {
+			return Trainer{}, fmt.Errorf("training is not supported for continuous variable %s", node.Name)
+		}
 		nodeIndices[node.Name] = i
 		if len(node.Factor.Given) > maxColumns {
 			maxColumns = len(node.Factor.Given)
@@ -49,7 +68,7 @@ func NewTrainer(net *Network) Trainer {
 			var ok bool
 			idx[i], ok = nodeIndices[n]
 			if !ok {
-				panic(fmt.Sprintf("parent node %s for %s not found", n, node.Name))
+				return Trainer{}, newVariableError(n, ErrUnknownVariable, "parent node %s for %s not found", n, node.Name)
 			}
 		}
 		indices[i] = idx
@@ -62,14 +81,30 @@ func NewTrainer(net *Network) Trainer {
 		indices: indices,
 		sample:  make([]int, 0, maxColumns),
 		utility: make([]float64, 0, maxColumns),
-	}
+	}, nil
 }
 
 // AddSample adds a training sample.
 // Order of values in the sample is the same as the order in which nodes were passed into the [Network] constructor.
+//
+// Panics if the sample does not match the network. See [Trainer.TryAddSample] for an error-returning variant.
 func (t *Trainer) AddSample(sample []int, utility []float64) {
-	nodes := t.network.Variables()
+	if err := t.TryAddSample(sample, utility); err != nil {
+		panic(err)
+	}
+}
+
+// TryAddSample adds a training sample.
+// Order of values in the sample is the same as the order in which nodes were passed into the [Network] constructor.
+//
+// Returns an error wrapping [ErrTableShape] if sample or utility don't match the number of variables,
+// and an error wrapping [ErrUnknownOutcome] if a sample value is out of range.
+func (t *Trainer) TryAddSample(sample []int, utility []float64) error {
+	if err := t.checkSample(sample, utility); err != nil {
+		return err
+	}
 
+	nodes := t.network.Variables()
 	for i, node := range nodes {
 		if node.NodeType == ve.DecisionNode {
 			continue
@@ -87,12 +122,18 @@ func (t *Trainer) AddSample(sample []int, utility []float64) {
 			}
 		}
 
-		idx, ok := node.Factor.rowIndex(t.sample)
+		idx, ok, err := node.Factor.rowIndex(t.sample)
+		if err != nil {
+			return err
+		}
 		if !ok {
 			continue
 		}
 
 		if node.NodeType == ve.UtilityNode {
+			if utility == nil {
+				return newVariableError(node.Name, ErrTableShape, "no utility values given for utility node %s", node.Name)
+			}
 			u := utility[i]
 			if math.IsNaN(u) {
 				continue
@@ -109,6 +150,24 @@ func (t *Trainer) AddSample(sample []int, utility []float64) {
 
 		t.counter[i][idx]++
 	}
+	return nil
+}
+
+// checkSample checks that a sample and its utility values match the network's variables.
+func (t *Trainer) checkSample(sample []int, utility []float64) error {
+	nodes := t.network.Variables()
+	if len(sample) != len(nodes) {
+		return fmt.Errorf("%w: sample has %d values, but network has %d variables", ErrTableShape, len(sample), len(nodes))
+	}
+	if utility != nil && len(utility) != len(nodes) {
+		return fmt.Errorf("%w: utility sample has %d values, but network has %d variables", ErrTableShape, len(utility), len(nodes))
+	}
+	for i, s := range sample {
+		if s >= len(nodes[i].Outcomes) {
+			return newVariableError(nodes[i].Name, ErrUnknownOutcome, "sample value %d out of range for node %s", s, nodes[i].Name)
+		}
+	}
+	return nil
 }
 
 // UpdateNetwork applies the training to the network, and returns a pointer to the original network.
@@ -129,6 +188,8 @@ func (t *Trainer) UpdateNetwork() (*Network, error) {
 				node.Factor.Table[j*cols+k] = float64(data[j][k]) / float64(cnt)
 			}
 		}
+		// trained table no longer follows a canonical model
+		node.Factor.Noisy = nil
 	}
 
 	return t.network, nil
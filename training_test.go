@@ -65,6 +65,13 @@ func TestTrainer(t *testing.T) {
 		trainer.AddSample(row, nil)
 	}
 
+	err = trainer.TryAddSample([]int{0, 0}, nil)
+	assert.ErrorIs(t, err, bbn.ErrTableShape)
+
+	err = trainer.TryAddSample([]int{0, 0, 2}, nil)
+	assert.ErrorIs(t, err, bbn.ErrUnknownOutcome)
+	assert.Panics(t, func() { trainer.AddSample([]int{0, 0}, nil) })
+
 	net, err = trainer.UpdateNetwork()
 	assert.Nil(t, err)
 
@@ -112,7 +119,6 @@ func TestTrainerDecision(t *testing.T) {
 	net, err := bbn.New("umbrella", "", vars, factors)
 	assert.Nil(t, err)
 	trainer := bbn.NewTrainer(net)
-	_ = trainer
 
 	samples := [][]int{
 		{0, 2, 0, 0},
@@ -0,0 +1,144 @@
+package bbn
+
+import (
+	"slices"
+
+	"github.com/mlange-42/bbn/ve"
+)
+
+// hasUtilityFunction checks whether the network has a total utility node with a utility function.
+func (n *Network) hasUtilityFunction() bool {
+	return n.totalUtilityIndex >= 0 && n.variables[n.totalUtilityIndex].Factor.Utility != nil
+}
+
+// checkUtilityFunction checks that only the total utility node has a utility function, and validates it.
+func (n *Network) checkUtilityFunction() error {
+	for i := range n.variables {
+		v := &n.variables[i]
+		if v.Factor == nil || v.Factor.Utility == nil {
+			continue
+		}
+		if i != n.totalUtilityIndex {
+			return newVariableError(v.Name, ErrUnsupported, "utility functions are only supported for total utility variables; got %s", v.Name)
+		}
+		if err := v.Factor.Utility.Validate(); err != nil {
+			return causedVariableError(v.Name, ErrDefinition, err, "utility function for %s: %s", v.Name, err.Error())
+		}
+	}
+	return nil
+}
+
+// CertaintyEquivalent returns the certainty equivalent for an expected total utility,
+// i.e. the weighted sum of utilities that is valued equally to the uncertain prospect.
+//
+// Without a utility function, the certainty equivalent is the expected utility.
+// Returns false if the utility function is not invertible.
+func (n *Network) CertaintyEquivalent(expected float64) (float64, bool) {
+	if !n.hasUtilityFunction() {
+		return expected, true
+	}
+	return n.variables[n.totalUtilityIndex].Factor.Utility.Inverse(expected)
+}
+
+// combineUtilities combines utilities of utility nodes to a total utility.
+// Weights may be nil for an unweighted sum.
+func (n *Network) combineUtilities(utilities []float64, weights []float64) float64 {
+	if n.hasUtilityFunction() {
+		return n.variables[n.totalUtilityIndex].Factor.Utility.Total(utilities, weights)
+	}
+	return weightedTotal(utilities, weights)
+}
+
+// weightedTotal combines utilities of utility nodes to their weighted sum, without a utility function.
+// Weights may be nil for an unweighted sum.
+func weightedTotal(utilities []float64, weights []float64) float64 {
+	total := 0.0
+	for i, u := range utilities {
+		if weights != nil {
+			u *= weights[i]
+		}
+		total += u
+	}
+	return total
+}
+
+// totalUtilityFactor creates a factor for the combined utility of a utility function,
+// over the union of the parents of all utility nodes.
+// Returns nil if there is no utility function.
+func (n *Network) totalUtilityFactor(vars *ve.Variables, varNames map[string]*variable) (*ve.Factor, error) {
+	if !n.hasUtilityFunction() {
+		return nil, nil
+	}
+	weights, err := n.attributeWeights()
+	if err != nil {
+		return nil, err
+	}
+
+	utilities := []*Variable{}
+	parents := []string{}
+	for i := range n.variables {
+		v := &n.variables[i]
+		if v.NodeType != ve.UtilityNode || i == n.totalUtilityIndex {
+			continue
+		}
+		utilities = append(utilities, v)
+		for _, p := range v.Factor.Given {
+			if !slices.Contains(parents, p) {
+				parents = append(parents, p)
+			}
+		}
+	}
+
+	variables := make([]ve.Variable, 0, len(parents)+1)
+	outcomes := make([]int, len(parents))
+	size := 1
+	for i, p := range parents {
+		vv, ok := varNames[p]
+		if !ok {
+			return nil, newVariableError(p, ErrUnsupported, "utility parent %s must be discrete for utility functions", p)
+		}
+		variables = append(variables, vv.VeVariable)
+		outcomes[i] = len(vv.Variable.Outcomes)
+		size *= outcomes[i]
+	}
+	variables = append(variables, varNames[n.variables[n.totalUtilityIndex].Name].VeVariable)
+
+	table := make([]float64, size)
+	indices := make([]int, len(parents))
+	values := make([]float64, len(utilities))
+	for row := range table {
+		for j, u := range utilities {
+			values[j] = attributeUtility(u, parents, indices)
+		}
+		table[row] = n.combineUtilities(values, weights)
+		nextIndices(indices, outcomes)
+	}
+
+	factor, err := vars.TryCreateFactor(variables, table)
+	if err != nil {
+		return nil, err
+	}
+	return &factor, nil
+}
+
+// attributeUtility returns the utility of a utility node,
+// for the given outcome indices of the variables in names.
+func attributeUtility(u *Variable, names []string, indices []int) float64 {
+	parents := make([]int, len(u.Factor.Given))
+	for i, p := range u.Factor.Given {
+		parents[i] = indices[slices.Index(names, p)]
+	}
+	row, _, _ := u.Factor.rowIndex(parents)
+	return u.Factor.Table[row]
+}
+
+// nextIndices advances outcome indices, with the last index varying fastest.
+func nextIndices(indices []int, outcomes []int) {
+	for i := len(indices) - 1; i >= 0; i-- {
+		indices[i]++
+		if indices[i] < outcomes[i] {
+			return
+		}
+		indices[i] = 0
+	}
+}
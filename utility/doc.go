@@ -0,0 +1,7 @@
+// Package utility provides non-linear functions for combining utilities of multiple attributes
+// into a total utility, like multiplicative utility and exponential utility for risk attitudes.
+//
+// Functions receive the utilities of the attributes, i.e. of utility variables,
+// and their weights from the table of the total utility variable.
+// By default, without a function, the total utility is the weighted sum of attribute utilities.
+package utility
@@ -0,0 +1,154 @@
+package utility
+
+import (
+	"fmt"
+	"math"
+	"slices"
+)
+
+// Function combines the utilities of attributes into a total utility.
+type Function interface {
+	// Total returns the total utility for the given attribute utilities and weights.
+	Total(utilities []float64, weights []float64) float64
+	// Inverse returns the weighted sum of attribute utilities that results in the given total utility.
+	// It is used for certainty equivalents. Returns false if the function is not invertible.
+	Inverse(total float64) (float64, bool)
+	// Validate checks the parameters of the function.
+	Validate() error
+}
+
+// weightedSum returns the weighted sum of attribute utilities.
+func weightedSum(utilities []float64, weights []float64) float64 {
+	sum := 0.0
+	for i, u := range utilities {
+		sum += weights[i] * u
+	}
+	return sum
+}
+
+// Multiplicative is a multiplicative multi-attribute utility function:
+//
+//	1 + K*U = (1 + K*w1*u1) * (1 + K*w2*u2) * ...
+//
+// where K is the scaling constant, wi are weights and ui are attribute utilities, typically in [0, 1].
+// For K > 0, attributes are complements, for -1 < K < 0 they are substitutes.
+type Multiplicative struct {
+	Scaling float64 // Scaling constant K, must be greater than -1 and not 0.
+}
+
+// Total implements [Function].
+func (f *Multiplicative) Total(utilities []float64, weights []float64) float64 {
+	product := 1.0
+	for i, u := range utilities {
+		product *= 1 + f.Scaling*weights[i]*u
+	}
+	return (product - 1) / f.Scaling
+}
+
+// Inverse implements [Function]. A multiplicative function is not invertible to a weighted sum.
+func (f *Multiplicative) Inverse(total float64) (float64, bool) {
+	return 0, false
+}
+
+// Validate implements [Function].
+func (f *Multiplicative) Validate() error {
+	if f.Scaling <= -1 || f.Scaling == 0 {
+		return fmt.Errorf("scaling constant of multiplicative utility must be greater than -1 and not 0; got %f", f.Scaling)
+	}
+	return nil
+}
+
+// Exponential is an exponential utility function of the weighted sum x of attribute utilities:
+//
+//	U = R * (1 - exp(-x/R))
+//
+// where R is the risk tolerance. The decision maker is risk-averse for R > 0, and risk-seeking for R < 0.
+// For large |R|, the function approaches risk neutrality, i.e. U = x.
+type Exponential struct {
+	RiskTolerance float64 // Risk tolerance R, must not be 0.
+}
+
+// Total implements [Function].
+func (f *Exponential) Total(utilities []float64, weights []float64) float64 {
+	x := weightedSum(utilities, weights)
+	return f.RiskTolerance * (1 - math.Exp(-x/f.RiskTolerance))
+}
+
+// Inverse implements [Function].
+func (f *Exponential) Inverse(total float64) (float64, bool) {
+	arg := 1 - total/f.RiskTolerance
+	if arg <= 0 {
+		return 0, false
+	}
+	return -f.RiskTolerance * math.Log(arg), true
+}
+
+// Validate implements [Function].
+func (f *Exponential) Validate() error {
+	if f.RiskTolerance == 0 {
+		return fmt.Errorf("risk tolerance of exponential utility must not be 0")
+	}
+	return nil
+}
+
+// Piecewise is a piecewise-linear value function of the weighted sum x of attribute utilities.
+//
+// The function interpolates linearly between points, and extrapolates the first and last segment.
+type Piecewise struct {
+	X []float64 // Values of the weighted sum, in strictly increasing order.
+	Y []float64 // Utilities at the respective values.
+}
+
+// Total implements [Function].
+func (f *Piecewise) Total(utilities []float64, weights []float64) float64 {
+	return interpolate(f.X, f.Y, weightedSum(utilities, weights))
+}
+
+// Inverse implements [Function]. Returns false if utilities are not strictly monotonic.
+func (f *Piecewise) Inverse(total float64) (float64, bool) {
+	if isIncreasing(f.Y) {
+		return interpolate(f.Y, f.X, total), true
+	}
+	y := slices.Clone(f.Y)
+	x := slices.Clone(f.X)
+	slices.Reverse(y)
+	slices.Reverse(x)
+	if isIncreasing(y) {
+		return interpolate(y, x, total), true
+	}
+	return 0, false
+}
+
+// Validate implements [Function].
+func (f *Piecewise) Validate() error {
+	if len(f.X) < 2 {
+		return fmt.Errorf("piecewise-linear utility requires at least two points")
+	}
+	if len(f.X) != len(f.Y) {
+		return fmt.Errorf("piecewise-linear utility requires the same number of x and y values")
+	}
+	if !isIncreasing(f.X) {
+		return fmt.Errorf("points of piecewise-linear utility must be in strictly increasing order")
+	}
+	return nil
+}
+
+// interpolate evaluates the piecewise-linear function through the given points at x,
+// extrapolating the first and last segment.
+func interpolate(xs, ys []float64, x float64) float64 {
+	i := 1
+	for i < len(xs)-1 && x > xs[i] {
+		i++
+	}
+	x0, x1, y0, y1 := xs[i-1], xs[i], ys[i-1], ys[i]
+	return y0 + (y1-y0)*(x-x0)/(x1-x0)
+}
+
+func isIncreasing(values []float64) bool {
+	for i := 1; i < len(values); i++ {
+		if values[i] <= values[i-1] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,80 @@
+package utility_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/mlange-42/bbn/utility"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMultiplicative(t *testing.T) {
+	f := utility.Multiplicative{Scaling: 0.5}
+	assert.Nil(t, f.Validate())
+
+	weights := []float64{0.6, 0.8}
+	assert.InDelta(t, 0.0, f.Total([]float64{0, 0}, weights), 1e-12)
+	assert.InDelta(t, ((1+0.5*0.6)*(1+0.5*0.8)-1)/0.5, f.Total([]float64{1, 1}, weights), 1e-12)
+	assert.InDelta(t, 0.6, f.Total([]float64{1, 0}, weights), 1e-12)
+
+	_, ok := f.Inverse(1)
+	assert.False(t, ok)
+
+	assert.NotNil(t, (&utility.Multiplicative{Scaling: 0}).Validate())
+	assert.NotNil(t, (&utility.Multiplicative{Scaling: -1}).Validate())
+}
+
+func TestExponential(t *testing.T) {
+	f := utility.Exponential{RiskTolerance: 100}
+	assert.Nil(t, f.Validate())
+
+	weights := []float64{1, 2}
+	u := f.Total([]float64{10, 20}, weights)
+	assert.InDelta(t, 100*(1-math.Exp(-0.5)), u, 1e-12)
+
+	x, ok := f.Inverse(u)
+	assert.True(t, ok)
+	assert.InDelta(t, 50, x, 1e-9)
+
+	_, ok = f.Inverse(100)
+	assert.False(t, ok)
+
+	// risk-seeking
+	f = utility.Exponential{RiskTolerance: -100}
+	u = f.Total([]float64{50}, []float64{1})
+	assert.Greater(t, u, 50.0)
+	x, ok = f.Inverse(u)
+	assert.True(t, ok)
+	assert.InDelta(t, 50, x, 1e-9)
+
+	assert.NotNil(t, (&utility.Exponential{}).Validate())
+}
+
+func TestPiecewise(t *testing.T) {
+	f := utility.Piecewise{X: []float64{0, 50, 100}, Y: []float64{0, 80, 100}}
+	assert.Nil(t, f.Validate())
+
+	weights := []float64{1}
+	assert.InDelta(t, 0, f.Total([]float64{0}, weights), 1e-12)
+	assert.InDelta(t, 40, f.Total([]float64{25}, weights), 1e-12)
+	assert.InDelta(t, 90, f.Total([]float64{75}, weights), 1e-12)
+	assert.InDelta(t, 120, f.Total([]float64{150}, weights), 1e-12)
+	assert.InDelta(t, -16, f.Total([]float64{-10}, weights), 1e-12)
+
+	x, ok := f.Inverse(90)
+	assert.True(t, ok)
+	assert.InDelta(t, 75, x, 1e-12)
+
+	f = utility.Piecewise{X: []float64{0, 100}, Y: []float64{100, 0}}
+	x, ok = f.Inverse(25)
+	assert.True(t, ok)
+	assert.InDelta(t, 75, x, 1e-12)
+
+	f = utility.Piecewise{X: []float64{0, 50, 100}, Y: []float64{0, 100, 0}}
+	_, ok = f.Inverse(50)
+	assert.False(t, ok)
+
+	assert.NotNil(t, (&utility.Piecewise{X: []float64{0}, Y: []float64{0}}).Validate())
+	assert.NotNil(t, (&utility.Piecewise{X: []float64{0, 0}, Y: []float64{0, 1}}).Validate())
+	assert.NotNil(t, (&utility.Piecewise{X: []float64{0, 1}, Y: []float64{0}}).Validate())
+}
@@ -0,0 +1,275 @@
+package bbn
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+
+	"github.com/mlange-42/bbn/ve"
+)
+
+// Tolerance for probability table rows summing to 1.
+const sumTolerance = 1e-6
+
+// Severity of a validation [Problem].
+type Severity uint8
+
+const (
+	Info    Severity = iota // Informative, e.g. a decision table that is ignored.
+	Warning                 // Potential problem that does not prevent solving the network.
+	Error                   // Problem that prevents solving the network, or results in wrong results.
+)
+
+var severityNames = [...]string{"info", "warning", "error"}
+
+func (s Severity) String() string {
+	return severityNames[s]
+}
+
+// Problem found by [Network.Validate].
+type Problem struct {
+	Severity Severity // Severity of the problem.
+	Variable string   // Name of the affected variable. Empty for problems of the network as a whole.
+	Message  string   // Description of the problem.
+}
+
+func (p Problem) String() string {
+	if p.Variable == "" {
+		return fmt.Sprintf("%s: %s", p.Severity, p.Message)
+	}
+	return fmt.Sprintf("%s: %s: %s", p.Severity, p.Variable, p.Message)
+}
+
+// Problems is a list of validation problems, as returned by [Network.Validate].
+type Problems []Problem
+
+// HasErrors checks whether any of the problems has severity [Error].
+func (p Problems) HasErrors() bool {
+	return p.Count(Error) > 0
+}
+
+// Count returns the number of problems with the given severity.
+func (p Problems) Count(severity Severity) int {
+	cnt := 0
+	for _, pr := range p {
+		if pr.Severity == severity {
+			cnt++
+		}
+	}
+	return cnt
+}
+
+// Validate checks the network's structure and tables.
+//
+// Checks for cycles, table size mismatches, negative values, all-zero rows,
+// rows that don't sum to 1, decisions that can't be ordered and utility nodes with children.
+// Returns an empty list if no problems were found.
+func (n *Network) Validate() Problems {
+	problems := Problems{}
+
+	problems = n.validateTables(problems)
+	problems = n.validateUtilities(problems)
+
+	cycles, problems := n.validateCycles(problems)
+	if !cycles {
+		problems = n.validateDecisionOrder(problems)
+	}
+
+	return problems
+}
+
+// validateTables checks table shapes and values.
+func (n *Network) validateTables(problems Problems) Problems {
+	for i := range n.variables {
+		v := &n.variables[i]
+		if v.Factor == nil {
+			if v.NodeType != ve.DecisionNode {
+				problems = append(problems, Problem{Error, v.Name, "no table defined"})
+			}
+			continue
+		}
+		cols := v.Factor.columns
+		expected := cols
+		for _, cnt := range v.Factor.outcomes {
+			expected *= cnt
+		}
+		table := v.Factor.Table
+
+		if v.NodeType == ve.DecisionNode {
+			if len(table) > 0 {
+				problems = append(problems, Problem{Info, v.Name, "table of decision node is ignored"})
+			}
+			continue
+		}
+		if len(table) == 0 {
+			problems = append(problems, Problem{Error, v.Name, "no table defined"})
+			continue
+		}
+		if len(table) != expected {
+			problems = append(problems, Problem{Error, v.Name,
+				fmt.Sprintf("wrong table size; expected %d values (%d rows with %d columns), got %d",
+					expected, expected/max(cols, 1), cols, len(table))})
+			continue
+		}
+		switch v.NodeType {
+		case ve.ChanceNode:
+			problems = validateProbabilities(v, problems)
+		case ve.ContinuousNode:
+			problems = validateGaussians(v, problems)
+		default:
+			problems = validateUtilityTable(v, problems)
+		}
+	}
+	return problems
+}
+
+// validateProbabilities checks the values of a probability table.
+func validateProbabilities(v *Variable, problems Problems) Problems {
+	cols := len(v.Outcomes)
+	table := v.Factor.Table
+	notNormalized := 0
+	for row := 0; row < len(table)/cols; row++ {
+		values := table[row*cols : (row+1)*cols]
+		sum := 0.0
+		valid := true
+		for _, p := range values {
+			if math.IsNaN(p) || math.IsInf(p, 0) {
+				problems = append(problems, Problem{Error, v.Name, fmt.Sprintf("invalid value %f in table row %d", p, row)})
+				valid = false
+				break
+			}
+			if p < 0 {
+				problems = append(problems, Problem{Error, v.Name, fmt.Sprintf("negative probability %f in table row %d", p, row)})
+				valid = false
+				break
+			}
+			sum += p
+		}
+		if !valid {
+			continue
+		}
+		if sum == 0 {
+			problems = append(problems, Problem{Warning, v.Name, fmt.Sprintf("all probabilities are zero in table row %d", row)})
+			continue
+		}
+		if math.Abs(sum-1) > sumTolerance {
+			notNormalized++
+		}
+	}
+	if notNormalized > 0 {
+		problems = append(problems, Problem{Warning, v.Name, fmt.Sprintf("%d table row(s) don't sum to 1 and will be normalized", notNormalized)})
+	}
+	return problems
+}
+
+// validateUtilityTable checks the values of a utility table.
+func validateUtilityTable(v *Variable, problems Problems) Problems {
+	for i, u := range v.Factor.Table {
+		if math.IsNaN(u) || math.IsInf(u, 0) {
+			problems = append(problems, Problem{Error, v.Name, fmt.Sprintf("invalid value %f in table row %d", u, i/len(v.Outcomes))})
+			break
+		}
+	}
+	return problems
+}
+
+// validateGaussians checks the values of a linear Gaussian table.
+func validateGaussians(v *Variable, problems Problems) Problems {
+	cols := v.Factor.columns
+	for row := 0; row < len(v.Factor.Table)/cols; row++ {
+		values := v.Factor.Table[row*cols : (row+1)*cols]
+		for _, x := range values {
+			if math.IsNaN(x) || math.IsInf(x, 0) {
+				problems = append(problems, Problem{Error, v.Name, fmt.Sprintf("invalid value %f in table row %d", x, row)})
+				return problems
+			}
+		}
+		if values[cols-1] <= 0 {
+			problems = append(problems, Problem{Error, v.Name, fmt.Sprintf("non-positive variance %f in table row %d", values[cols-1], row)})
+		}
+	}
+	return problems
+}
+
+// validateUtilities checks that utility nodes have no children, except the total utility node.
+func (n *Network) validateUtilities(problems Problems) Problems {
+	for i := range n.variables {
+		v := &n.variables[i]
+		if v.Factor == nil || i == n.totalUtilityIndex {
+			continue
+		}
+		for _, p := range v.Factor.Given {
+			parent, ok := n.variable(p)
+			if !ok || parent.NodeType != ve.UtilityNode {
+				continue
+			}
+			problems = append(problems, Problem{Error, p, fmt.Sprintf("utility node has child %s", v.Name)})
+		}
+	}
+	return problems
+}
+
+// validateCycles checks the network for cycles.
+// Returns whether cycles were found.
+func (n *Network) validateCycles(problems Problems) (bool, Problems) {
+	const (
+		unvisited = iota
+		active
+		done
+	)
+	state := make([]uint8, len(n.variables))
+	path := []int{}
+	found := false
+
+	var visit func(i int)
+	visit = func(i int) {
+		state[i] = active
+		path = append(path, i)
+		for _, p := range n.parentIndices(i) {
+			if state[p] == active {
+				start := slices.Index(path, p)
+				names := make([]string, 0, len(path)-start+1)
+				for _, idx := range path[start:] {
+					names = append(names, n.variables[idx].Name)
+				}
+				names = append(names, n.variables[p].Name)
+				slices.Reverse(names)
+				problems = append(problems, Problem{Error, n.variables[p].Name, "cycle " + strings.Join(names, " -> ")})
+				found = true
+				continue
+			}
+			if state[p] == unvisited {
+				visit(p)
+			}
+		}
+		path = path[:len(path)-1]
+		state[i] = done
+	}
+
+	for i := range n.variables {
+		if state[i] == unvisited {
+			visit(i)
+		}
+	}
+	return found, problems
+}
+
+// validateDecisionOrder checks that all decisions are connected by a directed path.
+// Requires an acyclic network.
+func (n *Network) validateDecisionOrder(problems Problems) Problems {
+	decisions := []int{}
+	for _, i := range n.topologicalIndices() {
+		if n.variables[i].NodeType == ve.DecisionNode {
+			decisions = append(decisions, i)
+		}
+	}
+	for i := 1; i < len(decisions); i++ {
+		prev, curr := decisions[i-1], decisions[i]
+		if !n.ancestorIndices(curr)[prev] {
+			problems = append(problems, Problem{Warning, n.variables[curr].Name,
+				fmt.Sprintf("no directed path from decision %s; decisions can't be ordered unambiguously", n.variables[prev].Name)})
+		}
+	}
+	return problems
+}
@@ -0,0 +1,88 @@
+package bbn_test
+
+import (
+	"testing"
+
+	"github.com/mlange-42/bbn"
+	"github.com/mlange-42/bbn/ve"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateCycle(t *testing.T) {
+	vars := []bbn.Variable{
+		{Name: "A", Outcomes: []string{"yes", "no"}},
+		{Name: "B", Outcomes: []string{"yes", "no"}},
+		{Name: "C", Outcomes: []string{"yes", "no"}},
+	}
+	factors := []bbn.Factor{
+		{For: "A", Given: []string{"C"}, Table: []float64{0.5, 0.5, 0.5, 0.5}},
+		{For: "B", Given: []string{"A"}, Table: []float64{0.5, 0.5, 0.5, 0.5}},
+		{For: "C", Given: []string{"B"}, Table: []float64{0.5, 0.5, 0.5, 0.5}},
+	}
+
+	net, err := bbn.New("cycle", "", vars, factors)
+	assert.Nil(t, err)
+
+	problems := net.Validate()
+	assert.True(t, problems.HasErrors())
+	assert.Equal(t, bbn.Problems{
+		{Severity: bbn.Error, Variable: "A", Message: "cycle A -> B -> C -> A"},
+	}, problems)
+}
+
+func TestValidateTables(t *testing.T) {
+	vars := []bbn.Variable{
+		{Name: "A", Outcomes: []string{"yes", "no"}},
+		{Name: "B", Outcomes: []string{"yes", "no"}},
+		{Name: "C", Outcomes: []string{"yes", "no"}},
+		{Name: "D", Outcomes: []string{"yes", "no"}},
+		{Name: "E", Outcomes: []string{"yes", "no"}},
+	}
+	factors := []bbn.Factor{
+		{For: "A", Table: []float64{60, 40}},
+		{For: "B", Given: []string{"A"}, Table: []float64{0.5, 0.5}},
+		{For: "C", Given: []string{"A"}, Table: []float64{0.5, 0.5, -0.5, 1.5}},
+		{For: "D", Given: []string{"A"}, Table: []float64{0.5, 0.5, 0, 0}},
+	}
+
+	net, err := bbn.New("tables", "", vars, factors)
+	assert.Nil(t, err)
+
+	problems := net.Validate()
+	assert.Equal(t, bbn.Problems{
+		{Severity: bbn.Warning, Variable: "A", Message: "1 table row(s) don't sum to 1 and will be normalized"},
+		{Severity: bbn.Error, Variable: "B", Message: "wrong table size; expected 4 values (2 rows with 2 columns), got 2"},
+		{Severity: bbn.Error, Variable: "C", Message: "negative probability -0.500000 in table row 1"},
+		{Severity: bbn.Warning, Variable: "D", Message: "all probabilities are zero in table row 1"},
+		{Severity: bbn.Error, Variable: "E", Message: "no table defined"},
+	}, problems)
+	assert.Equal(t, 3, problems.Count(bbn.Error))
+}
+
+func TestValidateDecisions(t *testing.T) {
+	vars := []bbn.Variable{
+		{Name: "A", Outcomes: []string{"yes", "no"}},
+		{Name: "D1", NodeType: ve.DecisionNode, Outcomes: []string{"yes", "no"}},
+		{Name: "D2", NodeType: ve.DecisionNode, Outcomes: []string{"yes", "no"}},
+		{Name: "U", NodeType: ve.UtilityNode, Outcomes: []string{"utility"}},
+		{Name: "B", Outcomes: []string{"yes", "no"}},
+	}
+	factors := []bbn.Factor{
+		{For: "A", Table: []float64{0.5, 0.5}},
+		{For: "D1", Given: []string{"A"}},
+		{For: "D2"},
+		{For: "U", Given: []string{"D1", "D2"}, Table: []float64{1, 2, 3, 4}},
+		{For: "B", Given: []string{"U"}, Table: []float64{0.5, 0.5}},
+	}
+
+	net, err := bbn.New("decisions", "", vars, factors)
+	assert.Nil(t, err)
+
+	problems := net.Validate()
+	assert.Equal(t, bbn.Problems{
+		{Severity: bbn.Error, Variable: "U", Message: "utility node has child B"},
+		{Severity: bbn.Warning, Variable: "D2", Message: "no directed path from decision D1; decisions can't be ordered unambiguously"},
+	}, problems)
+
+	assert.Equal(t, "error: U: utility node has child B", problems[0].String())
+}
@@ -0,0 +1,195 @@
+package bbn
+
+import (
+	"fmt"
+	"math"
+	"slices"
+
+	"github.com/mlange-42/bbn/ve"
+)
+
+// testSuffix is appended to the name of a variable for the name of its hypothetical test variable.
+const testSuffix = " test"
+
+// ValueReport reports the value of information and of control for a chance variable.
+// See [Network.ValueOfInformation].
+type ValueReport struct {
+	Variable string   // Name of the chance variable.
+	Outcomes []string // Outcomes of the chance variable.
+	Decision string   // Name of the decision the variable is observed before.
+	Utility  float64  // Expected utility of the network as is.
+	Perfect  float64  // Expected value of perfect information (EVPI).
+	Control  float64  // Value of control, i.e. the expected utility gain if the variable was a decision.
+	Test     *Test    // Hypothetical imperfect test. Nil if none.
+	Sample   float64  // Expected value of sample information (EVSI) of the test. Zero if there is no test.
+}
+
+// Test is a hypothetical, imperfect test of a chance variable. See [Network.ValueOfInformation].
+type Test struct {
+	Outcomes []string  // Outcomes of the test.
+	Table    []float64 // Probabilities of test outcomes, for each outcome of the tested variable.
+}
+
+// NewBinaryTest creates a test with outcomes "positive" and "negative" for a variable with two outcomes.
+//
+// The test is positive for the first outcome of the variable with probability sensitivity,
+// and negative for the second outcome with probability specificity.
+func NewBinaryTest(sensitivity, specificity float64) *Test {
+	return &Test{
+		Outcomes: []string{"positive", "negative"},
+		Table:    []float64{sensitivity, 1 - sensitivity, 1 - specificity, specificity},
+	}
+}
+
+// ValueOfInformation calculates the value of information and of control for a chance variable.
+//
+// The expected value of perfect information (EVPI) is the expected utility gain
+// if the variable is observed before the given decision.
+// The value of control is the gain if the variable becomes a decision without parents, taken first.
+// It is calculated by fixing the variable to each of its outcomes in turn.
+// If test is not nil, the expected value of sample information (EVSI) is the gain
+// if the result of the test, with the variable as its only parent, is observed before the decision.
+//
+// If decision is empty, the first decision in topological order is used.
+// Each value is calculated for a modified copy of the network, with policies re-solved by [Network.SolvePolicies].
+func (n *Network) ValueOfInformation(variable string, decision string, test *Test) (*ValueReport, error) {
+	decision, err := n.checkValueOfInformation(variable, decision)
+	if err != nil {
+		return nil, err
+	}
+	v, _ := n.variable(variable)
+	report := ValueReport{Variable: variable, Outcomes: v.Outcomes, Decision: decision, Test: test}
+
+	if report.Utility, err = n.modifiedUtility(func(m *mutation) error { return nil }); err != nil {
+		return nil, err
+	}
+	perfect, err := n.modifiedUtility(func(m *mutation) error { return m.observe(variable, decision) })
+	if err != nil {
+		return nil, err
+	}
+	control, err := n.controlledUtility(variable)
+	if err != nil {
+		return nil, err
+	}
+	report.Perfect = perfect - report.Utility
+	report.Control = control - report.Utility
+
+	if test == nil {
+		return &report, nil
+	}
+	sample, err := n.modifiedUtility(func(m *mutation) error { return m.addTest(variable, decision, test) })
+	if err != nil {
+		return nil, err
+	}
+	report.Sample = sample - report.Utility
+	return &report, nil
+}
+
+// checkValueOfInformation checks the arguments of [Network.ValueOfInformation].
+// Returns the decision, which is the first decision in topological order if empty.
+func (n *Network) checkValueOfInformation(variable string, decision string) (string, error) {
+	v, ok := n.variable(variable)
+	if !ok {
+		return "", newVariableError(variable, ErrUnknownVariable, "variable %s not found", variable)
+	}
+	if v.NodeType != ve.ChanceNode {
+		return "", newVariableError(variable, ErrUnsupported, "variable %s is not a chance variable", variable)
+	}
+	if decision != "" {
+		d, ok := n.variable(decision)
+		if !ok {
+			return "", newVariableError(decision, ErrUnknownVariable, "variable %s not found", decision)
+		}
+		if d.NodeType != ve.DecisionNode {
+			return "", newVariableError(decision, ErrUnsupported, "variable %s is not a decision variable", decision)
+		}
+		return decision, nil
+	}
+	topological, err := n.TopologicalOrder()
+	if err != nil {
+		return "", err
+	}
+	for _, name := range topological {
+		if d, _ := n.variable(name); d.NodeType == ve.DecisionNode {
+			return name, nil
+		}
+	}
+	return "", fmt.Errorf("network has no decisions")
+}
+
+// modifiedUtility applies a modification to a copy of the network, solves its policies,
+// and returns its expected utility. The network itself is not changed.
+func (n *Network) modifiedUtility(fn func(m *mutation) error) (float64, error) {
+	copied := *n
+	if err := copied.mutate(fn); err != nil {
+		return 0, err
+	}
+	if _, err := copied.SolvePolicies(true); err != nil {
+		return 0, err
+	}
+	utility, err := copied.SolveUtility(map[string]string{}, []string{}, "", false)
+	if err != nil {
+		return 0, err
+	}
+	return utility.Data()[0], nil
+}
+
+// controlledUtility returns the expected utility if the given chance variable was a decision without parents.
+// That is the maximum expected utility over all outcomes the variable can be fixed to.
+func (n *Network) controlledUtility(variable string) (float64, error) {
+	v, _ := n.variable(variable)
+	best := math.Inf(-1)
+	for i := range v.Outcomes {
+		utility, err := n.modifiedUtility(func(m *mutation) error { return m.control(variable, i) })
+		if err != nil {
+			return 0, err
+		}
+		best = max(best, utility)
+	}
+	return best, nil
+}
+
+// observe adds an edge from a variable to a decision, unless it already exists.
+func (m *mutation) observe(variable string, decision string) error {
+	if f := m.factor(decision); f != nil && slices.Contains(f.Given, variable) {
+		return nil
+	}
+	return m.addEdge(variable, decision)
+}
+
+// control fixes a chance variable to the outcome with the given index, by removing its parents
+// and replacing its table by a point mass.
+// A canonical model (see [Factor.Noisy]) of the variable is replaced by the point mass.
+// Removing edges already replaces it, but a variable without parents may still have one.
+func (m *mutation) control(variable string, outcome int) error {
+	v := &m.variables[m.index(variable)]
+	f := m.factorOrNew(v)
+	for len(f.Given) > 0 {
+		if err := m.removeEdge(f.Given[0], variable); err != nil {
+			return err
+		}
+	}
+	f.Noisy = nil
+	f.Table = make([]float64, len(v.Outcomes))
+	f.Table[outcome] = 1
+	return nil
+}
+
+// addTest adds a test variable for a chance variable, observed before a decision.
+func (m *mutation) addTest(variable string, decision string, test *Test) error {
+	name := variable + testSuffix
+	if m.index(name) >= 0 {
+		return newVariableError(name, ve.ErrDuplicateVariable, "duplicate variable name %s", name)
+	}
+	outcomes, err := m.outcomeCounts([]string{variable})
+	if err != nil {
+		return err
+	}
+	if len(test.Table) != outcomes[0]*len(test.Outcomes) {
+		return newVariableError(name, ErrTableShape, "wrong table size for test of %s; expected %d values, got %d",
+			variable, outcomes[0]*len(test.Outcomes), len(test.Table))
+	}
+	m.variables = append(m.variables, Variable{Name: name, NodeType: ve.ChanceNode, Outcomes: slices.Clone(test.Outcomes)})
+	m.factors = append(m.factors, Factor{For: name, Given: []string{variable}, Table: slices.Clone(test.Table)})
+	return m.addEdge(name, decision)
+}
@@ -0,0 +1,80 @@
+package bbn_test
+
+import (
+	"testing"
+
+	"github.com/mlange-42/bbn"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNetworkValueOfInformation(t *testing.T) {
+	net, err := bbn.FromFile("_examples/decision/umbrella.yml")
+	assert.Nil(t, err)
+
+	report, err := net.ValueOfInformation("Weather", "", bbn.NewBinaryTest(0.9, 0.8))
+	assert.Nil(t, err)
+	assert.Equal(t, "Umbrella", report.Decision)
+	assert.Equal(t, []string{"Sunny", "Rainy"}, report.Outcomes)
+	assert.InDelta(t, 77, report.Utility, 1e-9)
+	assert.InDelta(t, 0.7*100+0.3*70-77, report.Perfect, 1e-9)
+	assert.InDelta(t, 100-77, report.Control, 1e-9)
+	assert.Greater(t, report.Sample, 0.0)
+	assert.Less(t, report.Sample, report.Perfect)
+
+	perfect, err := net.ValueOfInformation("Weather", "Umbrella", bbn.NewBinaryTest(1, 1))
+	assert.Nil(t, err)
+	assert.InDelta(t, perfect.Perfect, perfect.Sample, 1e-9)
+
+	forecast, err := net.ValueOfInformation("Forecast", "Umbrella", nil)
+	assert.Nil(t, err)
+	assert.InDelta(t, 0, forecast.Perfect, 1e-9)
+	// a fixed forecast is uninformative
+	assert.InDelta(t, 0.7*100-77, forecast.Control, 1e-9)
+	assert.Equal(t, 0.0, forecast.Sample)
+
+	// the network itself is not modified
+	assert.Equal(t, 4, len(net.Variables()))
+	_, err = net.DecisionTree(nil, 0)
+	assert.ErrorIs(t, err, bbn.ErrNoPolicy)
+
+	_, err = net.ValueOfInformation("Umbrella", "", nil)
+	assert.ErrorIs(t, err, bbn.ErrUnsupported)
+	_, err = net.ValueOfInformation("Weather", "Forecast", nil)
+	assert.ErrorIs(t, err, bbn.ErrUnsupported)
+	_, err = net.ValueOfInformation("Missing", "", nil)
+	assert.ErrorIs(t, err, bbn.ErrUnknownVariable)
+	_, err = net.ValueOfInformation("Weather", "Missing", nil)
+	assert.ErrorIs(t, err, bbn.ErrUnknownVariable)
+	_, err = net.ValueOfInformation("Forecast", "", bbn.NewBinaryTest(0.9, 0.8))
+	assert.ErrorIs(t, err, bbn.ErrTableShape)
+}
+
+func TestNetworkValueOfInformationSequential(t *testing.T) {
+	net, err := bbn.FromFile("_examples/decision/oil.yml")
+	assert.Nil(t, err)
+
+	report, err := net.ValueOfInformation("Oil", "Do drill", nil)
+	assert.Nil(t, err)
+	assert.InDelta(t, 22.5, report.Utility, 1e-9)
+	assert.InDelta(t, 0.3*50+0.2*200-22.5, report.Perfect, 1e-9)
+	assert.InDelta(t, 200-22.5, report.Control, 1e-9)
+
+	_, err = net.ValueOfInformation("Test result", "Do test drill", nil)
+	assert.ErrorIs(t, err, bbn.ErrCycle)
+
+	sprinkler, err := bbn.FromFile("_examples/bbn/sprinkler.yml")
+	assert.Nil(t, err)
+	_, err = sprinkler.ValueOfInformation("Rain", "", nil)
+	assert.NotNil(t, err)
+}
+
+func TestNetworkValueOfInformationLIMID(t *testing.T) {
+	net, err := bbn.FromFile("_examples/decision/maintenance.yml")
+	assert.Nil(t, err)
+
+	report, err := net.ValueOfInformation("Wear", "Replace", bbn.NewBinaryTest(0.9, 0.8))
+	assert.Nil(t, err)
+	assert.InDelta(t, -24.25, report.Utility, 1e-9)
+	assert.GreaterOrEqual(t, report.Perfect, report.Sample)
+	assert.True(t, net.LIMID())
+}
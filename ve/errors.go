@@ -0,0 +1,13 @@
+package ve
+
+import "errors"
+
+// Sentinel errors returned by the error-returning functions of this package.
+// Use [errors.Is] to check for them.
+var (
+	ErrUnknownVariable   = errors.New("unknown variable")
+	ErrUnknownOutcome    = errors.New("unknown outcome")
+	ErrTableShape        = errors.New("wrong table shape")
+	ErrDuplicateVariable = errors.New("duplicate variable")
+	ErrNoPolicy          = errors.New("unable to derive policy")
+)
@@ -28,29 +28,99 @@ func (f *Factor) Variables() []Variable {
 }
 
 // Index in [Factor.Data] from outcome indices of factor variables.
+//
+// Panics if the number of indices does not match the factor's variables.
+// See [Factor.TryIndex] for an error-returning variant.
 func (f *Factor) Index(indices []int) int {
-	return f.variables.Index(indices)
+	idx, err := f.TryIndex(indices)
+	if err != nil {
+		panic(err)
+	}
+	return idx
+}
+
+// TryIndex returns the index in [Factor.Data] from outcome indices of factor variables.
+//
+// Returns an error wrapping [ErrTableShape] if the number of indices does not match the factor's variables.
+func (f *Factor) TryIndex(indices []int) (int, error) {
+	if err := f.variables.checkIndices(indices); err != nil {
+		return 0, err
+	}
+	return f.variables.Index(indices), nil
 }
 
 // Index in [Factor.Data] from outcome indices of factor variables.
 // Can be used with missing data, represented by -1.
 // Second return value is false if there is missing data in indices.
+//
+// Panics if the number of indices does not match the factor's variables.
+// See [Factor.TryIndexWithNoData] for an error-returning variant.
 func (f *Factor) IndexWithNoData(indices []int) (int, bool) {
-	return f.variables.IndexWithNoData(indices)
+	idx, ok, err := f.TryIndexWithNoData(indices)
+	if err != nil {
+		panic(err)
+	}
+	return idx, ok
+}
+
+// TryIndexWithNoData returns the index in [Factor.Data] from outcome indices of factor variables.
+// Can be used with missing data, represented by -1.
+// Second return value is false if there is missing data in indices.
+//
+// Returns an error wrapping [ErrTableShape] if the number of indices does not match the factor's variables.
+func (f *Factor) TryIndexWithNoData(indices []int) (int, bool, error) {
+	if err := f.variables.checkIndices(indices); err != nil {
+		return 0, false, err
+	}
+	idx, ok := f.variables.IndexWithNoData(indices)
+	return idx, ok, nil
 }
 
 // Outcomes calculates variable outcomes for a flat index of [Factor.Data].
 // Inverse operation of [Factor.Index].
+//
+// Panics if the number of indices does not match the factor's variables.
+// See [Factor.TryOutcomes] for an error-returning variant.
 func (f *Factor) Outcomes(index int, indices []int) {
+	if err := f.TryOutcomes(index, indices); err != nil {
+		panic(err)
+	}
+}
+
+// TryOutcomes calculates variable outcomes for a flat index of [Factor.Data].
+// Inverse operation of [Factor.Index].
+//
+// Returns an error wrapping [ErrTableShape] if the number of indices does not match the factor's variables.
+func (f *Factor) TryOutcomes(index int, indices []int) error {
+	if err := f.variables.checkIndices(indices); err != nil {
+		return err
+	}
 	f.variables.Outcomes(index, indices)
+	return nil
 }
 
 // RowIndex returns starting index and length of a "row" of data.
 // A row is for fixed outcomes of all but the last variable,
 // while the outcome of the last variable is used to index in the row.
+//
+// Panics if the number of indices is not one less than the factor's variables.
+// See [Factor.TryRowIndex] for an error-returning variant.
 func (f *Factor) RowIndex(indices []int) (int, int) {
-	if len(indices) != len(f.variables)-1 {
-		panic(fmt.Sprintf("factor with %d variables can't use %d row indices", len(f.variables), len(indices)))
+	idx, ln, err := f.TryRowIndex(indices)
+	if err != nil {
+		panic(err)
+	}
+	return idx, ln
+}
+
+// TryRowIndex returns starting index and length of a "row" of data.
+// A row is for fixed outcomes of all but the last variable,
+// while the outcome of the last variable is used to index in the row.
+//
+// Returns an error wrapping [ErrTableShape] if the number of indices is not one less than the factor's variables.
+func (f *Factor) TryRowIndex(indices []int) (int, int, error) {
+	if len(f.variables) == 0 || len(indices) != len(f.variables)-1 {
+		return 0, 0, fmt.Errorf("%w: factor with %d variables can't use %d row indices", ErrTableShape, len(f.variables), len(indices))
 	}
 	idx := 0
 	stride := int(f.variables[len(f.variables)-1].outcomes)
@@ -60,16 +130,20 @@ func (f *Factor) RowIndex(indices []int) (int, int) {
 		stride *= int(f.variables[i].outcomes)
 	}
 
-	return idx, int(f.variables[len(f.variables)-1].outcomes)
+	return idx, int(f.variables[len(f.variables)-1].outcomes), nil
 }
 
 // Get the factor's value for the given variable outcome indices.
+//
+// Panics if the number of indices does not match the factor's variables.
 func (f *Factor) Get(indices []int) float64 {
 	idx := f.Index(indices)
 	return f.data[idx]
 }
 
 // Set the factor's value for the given variable outcome indices.
+//
+// Panics if the number of indices does not match the factor's variables.
 func (f *Factor) Set(indices []int, value float64) {
 	idx := f.Index(indices)
 	f.data[idx] = value
@@ -78,19 +152,44 @@ func (f *Factor) Set(indices []int, value float64) {
 // Row returns a "row" of data.
 // A row is for fixed outcomes of all but the last variable,
 // while the outcome of the last variable is used to index in the row.
+//
+// Panics if the number of indices is not one less than the factor's variables.
+// See [Factor.TryRow] for an error-returning variant.
 func (f *Factor) Row(indices []int) []float64 {
-	idx, ln := f.RowIndex(indices)
-	return f.data[idx : idx+ln]
+	row, err := f.TryRow(indices)
+	if err != nil {
+		panic(err)
+	}
+	return row
+}
+
+// TryRow returns a "row" of data.
+// A row is for fixed outcomes of all but the last variable,
+// while the outcome of the last variable is used to index in the row.
+//
+// Returns an error wrapping [ErrTableShape] if the number of indices is not one less than the factor's variables.
+func (f *Factor) TryRow(indices []int) ([]float64, error) {
+	idx, ln, err := f.TryRowIndex(indices)
+	if err != nil {
+		return nil, err
+	}
+	return f.data[idx : idx+ln], nil
 }
 
 // Helper type for a list of variables for a factor
 type factorVariables []Variable
 
-// Index creates a flat [Factor] index from a multi-dimensional index.
-func (v factorVariables) Index(indices []int) int {
+// checkIndices checks that the number of indices matches the number of variables.
+func (v factorVariables) checkIndices(indices []int) error {
 	if len(indices) != len(v) {
-		panic(fmt.Sprintf("factor with %d variables can't use %d indices", len(v), len(indices)))
+		return fmt.Errorf("%w: factor with %d variables can't use %d indices", ErrTableShape, len(v), len(indices))
 	}
+	return nil
+}
+
+// Index creates a flat [Factor] index from a multi-dimensional index.
+// The number of indices must match the number of variables, see [factorVariables.checkIndices].
+func (v factorVariables) Index(indices []int) int {
 	index := 0
 	multiplier := 1
 	for i := len(indices) - 1; i >= 0; i-- {
@@ -104,10 +203,8 @@ func (v factorVariables) Index(indices []int) int {
 // Index creates a flat [Factor] index from a multi-dimensional index.
 // When data is missing in the index (represented by -1),
 // the second return value is false.
+// The number of indices must match the number of variables, see [factorVariables.checkIndices].
 func (v factorVariables) IndexWithNoData(indices []int) (int, bool) {
-	if len(indices) != len(v) {
-		panic(fmt.Sprintf("factor with %d variables can't use %d indices", len(v), len(indices)))
-	}
 	if len(v) == 0 {
 		return 0, true
 	}
@@ -126,11 +223,9 @@ func (v factorVariables) IndexWithNoData(indices []int) (int, bool) {
 	return index, true
 }
 
-// Index creates multi-dimensional index from a flat [Factor] index.
+// Outcomes creates multi-dimensional index from a flat [Factor] index.
+// The number of indices must match the number of variables, see [factorVariables.checkIndices].
 func (v factorVariables) Outcomes(index int, indices []int) {
-	if len(indices) != len(v) {
-		panic(fmt.Sprintf("factor with %d variables can't use %d indices", len(v), len(indices)))
-	}
 	if len(v) == 0 {
 		return
 	}
@@ -143,11 +238,8 @@ func (v factorVariables) Outcomes(index int, indices []int) {
 
 // increment increments the multi-dimensional index by one.
 // Returns false if the index overflows.
+// The number of indices must match the number of variables, see [factorVariables.checkIndices].
 func (v factorVariables) increment(indices []int) bool {
-	if len(indices) != len(v) {
-		panic(fmt.Sprintf("factor with %d variables can't use %d indices", len(v), len(indices)))
-	}
-
 	for i := len(indices) - 1; i >= 0; i-- {
 		indices[i]++
 		if indices[i] < int(v[i].outcomes) {
@@ -160,3 +160,31 @@ func BenchmarkFactorOutcome(b *testing.B) {
 	indices[0] = 1
 	_ = indices
 }
+
+func TestFactorIndexErrors(t *testing.T) {
+	f := Factor{
+		variables: []Variable{
+			{id: 0, outcomes: 3},
+			{id: 1, outcomes: 2},
+		},
+		data: make([]float64, 6),
+	}
+
+	_, err := f.TryIndex([]int{0})
+	assert.ErrorIs(t, err, ErrTableShape)
+	_, _, err = f.TryIndexWithNoData([]int{0, 0, 0})
+	assert.ErrorIs(t, err, ErrTableShape)
+	err = f.TryOutcomes(0, []int{0})
+	assert.ErrorIs(t, err, ErrTableShape)
+	_, _, err = f.TryRowIndex([]int{0, 0})
+	assert.ErrorIs(t, err, ErrTableShape)
+	_, err = f.TryRow([]int{})
+	assert.ErrorIs(t, err, ErrTableShape)
+
+	row, err := f.TryRow([]int{2})
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(row))
+
+	assert.Panics(t, func() { f.Index([]int{0}) })
+	assert.Panics(t, func() { f.RowIndex([]int{}) })
+}
@@ -7,6 +7,9 @@ const (
 	ChanceNode NodeType = iota
 	DecisionNode
 	UtilityNode
+	// ContinuousNode is a continuous (Gaussian) variable.
+	// It is not handled by [VE] directly, but by higher-level packages.
+	ContinuousNode
 )
 
 // Variable definition for variable elimination by [VE].
@@ -21,10 +21,24 @@ func NewVariables() *Variables {
 }
 
 // AddVariable creates and add a new [Variable].
+//
+// Panics if there is already a variable with the given ID. See [Variables.TryAddVariable] for an error-returning variant.
 func (v *Variables) AddVariable(id int, nodeType NodeType, outcomes uint16) Variable {
+	variable, err := v.TryAddVariable(id, nodeType, outcomes)
+	if err != nil {
+		panic(err)
+	}
+	return variable
+}
+
+// TryAddVariable creates and add a new [Variable].
+//
+// Returns an error wrapping [ErrDuplicateVariable] if there is already a variable with the given ID.
+func (v *Variables) TryAddVariable(id int, nodeType NodeType, outcomes uint16) (Variable, error) {
 	if _, ok := v.ids[id]; ok {
-		panic(fmt.Sprintf("there is already a variable with ID %d", id))
+		return Variable{}, fmt.Errorf("%w: there is already a variable with ID %d", ErrDuplicateVariable, id)
 	}
+	v.ids[id] = true
 	v.variables = append(v.variables,
 		Variable{
 			id:       id,
@@ -32,13 +46,28 @@ func (v *Variables) AddVariable(id int, nodeType NodeType, outcomes uint16) Vari
 			outcomes: outcomes,
 			nodeType: nodeType,
 		})
-	return v.variables[len(v.variables)-1]
+	return v.variables[len(v.variables)-1], nil
 }
 
 // CreateFactor creates a new [Factor] for the given variables.
 //
 // Argument data may be nil.
+//
+// Panics if the length of data does not match the variables. See [Variables.TryCreateFactor] for an error-returning variant.
 func (v *Variables) CreateFactor(vars []Variable, data []float64) Factor {
+	f, err := v.TryCreateFactor(vars, data)
+	if err != nil {
+		panic(err)
+	}
+	return f
+}
+
+// TryCreateFactor creates a new [Factor] for the given variables.
+//
+// Argument data may be nil.
+//
+// Returns an error wrapping [ErrTableShape] if the length of data does not match the variables.
+func (v *Variables) TryCreateFactor(vars []Variable, data []float64) (Factor, error) {
 	rows := 1
 	variables := make([]Variable, len(vars))
 	for i, v := range vars {
@@ -49,7 +78,7 @@ func (v *Variables) CreateFactor(vars []Variable, data []float64) Factor {
 		data = make([]float64, rows)
 	} else {
 		if len(data) != rows {
-			panic(fmt.Sprintf("wrong data length for factor. expected %d, got %d", rows, len(data)))
+			return Factor{}, fmt.Errorf("%w: wrong data length for factor. expected %d, got %d", ErrTableShape, rows, len(data))
 		}
 	}
 
@@ -59,13 +88,27 @@ func (v *Variables) CreateFactor(vars []Variable, data []float64) Factor {
 		id:        v.factorCounter - 1,
 		variables: variables,
 		data:      data,
-	}
+	}, nil
 }
 
 // Restrict a factor to the given evidence.
+//
+// Panics on invalid arguments. See [Variables.TryRestrict] for an error-returning variant.
 func (v *Variables) Restrict(f *Factor, variable Variable, observation int) Factor {
+	fNew, err := v.TryRestrict(f, variable, observation)
+	if err != nil {
+		panic(err)
+	}
+	return fNew
+}
+
+// TryRestrict restricts a factor to the given evidence.
+//
+// Returns an error wrapping [ErrUnknownOutcome] if the observation is out of range,
+// or wrapping [ErrUnknownVariable] if the variable is not in the factor.
+func (v *Variables) TryRestrict(f *Factor, variable Variable, observation int) (Factor, error) {
 	if observation < 0 || observation >= int(variable.outcomes) {
-		panic(fmt.Sprintf("observation %d out of range for variable with %d possible observation values", observation, variable.outcomes))
+		return Factor{}, fmt.Errorf("%w: observation %d out of range for variable with %d possible observation values", ErrUnknownOutcome, observation, variable.outcomes)
 	}
 	newVars := make([]Variable, 0, len(f.variables)-1)
 	idx := -1
@@ -81,7 +124,7 @@ func (v *Variables) Restrict(f *Factor, variable Variable, observation int) Fact
 	}
 
 	if idx < 0 {
-		panic(fmt.Sprintf("variable %d not in this factor", variable.id))
+		return Factor{}, notInFactorError(variable)
 	}
 
 	fNew := v.CreateFactor(newVars, nil)
@@ -103,11 +146,24 @@ func (v *Variables) Restrict(f *Factor, variable Variable, observation int) Fact
 		f.variables.increment(oldIndex)
 	}
 
-	return fNew
+	return fNew, nil
 }
 
 // SumOut a [Variable] from a [Factor].
+//
+// Panics on invalid arguments. See [Variables.TrySumOut] for an error-returning variant.
 func (v *Variables) SumOut(f *Factor, variable Variable) Factor {
+	fNew, err := v.TrySumOut(f, variable)
+	if err != nil {
+		panic(err)
+	}
+	return fNew
+}
+
+// TrySumOut sums out a [Variable] from a [Factor].
+//
+// Returns an error wrapping [ErrUnknownVariable] if a variable is not in the factor.
+func (v *Variables) TrySumOut(f *Factor, variable Variable) (Factor, error) {
 	newVars := make([]Variable, 0, len(f.variables)-1)
 	idx := -1
 
@@ -122,7 +178,7 @@ func (v *Variables) SumOut(f *Factor, variable Variable) Factor {
 	}
 
 	if idx < 0 {
-		panic(fmt.Sprintf("variable %d not in this factor", variable.id))
+		return Factor{}, notInFactorError(variable)
 	}
 
 	fNew := v.CreateFactor(newVars, nil)
@@ -143,11 +199,47 @@ func (v *Variables) SumOut(f *Factor, variable Variable) Factor {
 		f.variables.increment(oldIndex)
 	}
 
-	return fNew
+	return fNew, nil
 }
 
 // Policy derives a policy from a [Factor].
+//
+// Panics on invalid arguments. See [Variables.TryPolicy] for an error-returning variant.
 func (v *Variables) Policy(f *Factor, variable Variable) Factor {
+	fNew, err := v.TryPolicy(f, variable)
+	if err != nil {
+		panic(err)
+	}
+	return fNew
+}
+
+// TryPolicy derives a policy from a [Factor].
+//
+// Returns an error wrapping [ErrUnknownVariable] if a variable is not in the factor,
+// or wrapping [ErrNoPolicy] if the variable has no outcomes.
+func (v *Variables) TryPolicy(f *Factor, variable Variable) (Factor, error) {
+	return v.TryConstrainedPolicy(f, variable, nil)
+}
+
+// ConstrainedPolicy derives a policy from a [Factor], maximizing only over allowed outcomes.
+//
+// Panics on invalid arguments. See [Variables.TryConstrainedPolicy] for an error-returning variant.
+func (v *Variables) ConstrainedPolicy(f *Factor, variable Variable, allowed *Factor) Factor {
+	fNew, err := v.TryConstrainedPolicy(f, variable, allowed)
+	if err != nil {
+		panic(err)
+	}
+	return fNew
+}
+
+// TryConstrainedPolicy derives a policy from a [Factor], maximizing only over allowed outcomes.
+//
+// Argument allowed is a factor over the variable and a subset of the factor's variables,
+// with non-zero values for allowed outcomes. If it is nil, all outcomes are allowed.
+//
+// Returns an error wrapping [ErrUnknownVariable] if a variable is not in the factor,
+// or wrapping [ErrNoPolicy] if the variable has no outcomes, or no allowed outcomes.
+func (v *Variables) TryConstrainedPolicy(f *Factor, variable Variable, allowed *Factor) (Factor, error) {
 	newVars := make([]Variable, 0, len(f.variables))
 	idx := -1
 
@@ -160,7 +252,7 @@ func (v *Variables) Policy(f *Factor, variable Variable) Factor {
 	}
 
 	if idx < 0 {
-		panic(fmt.Sprintf("variable %d not in this factor", variable.id))
+		return Factor{}, notInFactorError(variable)
 	}
 	newVars = append(newVars, f.variables[idx])
 
@@ -171,9 +263,19 @@ func (v *Variables) Policy(f *Factor, variable Variable) Factor {
 	idxNew := len(newVars) - 1
 
 	cols := int(f.variables[idx].outcomes)
+	if cols == 0 {
+		return Factor{}, fmt.Errorf("%w: variable %d has no outcomes", ErrNoPolicy, variable.id)
+	}
 	rows := len(f.data) / cols
 
+	allowedMap, err := allowedIndices(f, allowed)
+	if err != nil {
+		return Factor{}, err
+	}
+	allowedIndex := make([]int, len(allowedMap))
+
 	rowData := make([]float64, cols)
+	feasible := make([]bool, cols)
 	maxIndices := make([]int, 0, 8)
 	for row := 0; row < rows; row++ {
 		fNew.Outcomes(row*cols, newIndex)
@@ -188,20 +290,19 @@ func (v *Variables) Policy(f *Factor, variable Variable) Factor {
 			}
 			oldIndex[idx] = newIndex[idxNew]
 			rowData[c] = f.Get(oldIndex)
-		}
-		maxUtility := math.Inf(-1)
-		for c, u := range rowData {
-			if u > maxUtility {
-				maxUtility = u
-				maxIndices = maxIndices[:1]
-				maxIndices[0] = c
-			} else if u == maxUtility {
-				maxIndices = append(maxIndices, c)
+
+			feasible[c] = true
+			if allowed != nil {
+				for j, k := range allowedMap {
+					allowedIndex[j] = oldIndex[k]
+				}
+				feasible[c] = allowed.Get(allowedIndex) != 0
 			}
 		}
+		maxIndices = argMax(rowData, feasible, maxIndices)
 
 		if len(maxIndices) == 0 {
-			panic("no utility values to derive policy")
+			return Factor{}, fmt.Errorf("%w: no allowed outcome for variable %d", ErrNoPolicy, variable.id)
 		}
 
 		probValue := 1.0 / float64(len(maxIndices))
@@ -213,13 +314,60 @@ func (v *Variables) Policy(f *Factor, variable Variable) Factor {
 		maxIndices = maxIndices[:0]
 	}
 
-	return fNew
+	return fNew, nil
+}
+
+// allowedIndices maps the variables of a factor of allowed outcomes to their indices in a factor.
+// Returns nil if allowed is nil.
+func allowedIndices(f *Factor, allowed *Factor) ([]int, error) {
+	if allowed == nil {
+		return nil, nil
+	}
+	result := make([]int, len(allowed.variables))
+	for i, av := range allowed.variables {
+		result[i] = slices.IndexFunc(f.variables, av.Is)
+		if result[i] < 0 {
+			return nil, notInFactorError(av)
+		}
+	}
+	return result, nil
+}
+
+// argMax appends the indices of all maximum values among the feasible values to result.
+func argMax(values []float64, feasible []bool, result []int) []int {
+	maxValue := math.Inf(-1)
+	for c, u := range values {
+		if !feasible[c] {
+			continue
+		}
+		if len(result) == 0 || u > maxValue {
+			maxValue = u
+			result = append(result[:0], c)
+		} else if u == maxValue {
+			result = append(result, c)
+		}
+	}
+	return result
 }
 
 // Rearrange changes the [Variable] order of a [Factor].
+//
+// Panics on invalid arguments. See [Variables.TryRearrange] for an error-returning variant.
 func (v *Variables) Rearrange(f *Factor, variables []Variable) Factor {
+	fNew, err := v.TryRearrange(f, variables)
+	if err != nil {
+		panic(err)
+	}
+	return fNew
+}
+
+// TryRearrange changes the [Variable] order of a [Factor].
+//
+// Returns an error wrapping [ErrUnknownVariable] if a variable is not in the factor,
+// or wrapping [ErrTableShape] if the number of variables does not match.
+func (v *Variables) TryRearrange(f *Factor, variables []Variable) (Factor, error) {
 	if len(f.variables) != len(variables) {
-		panic("number of old and new variables doesn't match")
+		return Factor{}, fmt.Errorf("%w: number of old and new variables doesn't match", ErrTableShape)
 	}
 
 	varsEqual := true
@@ -230,12 +378,12 @@ func (v *Variables) Rearrange(f *Factor, variables []Variable) Factor {
 		}
 		idx := slices.Index(f.variables, vv)
 		if idx < 0 {
-			panic(fmt.Sprintf("variable %d not in original factor", vv.id))
+			return Factor{}, fmt.Errorf("%w: variable %d not in original factor", ErrUnknownVariable, vv.id)
 		}
 		indices[i] = idx
 	}
 	if varsEqual {
-		return v.CreateFactor(f.variables, append([]float64{}, f.data...))
+		return v.CreateFactor(f.variables, append([]float64{}, f.data...)), nil
 	}
 
 	fNew := v.CreateFactor(variables, nil)
@@ -251,7 +399,7 @@ func (v *Variables) Rearrange(f *Factor, variables []Variable) Factor {
 		fNew.variables.increment(newIndex)
 	}
 
-	return fNew
+	return fNew, nil
 }
 
 // Product multiplies factors.
@@ -349,7 +497,20 @@ func (v *Variables) Sum(factors ...*Factor) Factor {
 }
 
 // Marginal calculates marginal probabilities from a [Factor] for the given [Variable].
+//
+// Panics on invalid arguments. See [Variables.TryMarginal] for an error-returning variant.
 func (v *Variables) Marginal(f *Factor, variable Variable) Factor {
+	fNew, err := v.TryMarginal(f, variable)
+	if err != nil {
+		panic(err)
+	}
+	return fNew
+}
+
+// TryMarginal calculates marginal probabilities from a [Factor] for the given [Variable].
+//
+// Returns an error wrapping [ErrUnknownVariable] if a variable is not in the factor.
+func (v *Variables) TryMarginal(f *Factor, variable Variable) (Factor, error) {
 	idx := -1
 	for i := range f.variables {
 		if f.variables[i].id == variable.id {
@@ -359,7 +520,7 @@ func (v *Variables) Marginal(f *Factor, variable Variable) Factor {
 	}
 
 	if idx < 0 {
-		panic(fmt.Sprintf("variable %d not in this factor", variable.id))
+		return Factor{}, notInFactorError(variable)
 	}
 
 	newVars := []Variable{variable}
@@ -371,7 +532,7 @@ func (v *Variables) Marginal(f *Factor, variable Variable) Factor {
 		fNew.data[oldIndex[idx]] += v
 		f.variables.increment(oldIndex)
 	}
-	return fNew
+	return fNew, nil
 }
 
 // Normalize normalizes a [Factor].
@@ -395,7 +556,21 @@ func (v *Variables) Normalize(f *Factor) Factor {
 
 // NormalizeFor normalizes a [Factor] for a certain [Variable].
 // It also re-arranges the new factor to have the normalized variable as the last one.
+//
+// Panics on invalid arguments. See [Variables.TryNormalizeFor] for an error-returning variant.
 func (v *Variables) NormalizeFor(f *Factor, variable Variable) Factor {
+	fNew, err := v.TryNormalizeFor(f, variable)
+	if err != nil {
+		panic(err)
+	}
+	return fNew
+}
+
+// TryNormalizeFor normalizes a [Factor] for a certain [Variable].
+// It also re-arranges the new factor to have the normalized variable as the last one.
+//
+// Returns an error wrapping [ErrUnknownVariable] if a variable is not in the factor.
+func (v *Variables) TryNormalizeFor(f *Factor, variable Variable) (Factor, error) {
 	idx := -1
 	for i := range f.variables {
 		if f.variables[i].id == variable.id {
@@ -405,7 +580,7 @@ func (v *Variables) NormalizeFor(f *Factor, variable Variable) Factor {
 	}
 
 	if idx < 0 {
-		panic(fmt.Sprintf("variable %d not in this factor", variable.id))
+		return Factor{}, notInFactorError(variable)
 	}
 
 	newVars := make([]Variable, len(f.variables))
@@ -434,7 +609,7 @@ func (v *Variables) NormalizeFor(f *Factor, variable Variable) Factor {
 		}
 	}
 
-	return fNew
+	return fNew, nil
 }
 
 // Invert a [Factor] by applying 1/x for each element (if x != 0).
@@ -450,3 +625,8 @@ func (v *Variables) Invert(f *Factor) Factor {
 
 	return fNew
 }
+
+// notInFactorError creates an error for a variable that is not in a factor.
+func notInFactorError(variable Variable) error {
+	return fmt.Errorf("%w: variable %d not in this factor", ErrUnknownVariable, variable.id)
+}
@@ -337,6 +337,44 @@ func TestVariablesPolicy(t *testing.T) {
 	}, p.Data())
 }
 
+func TestVariablesConstrainedPolicy(t *testing.T) {
+	v := NewVariables()
+
+	v1 := v.AddVariable(0, ChanceNode, 3)
+	v2 := v.AddVariable(1, DecisionNode, 2)
+	v3 := v.AddVariable(2, ChanceNode, 2)
+
+	f := v.CreateFactor([]Variable{v1, v2}, []float64{
+		0.4, 0.6,
+		0.9, 0.1,
+		0.2, 0.8,
+	})
+	allowed := v.CreateFactor([]Variable{v1, v2}, []float64{
+		1, 0,
+		1, 1,
+		1, 1,
+	})
+
+	p := v.ConstrainedPolicy(&f, v2, &allowed)
+	assert.Equal(t, []float64{
+		1, 0,
+		1, 0,
+		0, 1,
+	}, p.Data())
+
+	p = v.ConstrainedPolicy(&f, v2, nil)
+	unconstrained := v.Policy(&f, v2)
+	assert.Equal(t, unconstrained.Data(), p.Data())
+
+	none := v.CreateFactor([]Variable{v2}, []float64{0, 0})
+	_, err := v.TryConstrainedPolicy(&f, v2, &none)
+	assert.ErrorIs(t, err, ErrNoPolicy)
+
+	other := v.CreateFactor([]Variable{v3, v2}, []float64{1, 1, 1, 1})
+	_, err = v.TryConstrainedPolicy(&f, v2, &other)
+	assert.ErrorIs(t, err, ErrUnknownVariable)
+}
+
 func TestVariablesRearrange(t *testing.T) {
 	v := NewVariables()
 
@@ -368,3 +406,54 @@ func TestVariablesRearrange(t *testing.T) {
 	assert.Equal(t, original, f3.Data())
 
 }
+
+func TestVariablesErrors(t *testing.T) {
+	v := NewVariables()
+
+	v1 := v.AddVariable(0, ChanceNode, 2)
+	v2 := v.AddVariable(1, ChanceNode, 3)
+	v3 := v.AddVariable(2, ChanceNode, 2)
+
+	_, err := v.TryAddVariable(0, ChanceNode, 2)
+	assert.ErrorIs(t, err, ErrDuplicateVariable)
+	assert.Panics(t, func() { v.AddVariable(1, ChanceNode, 2) })
+
+	_, err = v.TryCreateFactor([]Variable{v1, v2}, []float64{1, 2, 3})
+	assert.ErrorIs(t, err, ErrTableShape)
+
+	f, err := v.TryCreateFactor([]Variable{v1, v2}, nil)
+	assert.Nil(t, err)
+
+	_, err = v.TryRestrict(&f, v1, 2)
+	assert.ErrorIs(t, err, ErrUnknownOutcome)
+	_, err = v.TryRestrict(&f, v3, 0)
+	assert.ErrorIs(t, err, ErrUnknownVariable)
+
+	_, err = v.TrySumOut(&f, v3)
+	assert.ErrorIs(t, err, ErrUnknownVariable)
+	_, err = v.TryMarginal(&f, v3)
+	assert.ErrorIs(t, err, ErrUnknownVariable)
+	_, err = v.TryNormalizeFor(&f, v3)
+	assert.ErrorIs(t, err, ErrUnknownVariable)
+	_, err = v.TryPolicy(&f, v3)
+	assert.ErrorIs(t, err, ErrUnknownVariable)
+	_, err = v.TryRearrange(&f, []Variable{v1})
+	assert.ErrorIs(t, err, ErrTableShape)
+	_, err = v.TryRearrange(&f, []Variable{v1, v3})
+	assert.ErrorIs(t, err, ErrUnknownVariable)
+}
+
+func TestVariablesDuplicateID(t *testing.T) {
+	v := NewVariables()
+
+	_, err := v.TryAddVariable(0, ChanceNode, 2)
+	assert.Nil(t, err)
+	_, err = v.TryAddVariable(1, ChanceNode, 2)
+	assert.Nil(t, err)
+
+	// IDs were not registered before, so duplicates were accepted
+	_, err = v.TryAddVariable(0, UtilityNode, 1)
+	assert.ErrorIs(t, err, ErrDuplicateVariable)
+	_, err = v.TryAddVariable(1, ChanceNode, 3)
+	assert.ErrorIs(t, err, ErrDuplicateVariable)
+}
@@ -19,6 +19,7 @@ type VE struct {
 	dependencies map[Variable][]Variable
 	factors      map[int]*Factor
 	weights      []float64
+	constraints  map[int]*Factor
 }
 
 // New creates a [VE] instance from the given variables, factors
@@ -38,9 +39,18 @@ func New(variables *Variables, factors []Factor, dependencies map[Variable][]Var
 		dependencies: dependencies,
 		factors:      fac,
 		weights:      weights,
+		constraints:  map[int]*Factor{},
 	}
 }
 
+// Constrain restricts the outcomes of a decision variable when solving policies.
+//
+// Factor allowed contains the decision variable and some of its parents,
+// with non-zero values for allowed outcomes. See [Variables.TryConstrainedPolicy].
+func (ve *VE) Constrain(decision Variable, allowed *Factor) {
+	ve.constraints[decision.id] = allowed
+}
+
 // Variables for the VE.
 func (ve *VE) Variables() *Variables {
 	return ve.variables
@@ -229,19 +239,52 @@ func (ve *VE) summarize() *Factor {
 }
 
 // SolveQuery solves marginal probabilities for the given query variables, and the given evidence.
+//
+// Panics on invalid arguments. See [VE.TrySolveQuery] for an error-returning variant.
 func (ve *VE) SolveQuery(evidence []Evidence, query []Variable) *Factor {
+	f, err := ve.TrySolveQuery(evidence, query)
+	if err != nil {
+		panic(err)
+	}
+	return f
+}
+
+// TrySolveQuery solves marginal probabilities for the given query variables, and the given evidence.
+//
+// Returns an error wrapping [ErrUnknownVariable] or [ErrUnknownOutcome] for invalid evidence or query variables.
+func (ve *VE) TrySolveQuery(evidence []Evidence, query []Variable) (*Factor, error) {
 	return ve.solve(evidence, query, false, nil)
 }
 
-// SolveQuery solves utilities for the given query variables, and the given evidence.
+// SolveUtility solves utilities for the given query variables, and the given evidence.
 //
 // Argument utilityVar can be used to solve for only this variable, dropping all other utilities.
 // Solves total utility if utilityVar is nil.
+//
+// Panics on invalid arguments. See [VE.TrySolveUtility] for an error-returning variant.
 func (ve *VE) SolveUtility(evidence []Evidence, query []Variable, utilityVar *Variable) *Factor {
+	f, err := ve.TrySolveUtility(evidence, query, utilityVar)
+	if err != nil {
+		panic(err)
+	}
+	return f
+}
+
+// TrySolveUtility solves utilities for the given query variables, and the given evidence.
+//
+// Argument utilityVar can be used to solve for only this variable, dropping all other utilities.
+// Solves total utility if utilityVar is nil.
+//
+// Returns an error wrapping [ErrUnknownVariable] or [ErrUnknownOutcome] for invalid evidence or query variables.
+func (ve *VE) TrySolveUtility(evidence []Evidence, query []Variable, utilityVar *Variable) (*Factor, error) {
 	return ve.solve(evidence, query, true, utilityVar)
 }
 
-func (ve *VE) solve(evidence []Evidence, query []Variable, utility bool, utilityVar *Variable) *Factor {
+func (ve *VE) solve(evidence []Evidence, query []Variable, utility bool, utilityVar *Variable) (*Factor, error) {
+	if err := ve.checkArguments(evidence, query, utilityVar); err != nil {
+		return nil, err
+	}
+
 	ve.eliminateEvidence(evidence)
 
 	if utility {
@@ -256,16 +299,61 @@ func (ve *VE) solve(evidence []Evidence, query []Variable, utility bool, utility
 
 	ve.eliminateHidden(evidence, query, false)
 
-	return ve.summarize()
+	return ve.summarize(), nil
+}
+
+// checkArguments checks that evidence and query variables are part of this VE,
+// and that evidence values are in the range of the variable's outcomes.
+func (ve *VE) checkArguments(evidence []Evidence, query []Variable, utilityVar *Variable) error {
+	for _, ev := range evidence {
+		if !ve.contains(ev.Variable) {
+			return fmt.Errorf("%w: evidence variable %d", ErrUnknownVariable, ev.Variable.id)
+		}
+		if ev.Value < 0 || ev.Value >= int(ev.Variable.outcomes) {
+			return fmt.Errorf("%w: observation %d out of range for variable %d with %d possible observation values",
+				ErrUnknownOutcome, ev.Value, ev.Variable.id, ev.Variable.outcomes)
+		}
+	}
+	for _, q := range query {
+		if !ve.contains(q) {
+			return fmt.Errorf("%w: query variable %d", ErrUnknownVariable, q.id)
+		}
+	}
+	if utilityVar != nil && !ve.contains(*utilityVar) {
+		return fmt.Errorf("%w: utility variable %d", ErrUnknownVariable, utilityVar.id)
+	}
+	return nil
+}
+
+// contains checks whether the variable belongs to this VE.
+func (ve *VE) contains(v Variable) bool {
+	return int(v.index) < len(ve.variables.variables) && ve.variables.variables[v.index].id == v.id
 }
 
 // SolvePolicies solves decision policies.
 //
 // Solves only the last decision if single is true.
+// Returns nil if there are no unsolved decisions.
+//
+// Panics if no policy can be derived for a decision. See [VE.TrySolvePolicies] for an error-returning variant.
 func (ve *VE) SolvePolicies(single bool) map[Variable][2]*Factor {
+	policies, err := ve.TrySolvePolicies(single)
+	if err != nil {
+		panic(err)
+	}
+	return policies
+}
+
+// TrySolvePolicies solves decision policies.
+//
+// Solves only the last decision if single is true.
+// Returns nil if there are no unsolved decisions.
+//
+// Returns an error wrapping [ErrNoPolicy] if no policy can be derived for a decision.
+func (ve *VE) TrySolvePolicies(single bool) (map[Variable][2]*Factor, error) {
 	decisions := ve.getDecisions()
 	if len(decisions) == 0 {
-		return nil
+		return nil, nil
 	}
 
 	ve.sumUtilities()
@@ -275,7 +363,7 @@ func (ve *VE) SolvePolicies(single bool) map[Variable][2]*Factor {
 	return ve.solvePolicies(decisions, single)
 }
 
-func (ve *VE) solvePolicies(decisions []Variable, single bool) map[Variable][2]*Factor {
+func (ve *VE) solvePolicies(decisions []Variable, single bool) (map[Variable][2]*Factor, error) {
 	policies := map[Variable][2]*Factor{}
 	factors := []*Factor{}
 	for i := len(decisions) - 1; i >= 0; i-- {
@@ -289,7 +377,7 @@ func (ve *VE) solvePolicies(decisions []Variable, single bool) map[Variable][2]*
 		}*/
 
 		if len(factors) == 0 {
-			panic(fmt.Sprintf("found no factors containing variable %d and its parents", dec.id))
+			return nil, fmt.Errorf("%w: found no factors containing variable %d and its parents", ErrNoPolicy, dec.id)
 		}
 
 		// TODO: check that multiplying when multiple factors are remaining is correct!
@@ -307,7 +395,22 @@ func (ve *VE) solvePolicies(decisions []Variable, single bool) map[Variable][2]*
 		fmt.Println("Factor product")
 		fmt.Println(fac)*/
 
-		policy := ve.variables.Policy(fac, dec)
+		allowed := ve.constraints[dec.id]
+		if allowed != nil {
+			// extend the factor to all variables of the constraint
+			ones := make([]float64, len(allowed.data))
+			for i := range ones {
+				ones[i] = 1
+			}
+			scope := ve.variables.CreateFactor(allowed.variables, ones)
+			f := ve.variables.Product(fac, &scope)
+			fac = &f
+		}
+
+		policy, err := ve.variables.TryConstrainedPolicy(fac, dec, allowed)
+		if err != nil {
+			return nil, err
+		}
 
 		policies[dec] = [2]*Factor{fac, &policy}
 		ve.factors[policy.id] = &policy
@@ -322,7 +425,7 @@ func (ve *VE) solvePolicies(decisions []Variable, single bool) map[Variable][2]*
 		}
 	}
 
-	return policies
+	return policies, nil
 }
 
 func (ve *VE) findDecisionFactors(decision Variable, result []*Factor) []*Factor {
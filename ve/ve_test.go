@@ -334,3 +334,36 @@ func TestSortDecisions(t *testing.T) {
 
 	assert.Equal(t, []Variable{d1, d2, d3}, ve.getDecisions())
 }
+
+func TestVEErrors(t *testing.T) {
+	vars := NewVariables()
+
+	rain := vars.AddVariable(0, ChanceNode, 2)
+	sprinkler := vars.AddVariable(1, ChanceNode, 2)
+
+	fRain := vars.CreateFactor([]Variable{rain}, []float64{0.2, 0.8})
+	fSprinkler := vars.CreateFactor([]Variable{rain, sprinkler}, []float64{0.01, 0.99, 0.2, 0.8})
+
+	other := NewVariables()
+	snow := other.AddVariable(5, ChanceNode, 2)
+
+	ve := New(vars, []Factor{fRain, fSprinkler}, nil, nil)
+	_, err := ve.TrySolveQuery([]Evidence{{Variable: rain, Value: 2}}, []Variable{sprinkler})
+	assert.ErrorIs(t, err, ErrUnknownOutcome)
+
+	ve = New(vars, []Factor{fRain, fSprinkler}, nil, nil)
+	_, err = ve.TrySolveQuery(nil, []Variable{snow})
+	assert.ErrorIs(t, err, ErrUnknownVariable)
+
+	ve = New(vars, []Factor{fRain, fSprinkler}, nil, nil)
+	_, err = ve.TrySolveUtility(nil, nil, &snow)
+	assert.ErrorIs(t, err, ErrUnknownVariable)
+
+	ve = New(vars, []Factor{fRain, fSprinkler}, nil, nil)
+	assert.Panics(t, func() { ve.SolveQuery(nil, []Variable{snow}) })
+
+	ve = New(vars, []Factor{fRain, fSprinkler}, nil, nil)
+	policies, err := ve.TrySolvePolicies(false)
+	assert.Nil(t, err)
+	assert.Nil(t, policies)
+}
@@ -32,6 +32,8 @@ type definitionXml struct {
 }
 
 // FromBIFXML creates a [Network] from XML. See also [FromFile].
+//
+// Syntax errors are returned as [*ParseError].
 func FromBIFXML(content []byte) (*Network, error) {
 	reader := bytes.NewReader(content)
 	decoder := xml.NewDecoder(reader)
@@ -40,7 +42,7 @@ func FromBIFXML(content []byte) (*Network, error) {
 
 	err := decoder.Decode(&bifNet)
 	if err != nil {
-		return nil, err
+		return nil, xmlError(err, decoder)
 	}
 
 	defs := map[string]*definitionXml{}
@@ -52,7 +54,10 @@ func FromBIFXML(content []byte) (*Network, error) {
 	variables := make([]Variable, len(bifNet.Network.Variables))
 	factors := []Factor{}
 	for i, variable := range bifNet.Network.Variables {
-		def := defs[variable.Name]
+		def, ok := defs[variable.Name]
+		if !ok {
+			return nil, newVariableError(variable.Name, ErrTableShape, "no definition for node '%s'", variable.Name)
+		}
 
 		columns := len(variable.Outcomes)
 		tableValues := strings.Fields(def.Table)
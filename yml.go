@@ -2,52 +2,107 @@ package bbn
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
+	"slices"
 	"strconv"
 	"strings"
 
+	"github.com/mlange-42/bbn/equation"
 	"github.com/mlange-42/bbn/logic"
+	"github.com/mlange-42/bbn/noisy"
+	"github.com/mlange-42/bbn/ordinal"
+	"github.com/mlange-42/bbn/utility"
 	"github.com/mlange-42/bbn/ve"
 	"gopkg.in/yaml.v3"
 )
 
 const (
-	ChanceNodeType   = "nature"
-	DecisionNodeType = "decision"
-	UtilityNodeType  = "utility"
+	ChanceNodeType     = "nature"
+	DecisionNodeType   = "decision"
+	UtilityNodeType    = "utility"
+	ContinuousNodeType = "continuous"
 )
 
 var nodeTypes = map[string]ve.NodeType{
-	"":               ve.ChanceNode,
-	ChanceNodeType:   ve.ChanceNode,
-	DecisionNodeType: ve.DecisionNode,
-	UtilityNodeType:  ve.UtilityNode,
+	"":                 ve.ChanceNode,
+	ChanceNodeType:     ve.ChanceNode,
+	DecisionNodeType:   ve.DecisionNode,
+	UtilityNodeType:    ve.UtilityNode,
+	ContinuousNodeType: ve.ContinuousNode,
 }
 
 var nodeTypeNames = map[ve.NodeType]string{
-	ve.ChanceNode:   "",
-	ve.DecisionNode: DecisionNodeType,
-	ve.UtilityNode:  UtilityNodeType,
+	ve.ChanceNode:     "",
+	ve.DecisionNode:   DecisionNodeType,
+	ve.UtilityNode:    UtilityNodeType,
+	ve.ContinuousNode: ContinuousNodeType,
 }
 
 type variableYaml struct {
-	Variable string      // Name of the node.
-	Given    []string    `yaml:",flow,omitempty"`
-	Type     string      `yaml:",omitempty"`      // Type of the node [nature, decision, utility]
-	Outcomes []string    `yaml:",flow"`           // Names of the node's possible states.
-	Position [2]int      `yaml:",flow"`           // Coordinates for visualization, optional.
-	Color    string      `yaml:",omitempty"`      // Node color, optional.
-	Logic    string      `yaml:",omitempty"`      // Logic operations, alternative to a table
-	Table    [][]float64 `yaml:",flow,omitempty"` // Table with the variable's factor
+	Variable  string      // Name of the node.
+	Given     []string    `yaml:",flow,omitempty"`
+	Type      string      `yaml:",omitempty"`      // Type of the node [nature, decision, utility, continuous]
+	Outcomes  []string    `yaml:",flow"`           // Names of the node's possible states.
+	Bins      []binEdge   `yaml:",flow,omitempty"` // Edges of interval outcomes, optional.
+	Position  [2]int      `yaml:",flow"`           // Coordinates for visualization, optional.
+	Color     string      `yaml:",omitempty"`      // Node color, optional.
+	Logic     string      `yaml:",omitempty"`      // Logic operations, alternative to a table
+	Noisy     string      `yaml:",omitempty"`      // Canonical model [or, and, max], alternative to a table
+	Links     []floats    `yaml:",flow,omitempty"` // Link probabilities of the canonical model
+	Leak      floats      `yaml:",flow,omitempty"` // Leak probability of the canonical model
+	Function  string      `yaml:",omitempty"`      // Ordinal function [sum, min, max, mean, threshold], alternative to a table
+	Weights   []float64   `yaml:",flow,omitempty"` // Parent weights of the ordinal function, optional
+	Threshold float64     `yaml:",omitempty"`      // Threshold of the ordinal threshold function
+	Equation  string      `yaml:",omitempty"`      // Equation over parents, alternative to a table
+	Table     [][]float64 `yaml:",flow,omitempty"` // Table with the variable's factor
+	Allowed   [][]bool    `yaml:",flow,omitempty"` // Allowed outcomes of a decision, by combination of parent outcomes, optional
+
+	Utility       string       `yaml:",omitempty"`               // Utility function [multiplicative, exponential, piecewise] of a total utility node
+	Scaling       float64      `yaml:",omitempty"`               // Scaling constant of the multiplicative utility function
+	RiskTolerance float64      `yaml:"risk-tolerance,omitempty"` // Risk tolerance of the exponential utility function
+	Points        [][2]float64 `yaml:",flow,omitempty"`          // Points of the piecewise-linear utility function
+}
+
+// floats is a list of floats in YAML, which can also be given as a single scalar value.
+type floats []float64
+
+// UnmarshalYAML implements [yaml.Unmarshaler].
+func (f *floats) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		var v float64
+		if err := node.Decode(&v); err != nil {
+			return err
+		}
+		*f = floats{v}
+		return nil
+	}
+	var v []float64
+	if err := node.Decode(&v); err != nil {
+		return err
+	}
+	*f = v
+	return nil
+}
+
+// MarshalYAML implements [yaml.Marshaler].
+func (f floats) MarshalYAML() (interface{}, error) {
+	if len(f) == 1 {
+		return f[0], nil
+	}
+	return []float64(f), nil
 }
 
 type networkYaml struct {
 	Name      string
 	Info      string `yaml:",omitempty"`
+	LIMID     bool   `yaml:"limid,omitempty"` // Limited-memory influence diagram, optional
 	Variables []variableYaml
 }
 
-// FromBIFXML creates a [Network] from YAML. See also [FromFile].
+// FromYAML creates a [Network] from YAML. See also [FromFile].
+//
+// Syntax errors and errors in variable definitions are returned as [*ParseError].
 func FromYAML(content []byte) (*Network, error) {
 	reader := bytes.NewReader(content)
 	decoder := yaml.NewDecoder(reader)
@@ -56,42 +111,248 @@ func FromYAML(content []byte) (*Network, error) {
 	net := networkYaml{}
 	err := decoder.Decode(&net)
 	if err != nil {
-		return nil, err
+		return nil, yamlError(err)
 	}
+	return fromNetworkYaml(&net, yamlVariablePositions(content, "variables"))
+}
 
+// fromNetworkYaml creates a [Network] from its YAML representation.
+func fromNetworkYaml(net *networkYaml, positions yamlPositions) (*Network, error) {
 	variables := make([]Variable, len(net.Variables))
 	factors := []Factor{}
 	for i, v := range net.Variables {
 		tp, ok := nodeTypes[v.Type]
 		if !ok {
-			return nil, fmt.Errorf("unknown node type %s", v.Type)
+			return nil, positions.error(i, fmt.Errorf("unknown node type %s", v.Type))
+		}
+		bins := fromBinEdges(v.Bins)
+		outcomes := v.Outcomes
+		if len(outcomes) == 0 {
+			outcomes = BinLabels(bins)
 		}
 		variables[i] = Variable{
 			Name:     v.Variable,
 			NodeType: tp,
-			Outcomes: v.Outcomes,
+			Outcomes: outcomes,
+			Bins:     bins,
 			Position: v.Position,
 			Color:    v.Color,
 		}
 
 		table, err := toTable(&v)
 		if err != nil {
-			return nil, err
+			return nil, positions.error(i, err)
+		}
+		model, err := toNoisy(&v)
+		if err != nil {
+			return nil, positions.error(i, err)
+		}
+		fn, err := toUtility(&v)
+		if err != nil {
+			return nil, positions.error(i, err)
+		}
+		allowed, err := toAllowed(&v, len(outcomes))
+		if err != nil {
+			return nil, positions.error(i, err)
 		}
 
 		factors = append(factors, Factor{
-			For:   v.Variable,
-			Given: v.Given,
-			Table: table,
+			For:     v.Variable,
+			Given:   v.Given,
+			Table:   table,
+			Noisy:   model,
+			Utility: fn,
+			Allowed: allowed,
 		})
 	}
+	if err := deferredTables(net.Variables, variables, factors, positions); err != nil {
+		return nil, err
+	}
+
+	network, err := New(net.Name, net.Info, variables, factors)
+	if err != nil {
+		return nil, err
+	}
+	network.limid = net.LIMID
+	return network, nil
+}
+
+type dbnYaml struct {
+	Name      string
+	Info      string `yaml:",omitempty"`
+	Variables []variableYaml
+	Prior     []variableYaml `yaml:",omitempty"`
+}
+
+// DBNFromYAML creates a [DBN] from YAML. See also [DBNFromFile].
+//
+// The format is the same as for a [Network], with variables defining the transition slice.
+// Parents in the previous time slice are referred to like "Rain@t-1".
+// Variables with such parents require a definition for the first time step under key 'prior',
+// which can omit outcomes, bins, position and color.
+//
+// Syntax errors and errors in variable definitions are returned as [*ParseError].
+func DBNFromYAML(content []byte) (*DBN, error) {
+	reader := bytes.NewReader(content)
+	decoder := yaml.NewDecoder(reader)
+	decoder.KnownFields(true)
+
+	dbn := dbnYaml{}
+	err := decoder.Decode(&dbn)
+	if err != nil {
+		return nil, yamlError(err)
+	}
+	positions := yamlVariablePositions(content, "variables")
+
+	prior, priorPositions, err := dbnPriorYaml(&dbn, positions, yamlVariablePositions(content, "prior"))
+	if err != nil {
+		return nil, err
+	}
+	priorNet, err := fromNetworkYaml(prior, priorPositions)
+	if err != nil {
+		return nil, err
+	}
+	transitionNet, err := fromNetworkYaml(dbnTransitionYaml(&dbn), positions)
+	if err != nil {
+		return nil, err
+	}
+	return NewDBN(priorNet, transitionNet)
+}
 
-	return New(net.Name, net.Info, variables, factors)
+// dbnPriorYaml creates the network definition for the first time step of a DBN.
+// Returns the definition and the positions of its variables.
+func dbnPriorYaml(dbn *dbnYaml, positions, priorPositions yamlPositions) (*networkYaml, yamlPositions, error) {
+	defs := map[string]int{}
+	for i, p := range dbn.Prior {
+		if _, ok := defs[p.Variable]; ok {
+			return nil, nil, priorPositions.error(i, fmt.Errorf("duplicate prior definition for variable %s", p.Variable))
+		}
+		if !slices.ContainsFunc(dbn.Variables, func(v variableYaml) bool { return v.Variable == p.Variable }) {
+			return nil, nil, priorPositions.error(i, fmt.Errorf("prior definition for unknown variable %s", p.Variable))
+		}
+		defs[p.Variable] = i
+	}
+
+	net := networkYaml{Name: dbn.Name, Info: dbn.Info, Variables: make([]variableYaml, len(dbn.Variables))}
+	netPositions := make(yamlPositions, len(dbn.Variables))
+	for i, v := range dbn.Variables {
+		j, ok := defs[v.Variable]
+		if !ok {
+			if slices.ContainsFunc(v.Given, func(g string) bool { return strings.HasSuffix(g, previousSuffix) }) {
+				return nil, nil, positions.error(i, fmt.Errorf("variable %s has parents in the previous time slice, and requires a definition in 'prior'", v.Variable))
+			}
+			net.Variables[i], netPositions[i] = v, positions.at(i)
+			continue
+		}
+		p := dbn.Prior[j]
+		if len(p.Outcomes) == 0 && len(p.Bins) == 0 {
+			p.Outcomes, p.Bins = v.Outcomes, v.Bins
+		}
+		if p.Type == "" {
+			p.Type = v.Type
+		}
+		if p.Position == [2]int{} {
+			p.Position = v.Position
+		}
+		if p.Color == "" {
+			p.Color = v.Color
+		}
+		net.Variables[i], netPositions[i] = p, priorPositions.at(j)
+	}
+	return &net, netPositions, nil
+}
+
+// dbnTransitionYaml creates the network definition for further time steps of a DBN.
+// Variables of the previous time slice are appended as root nodes.
+func dbnTransitionYaml(dbn *dbnYaml) *networkYaml {
+	net := networkYaml{Name: dbn.Name, Info: dbn.Info, Variables: slices.Clone(dbn.Variables)}
+	for _, v := range dbn.Variables {
+		for _, g := range v.Given {
+			name, ok := strings.CutSuffix(g, previousSuffix)
+			if !ok || slices.ContainsFunc(net.Variables, func(v variableYaml) bool { return v.Variable == g }) {
+				continue
+			}
+			idx := slices.IndexFunc(dbn.Variables, func(v variableYaml) bool { return v.Variable == name })
+			if idx < 0 {
+				continue // reported as unknown parent
+			}
+			current := dbn.Variables[idx]
+			outcomes := len(current.Outcomes)
+			if outcomes == 0 {
+				outcomes = len(current.Bins) - 1
+			}
+			uniform := make([]float64, max(outcomes, 1))
+			for i := range uniform {
+				uniform[i] = 1
+			}
+			net.Variables = append(net.Variables, variableYaml{
+				Variable: g,
+				Type:     current.Type,
+				Outcomes: current.Outcomes,
+				Bins:     current.Bins,
+				Position: current.Position,
+				Table:    [][]float64{uniform},
+			})
+		}
+	}
+	return &net
+}
+
+// yamlPositions holds line and column of the variable definitions in a YAML file.
+type yamlPositions [][2]int
+
+// yamlVariablePositions extracts the positions of variable definitions under the given key from YAML content.
+func yamlVariablePositions(content []byte, key string) yamlPositions {
+	root := yaml.Node{}
+	if err := yaml.Unmarshal(content, &root); err != nil || len(root.Content) == 0 {
+		return nil
+	}
+	doc := root.Content[0]
+	if doc.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(doc.Content); i += 2 {
+		if doc.Content[i].Value != key {
+			continue
+		}
+		seq := doc.Content[i+1]
+		positions := make(yamlPositions, len(seq.Content))
+		for j, v := range seq.Content {
+			positions[j] = [2]int{v.Line, v.Column}
+		}
+		return positions
+	}
+	return nil
+}
+
+// at returns the position of the variable at the given index, or zeros if not available.
+func (p yamlPositions) at(index int) [2]int {
+	if index >= len(p) {
+		return [2]int{}
+	}
+	return p[index]
+}
+
+// error wraps an error for the variable at the given index into a [*ParseError].
+func (p yamlPositions) error(index int, err error) error {
+	if index >= len(p) {
+		return &ParseError{Err: err}
+	}
+	return &ParseError{Line: p[index][0], Column: p[index][1], Err: err}
 }
 
 func toTable(v *variableYaml) ([]float64, error) {
-	if len(v.Table) > 0 && v.Logic != "" {
-		return nil, fmt.Errorf("node can only have one of 'table' or 'logic'")
+	if err := checkDefinitions(v); err != nil {
+		return nil, err
+	}
+	if v.Function != "" {
+		// check early, table is generated when all variables are known
+		_, err := toFunction(v)
+		return nil, err
+	}
+	if v.Equation != "" {
+		_, err := equation.Parse(v.Equation)
+		return nil, err
 	}
 
 	if v.Logic == "" {
@@ -105,10 +366,58 @@ func toTable(v *variableYaml) ([]float64, error) {
 		return table, nil
 	}
 
+	if isExpression(v.Logic) {
+		// check syntax early, table is generated when all variables are known
+		if _, err := logic.ParseExpression(v.Logic); err != nil {
+			if parts := strings.Fields(v.Logic); len(parts) > 1 && areIntegers(parts[1:]) {
+				// operator name with integer arguments
+				return nil, unknownOperatorError(parts[0])
+			}
+			return nil, err
+		}
+		return nil, nil
+	}
+	return operatorTable(v)
+}
+
+// toAllowed flattens the allowed outcomes of a decision variable.
+func toAllowed(v *variableYaml, columns int) ([]bool, error) {
+	if len(v.Allowed) == 0 {
+		return nil, nil
+	}
+	allowed := make([]bool, 0, len(v.Allowed)*columns)
+	for i, row := range v.Allowed {
+		if len(row) != columns {
+			return nil, fmt.Errorf("row %d of 'allowed' has %d entries, but variable %s has %d outcomes", i, len(row), v.Variable, columns)
+		}
+		allowed = append(allowed, row...)
+	}
+	return allowed, nil
+}
+
+// checkDefinitions checks that a variable has at most one of table, logic, canonical model or ordinal function.
+func checkDefinitions(v *variableYaml) error {
+	definitions := 0
+	for _, d := range []bool{len(v.Table) > 0, v.Logic != "", v.Noisy != "", v.Function != "", v.Equation != ""} {
+		if d {
+			definitions++
+		}
+	}
+	if definitions > 1 {
+		return fmt.Errorf("node can only have one of 'table', 'logic', 'noisy', 'function' or 'equation'")
+	}
+	if v.Function == "" && (len(v.Weights) > 0 || v.Threshold != 0) {
+		return fmt.Errorf("'weights' and 'threshold' require an ordinal function in 'function'")
+	}
+	return nil
+}
+
+// operatorTable creates the table of a variable from a logic operator name, followed by integer arguments.
+func operatorTable(v *variableYaml) ([]float64, error) {
 	parts := strings.Split(v.Logic, " ")
 	l, ok := logic.Get(strings.ToLower(parts[0]))
 	if !ok {
-		return nil, fmt.Errorf("unknown logic operator %s; valid operators are e.g.: not, and, or, xor, if-then, if-not-then, if-then-not, if-not-then-not, not-and, etc", v.Logic)
+		return nil, unknownOperatorError(parts[0])
 	}
 	args := make([]int, len(parts)-1)
 	for i := 1; i < len(parts); i++ {
@@ -130,10 +439,272 @@ func toTable(v *variableYaml) ([]float64, error) {
 	return table, nil
 }
 
+func unknownOperatorError(name string) error {
+	return fmt.Errorf("unknown logic operator %s; valid operators are: %s", name, strings.Join(logic.Names(), ", "))
+}
+
+// isExpression checks whether a logic definition is a boolean expression (see [logic.Expression]),
+// rather than an operator name followed by integer arguments.
+func isExpression(def string) bool {
+	parts := strings.Fields(def)
+	if len(parts) == 0 {
+		return false
+	}
+	if _, ok := logic.Get(strings.ToLower(parts[0])); !ok {
+		return true
+	}
+	return !areIntegers(parts[1:])
+}
+
+func areIntegers(values []string) bool {
+	for _, v := range values {
+		if _, err := strconv.Atoi(v); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// deferredTables generates tables for variables with logic expressions, ordinal functions or equations, called from [FromYAML].
+// These require the outcomes of the parents, and are thus generated after all variables are known.
+func deferredTables(defs []variableYaml, variables []Variable, factors []Factor, positions yamlPositions) error {
+	outcomes := make(map[string][]string, len(variables))
+	for _, v := range variables {
+		outcomes[v.Name] = v.Outcomes
+	}
+	for i, v := range defs {
+		var table []float64
+		var err error
+		if v.Function != "" {
+			fn, _ := toFunction(&v)
+			table, err = functionTable(&variables[i], fn, v.Given, outcomes)
+		} else if v.Equation != "" {
+			table, err = equationTable(&variables[i], v.Equation, v.Given, variables)
+		} else if isExpression(v.Logic) {
+			table, err = expressionTable(&variables[i], v.Logic, v.Given, outcomes)
+		} else {
+			continue
+		}
+		if err != nil {
+			return positions.error(i, err)
+		}
+		factors[i].Table = table
+	}
+	return nil
+}
+
+// expressionTable generates the table for a variable from a logic expression.
+func expressionTable(v *Variable, expr string, given []string, outcomes map[string][]string) ([]float64, error) {
+	if len(v.Outcomes) != 2 {
+		return nil, newVariableError(v.Name, ErrTableShape, "logic expression requires variable %s to have 2 outcomes; got %d", v.Name, len(v.Outcomes))
+	}
+	e, err := logic.ParseExpression(expr)
+	if err != nil {
+		return nil, err
+	}
+	parents, err := parentOutcomes(v, given, outcomes)
+	if err != nil {
+		return nil, err
+	}
+	return e.Table(given, parents)
+}
+
+// parentOutcomes collects the outcomes of the given parents of a variable.
+func parentOutcomes(v *Variable, given []string, outcomes map[string][]string) ([][]string, error) {
+	result := make([][]string, len(given))
+	for i, g := range given {
+		o, ok := outcomes[g]
+		if !ok {
+			return nil, newVariableError(g, ErrUnknownVariable, "parent variable %s of %s not found", g, v.Name)
+		}
+		result[i] = o
+	}
+	return result, nil
+}
+
+// toFunction creates the ordinal function of a variable.
+func toFunction(v *variableYaml) (ordinal.Function, error) {
+	name := strings.ToLower(v.Function)
+	if name != "threshold" && v.Threshold != 0 {
+		return nil, fmt.Errorf("'threshold' requires ordinal function 'threshold'")
+	}
+	if (name == "min" || name == "max") && v.Weights != nil {
+		return nil, fmt.Errorf("ordinal function %s does not support weights", name)
+	}
+	switch name {
+	case "sum":
+		return &ordinal.Sum{Weights: v.Weights}, nil
+	case "mean":
+		return &ordinal.Mean{Weights: v.Weights}, nil
+	case "min":
+		return &ordinal.Min{}, nil
+	case "max":
+		return &ordinal.Max{}, nil
+	case "threshold":
+		return &ordinal.Threshold{Weights: v.Weights, Threshold: v.Threshold}, nil
+	default:
+		return nil, fmt.Errorf("unknown ordinal function %s; valid functions are: sum, min, max, mean, threshold", v.Function)
+	}
+}
+
+// functionTable generates the table for a chance variable from an ordinal function.
+func functionTable(v *Variable, fn ordinal.Function, given []string, outcomes map[string][]string) ([]float64, error) {
+	if v.NodeType != ve.ChanceNode {
+		return nil, newVariableError(v.Name, ErrTableShape, "ordinal functions are only supported for chance variables; got %s", v.Name)
+	}
+	parents, err := parentOutcomes(v, given, outcomes)
+	if err != nil {
+		return nil, err
+	}
+	table, err := fn.Table(v.Outcomes, parents)
+	if err != nil {
+		return nil, newVariableError(v.Name, ErrTableShape, "ordinal function for %s: %s", v.Name, err.Error())
+	}
+	return table, nil
+}
+
+// equationTable generates the table for a chance variable from an equation.
+func equationTable(v *Variable, expr string, given []string, variables []Variable) ([]float64, error) {
+	if v.NodeType != ve.ChanceNode {
+		return nil, newVariableError(v.Name, ErrTableShape, "equations are only supported for chance variables; got %s", v.Name)
+	}
+	eq, err := equation.Parse(expr)
+	if err != nil {
+		return nil, err
+	}
+	parents := make([]equation.Variable, len(given))
+	for i, g := range given {
+		idx := slices.IndexFunc(variables, func(v Variable) bool { return v.Name == g })
+		if idx < 0 {
+			return nil, newVariableError(g, ErrUnknownVariable, "parent variable %s of %s not found", g, v.Name)
+		}
+		parents[i] = equation.Variable{Name: g, Outcomes: variables[idx].Outcomes, Bins: variables[idx].Bins}
+	}
+	table, err := eq.Table(equation.Variable{Name: v.Name, Outcomes: v.Outcomes, Bins: v.Bins}, parents)
+	if err != nil {
+		var syntaxErr *equation.SyntaxError
+		if errors.As(err, &syntaxErr) {
+			return nil, err
+		}
+		return nil, newVariableError(v.Name, ErrTableShape, "%s: %s", v.Name, err.Error())
+	}
+	return table, nil
+}
+
+// toNoisy creates the canonical model of a variable, if any.
+func toNoisy(v *variableYaml) (noisy.Model, error) {
+	if v.Noisy == "" {
+		if len(v.Links) > 0 || len(v.Leak) > 0 {
+			return nil, fmt.Errorf("'links' and 'leak' require a canonical model in 'noisy'")
+		}
+		return nil, nil
+	}
+	switch strings.ToLower(v.Noisy) {
+	case "or", "and":
+		links := make([]float64, len(v.Links))
+		for i, l := range v.Links {
+			if len(l) != 1 {
+				return nil, fmt.Errorf("noisy-%s requires a single link probability per parent", v.Noisy)
+			}
+			links[i] = l[0]
+		}
+		if len(v.Leak) > 1 {
+			return nil, fmt.Errorf("noisy-%s requires a single leak probability", v.Noisy)
+		}
+		leak := 0.0
+		if len(v.Leak) == 1 {
+			leak = v.Leak[0]
+		}
+		if strings.ToLower(v.Noisy) == "or" {
+			return &noisy.Or{Links: links, Leak: leak}, nil
+		}
+		return &noisy.And{Links: links, Leak: leak}, nil
+	case "max":
+		links := make([][]float64, len(v.Links))
+		for i, l := range v.Links {
+			links[i] = l
+		}
+		return &noisy.Max{Links: links, Leak: v.Leak}, nil
+	default:
+		return nil, fmt.Errorf("unknown canonical model %s; valid models are: or, and, max", v.Noisy)
+	}
+}
+
+// fromNoisy sets the canonical model of a variable in YAML.
+// Returns false if the model type is not supported by YAML.
+func fromNoisy(model noisy.Model, v *variableYaml) bool {
+	switch m := model.(type) {
+	case *noisy.Or:
+		v.Noisy, v.Links, v.Leak = "or", toFloats(m.Links), floats{m.Leak}
+	case *noisy.And:
+		v.Noisy, v.Links, v.Leak = "and", toFloats(m.Links), floats{m.Leak}
+	case *noisy.Max:
+		v.Noisy, v.Leak = "max", m.Leak
+		v.Links = make([]floats, len(m.Links))
+		for i, l := range m.Links {
+			v.Links[i] = l
+		}
+	default:
+		return false
+	}
+	return true
+}
+
+// toUtility creates the utility function of a variable, if any.
+func toUtility(v *variableYaml) (utility.Function, error) {
+	switch strings.ToLower(v.Utility) {
+	case "":
+		if v.Scaling != 0 || v.RiskTolerance != 0 || len(v.Points) > 0 {
+			return nil, fmt.Errorf("'scaling', 'risk-tolerance' and 'points' require a utility function in 'utility'")
+		}
+		return nil, nil
+	case "multiplicative":
+		return &utility.Multiplicative{Scaling: v.Scaling}, nil
+	case "exponential":
+		return &utility.Exponential{RiskTolerance: v.RiskTolerance}, nil
+	case "piecewise":
+		fn := utility.Piecewise{X: make([]float64, len(v.Points)), Y: make([]float64, len(v.Points))}
+		for i, p := range v.Points {
+			fn.X[i], fn.Y[i] = p[0], p[1]
+		}
+		return &fn, nil
+	default:
+		return nil, fmt.Errorf("unknown utility function %s; valid functions are: multiplicative, exponential, piecewise", v.Utility)
+	}
+}
+
+// fromUtility sets the utility function of a variable in YAML.
+// Returns false if the function type is not supported by YAML.
+func fromUtility(fn utility.Function, v *variableYaml) bool {
+	switch f := fn.(type) {
+	case *utility.Multiplicative:
+		v.Utility, v.Scaling = "multiplicative", f.Scaling
+	case *utility.Exponential:
+		v.Utility, v.RiskTolerance = "exponential", f.RiskTolerance
+	case *utility.Piecewise:
+		v.Utility = "piecewise"
+		v.Points = make([][2]float64, len(f.X))
+		for i := range f.X {
+			v.Points[i] = [2]float64{f.X[i], f.Y[i]}
+		}
+	default:
+		return false
+	}
+	return true
+}
+
+func toFloats(values []float64) []floats {
+	result := make([]floats, len(values))
+	for i, v := range values {
+		result[i] = floats{v}
+	}
+	return result
+}
+
 func ToYAML(network *Network) ([]byte, error) {
 	variables := make([]variableYaml, len(network.variables))
 	for i, v := range network.variables {
-		cols := len(v.Outcomes)
+		cols := v.Factor.columns
 		table := make([][]float64, len(v.Factor.Table)/cols)
 
 		for i := range table {
@@ -145,13 +716,24 @@ func ToYAML(network *Network) ([]byte, error) {
 			Given:    v.Factor.Given,
 			Type:     nodeTypeNames[v.NodeType],
 			Outcomes: v.Outcomes,
+			Bins:     toBinEdges(v.Bins),
 			Position: v.Position,
 			Table:    table,
 		}
+		if v.Factor.Noisy != nil && fromNoisy(v.Factor.Noisy, &variables[i]) {
+			variables[i].Table = nil
+		}
+		if v.Factor.Utility != nil && !fromUtility(v.Factor.Utility, &variables[i]) {
+			return nil, newVariableError(v.Name, ErrUnsupported, "utility function of type %T for %s can't be written to YAML", v.Factor.Utility, v.Name)
+		}
+		for j := 0; j < len(v.Factor.Allowed); j += cols {
+			variables[i].Allowed = append(variables[i].Allowed, v.Factor.Allowed[j:j+cols])
+		}
 	}
 
 	net := networkYaml{
 		Name:      network.Name(),
+		LIMID:     network.limid,
 		Variables: variables,
 	}
 
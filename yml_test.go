@@ -2,8 +2,10 @@ package bbn
 
 import (
 	"fmt"
+	"math"
 	"testing"
 
+	"github.com/mlange-42/bbn/logic"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -136,3 +138,263 @@ variables:
 		"Sprinkler": {1, 0},
 	}, result)
 }
+
+func TestFromYAMLExpression(t *testing.T) {
+	net, err := FromFile("_examples/logic/knights-expression.yml")
+	assert.Nil(t, err)
+	assert.Equal(t, []float64{0, 1, 0, 1, 1, 0, 0, 1}, net.variables[2].Factor.Table)
+
+	yml := `name: Test
+variables:
+- variable: A
+  outcomes: [yes, no]
+  table: [[1, 1]]
+- variable: B
+  given: [A]
+  outcomes: [yes, no]
+  logic: A and C
+`
+	_, err = FromYAML([]byte(yml))
+	var parseErr *ParseError
+	assert.ErrorAs(t, err, &parseErr)
+	assert.Equal(t, 6, parseErr.Line)
+	assert.Contains(t, err.Error(), "unknown parent C")
+	assert.Contains(t, err.Error(), "position 7")
+
+	_, err = FromYAML([]byte(`name: Test
+variables:
+- variable: A
+  outcomes: [yes, no]
+  table: [[1, 1]]
+- variable: B
+  given: [A]
+  outcomes: [yes, no]
+  logic: A and (
+`))
+	assert.ErrorAs(t, err, &parseErr)
+	assert.Contains(t, err.Error(), "end of expression")
+
+	_, err = FromYAML([]byte(`name: Test
+variables:
+- variable: A
+  outcomes: [yes, no]
+  table: [[1, 1]]
+- variable: B
+  given: [A]
+  outcomes: [x, y, z]
+  logic: not A
+`))
+	assert.ErrorIs(t, err, ErrTableShape)
+}
+
+func TestFromYAMLFunction(t *testing.T) {
+	net, err := FromFile("_examples/bbn/dice.yml")
+	assert.Nil(t, err)
+
+	result, _, err := net.SolveQuery(nil, []string{"Sum"}, false)
+	assert.Nil(t, err)
+	assert.InDeltaSlice(t, []float64{1, 2, 3, 4, 5, 6, 5, 4, 3, 2, 1}, scale(result["Sum"], 36), 1e-9)
+
+	result, _, err = net.SolveQuery(nil, []string{"Max"}, false)
+	assert.Nil(t, err)
+	assert.InDeltaSlice(t, []float64{1, 3, 5, 7, 9, 11}, scale(result["Max"], 36), 1e-9)
+
+	result, _, err = net.SolveQuery(map[string]string{"Die 1": "4"}, []string{"Ten or more"}, false)
+	assert.Nil(t, err)
+	assert.InDeltaSlice(t, []float64{1.0 / 6, 5.0 / 6}, result["Ten or more"], 1e-9)
+
+	header := `name: Test
+variables:
+- variable: A
+  outcomes: [low, high]
+  table: [[1, 1]]
+- variable: B
+  given: [A]
+`
+	tests := []struct {
+		yml string
+		err string
+	}{
+		{"  outcomes: [low, high]\n  function: median\n", "unknown ordinal function median"},
+		{"  outcomes: [low, high]\n  function: max\n  weights: [2]\n", "does not support weights"},
+		{"  outcomes: [low, high]\n  function: sum\n  threshold: 1\n", "requires ordinal function 'threshold'"},
+		{"  outcomes: [low, high]\n  weights: [2]\n  table: [[1, 0], [0, 1]]\n", "require an ordinal function"},
+		{"  outcomes: [low, high]\n  function: sum\n  logic: A\n", "can only have one of"},
+		{"  outcomes: [low, high]\n  function: sum\n  weights: [1, 2]\n", "got 2 weights for 1 parents"},
+		{"  outcomes: [low, mid, high]\n  function: threshold\n", "requires a child with 2 outcomes"},
+		{"  type: decision\n  outcomes: [low, high]\n  function: sum\n", "only supported for chance variables"},
+	}
+	for _, tt := range tests {
+		_, err = FromYAML([]byte(header + tt.yml))
+		var parseErr *ParseError
+		assert.ErrorAs(t, err, &parseErr, tt.yml)
+		assert.Equal(t, 6, parseErr.Line, tt.yml)
+		assert.Contains(t, err.Error(), tt.err)
+	}
+}
+
+func TestFromYAMLEquation(t *testing.T) {
+	net, err := FromFile("_examples/bbn/machine.yml")
+	assert.Nil(t, err)
+
+	result, _, err := net.SolveQuery(map[string]string{"Age": "10..20"}, []string{"Failure"}, false)
+	assert.Nil(t, err)
+	assert.InDeltaSlice(t, []float64{1 - math.Exp(-0.75), math.Exp(-0.75)}, result["Failure"], 1e-9)
+
+	result, _, err = net.SolveQuery(map[string]string{"Shifts": "2"}, []string{"Temperature"}, false)
+	assert.Nil(t, err)
+	assert.InDelta(t, 0.5, result["Temperature"][0]+result["Temperature"][1], 1e-9)
+
+	header := `name: Test
+variables:
+- variable: A
+  outcomes: ["1", "2"]
+  table: [[1, 1]]
+- variable: B
+  given: [A]
+`
+	tests := []struct {
+		yml string
+		err string
+	}{
+		{"  outcomes: [yes, no]\n  equation: 0.1 * (A\n", "expected ')'"},
+		{"  outcomes: [yes, no]\n  equation: 0.1 * C\n", "unknown variable C"},
+		{"  outcomes: [yes, no]\n  equation: A\n", "range [0, 1]"},
+		{"  outcomes: [yes, no]\n  equation: A / 2\n  table: [[1, 0], [0, 1]]\n", "can only have one of"},
+		{"  outcomes: [x, y, z]\n  equation: A / 2\n", "requires a child with 2 outcomes"},
+		{"  type: decision\n  outcomes: [yes, no]\n  equation: A / 2\n", "only supported for chance variables"},
+	}
+	for _, tt := range tests {
+		_, err = FromYAML([]byte(header + tt.yml))
+		var parseErr *ParseError
+		assert.ErrorAs(t, err, &parseErr, tt.yml)
+		assert.Equal(t, 6, parseErr.Line, tt.yml)
+		assert.Contains(t, err.Error(), tt.err)
+	}
+}
+
+func scale(values []float64, factor float64) []float64 {
+	result := make([]float64, len(values))
+	for i, v := range values {
+		result[i] = v * factor
+	}
+	return result
+}
+
+func TestFromYAMLLogicArgs(t *testing.T) {
+	err := logic.Register("test-at-least", func() logic.Factor { return logic.CountGreater(0) })
+	assert.Nil(t, err)
+
+	yml := `name: Test
+variables:
+- variable: A
+  outcomes: [yes, no]
+  table: [[1, 1]]
+- variable: B
+  outcomes: [yes, no]
+  table: [[1, 1]]
+- variable: None
+  given: [A, B]
+  outcomes: [yes, no]
+  logic: count-is 0
+- variable: Both
+  given: [A, B]
+  outcomes: [yes, no]
+  logic: count-is 2
+- variable: Any
+  given: [A, B]
+  outcomes: [yes, no]
+  logic: test-at-least 0
+`
+	net, err := FromYAML([]byte(yml))
+	assert.Nil(t, err)
+	assert.Equal(t, []float64{0, 1, 0, 1, 0, 1, 1, 0}, net.variables[2].Factor.Table)
+	assert.Equal(t, []float64{1, 0, 0, 1, 0, 1, 0, 1}, net.variables[3].Factor.Table)
+	assert.Equal(t, []float64{1, 0, 1, 0, 1, 0, 0, 1}, net.variables[4].Factor.Table)
+
+	_, err = FromYAML([]byte(`name: Test
+variables:
+- variable: A
+  outcomes: [yes, no]
+  table: [[1, 1]]
+- variable: B
+  given: [A]
+  outcomes: [yes, no]
+  logic: majority 2
+`))
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "unknown logic operator majority")
+}
+
+func TestFromYAMLUtility(t *testing.T) {
+	net, err := FromFile("_examples/decision/investment.yml")
+	assert.Nil(t, err)
+
+	policies, err := net.SolvePolicies(true)
+	assert.Nil(t, err)
+	assert.Equal(t, []float64{0, 1}, policies["Investment"].Table)
+
+	utility, err := net.SolveUtility(map[string]string{}, []string{}, "", false)
+	assert.Nil(t, err)
+	assert.InDelta(t, 100*(1-math.Exp(-0.4)), utility.Data()[0], 1e-9)
+
+	ce, ok := net.CertaintyEquivalent(utility.Data()[0])
+	assert.True(t, ok)
+	assert.InDelta(t, 40, ce, 1e-9)
+
+	// attribute utilities are still available
+	utility, err = net.SolveUtility(map[string]string{}, []string{}, "Return", false)
+	assert.Nil(t, err)
+	assert.InDelta(t, 40, utility.Data()[0], 1e-9)
+
+	stocks := 100 * (0.5*(1-math.Exp(-1.45)) + 0.5*(1-math.Exp(0.55)))
+	utility, err = net.SolveUtility(map[string]string{"Investment": "stocks"}, []string{}, "", true)
+	assert.Nil(t, err)
+	assert.InDelta(t, stocks, utility.Data()[0], 1e-9)
+
+	profiles, err := net.DecisionRiskProfiles(map[string]string{}, "Investment")
+	assert.Nil(t, err)
+	assert.InDelta(t, stocks, profiles[0].ExpectedUtility, 1e-9)
+	assert.InDelta(t, 45, profiles[0].Mean(), 1e-9)
+	assert.Equal(t, []float64{-55, 145}, profiles[0].Utilities)
+	assert.False(t, profiles[1].SecondOrderDominates(&profiles[0]))
+
+	yml, err := ToYAML(net)
+	assert.Nil(t, err)
+	assert.Contains(t, string(yml), "risk-tolerance: 100")
+	net2, err := FromYAML(yml)
+	assert.Nil(t, err)
+	policies, err = net2.SolvePolicies(true)
+	assert.Nil(t, err)
+	assert.Equal(t, []float64{0, 1}, policies["Investment"].Table)
+
+	header := `name: Test
+variables:
+- variable: A
+  type: decision
+  outcomes: [yes, no]
+- variable: U
+  type: utility
+  given: [A]
+  outcomes: [value]
+  table: [[1], [0]]
+- variable: T
+`
+	tests := []struct {
+		yml string
+		err string
+	}{
+		{"  type: utility\n  given: [U]\n  outcomes: [U]\n  utility: foo\n  table: [[1]]\n", "unknown utility function foo"},
+		{"  type: utility\n  given: [U]\n  outcomes: [U]\n  risk-tolerance: 10\n  table: [[1]]\n", "require a utility function"},
+		{"  type: utility\n  given: [U]\n  outcomes: [U]\n  utility: exponential\n  table: [[1]]\n", "must not be 0"},
+		{"  type: utility\n  given: [U]\n  outcomes: [U]\n  utility: piecewise\n  points: [[0, 0]]\n  table: [[1]]\n", "at least two points"},
+		{"  type: utility\n  given: [A]\n  outcomes: [value]\n  utility: multiplicative\n  scaling: 0.5\n  table: [[1], [0]]\n", "only supported for total utility"},
+	}
+	for _, tt := range tests {
+		_, err = FromYAML([]byte(header + tt.yml))
+		assert.NotNil(t, err, tt.yml)
+		if err != nil {
+			assert.Contains(t, err.Error(), tt.err, tt.yml)
+		}
+	}
+}